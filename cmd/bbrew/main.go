@@ -17,7 +17,12 @@ func main() {
 	// Custom usage message
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Bold Brew - A TUI for Homebrew package management\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: bbrew [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: bbrew [options]\n")
+		fmt.Fprintf(os.Stderr, "       bbrew <command>\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  news          List packages added, removed, deprecated or disabled in Homebrew\n")
+		fmt.Fprintf(os.Stderr, "  licenses      List installed packages with their license (--format csv|json)\n")
+		fmt.Fprintf(os.Stderr, "  sbom          Software bill of materials of installed packages (--format cyclonedx|spdx)\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		fmt.Fprintf(os.Stderr, "  -f <path|url> Path or URL to Brewfile\n")
 		fmt.Fprintf(os.Stderr, "  -v, --version Show version information\n")
@@ -26,6 +31,9 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  bbrew                    Launch the TUI with all packages\n")
 		fmt.Fprintf(os.Stderr, "  bbrew -f ~/Brewfile      Launch with packages from local Brewfile\n")
 		fmt.Fprintf(os.Stderr, "  bbrew -f https://...     Launch with packages from remote Brewfile\n")
+		fmt.Fprintf(os.Stderr, "  bbrew news               Show what changed in Homebrew recently\n")
+		fmt.Fprintf(os.Stderr, "  bbrew licenses --format json > licenses.json\n")
+		fmt.Fprintf(os.Stderr, "  bbrew sbom --format spdx > sbom.spdx.json\n")
 	}
 
 	flag.Parse()
@@ -36,6 +44,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle subcommands, which print to stdout instead of launching the TUI
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Arg(0), flag.Args()[1:]))
+	}
+
 	// Resolve Brewfile path (handles both local and remote URLs)
 	var cleanup func()
 	if *brewfilePath != "" {
@@ -56,7 +69,7 @@ func main() {
 		appService.SetBrewfilePath(*brewfilePath)
 	}
 
-	// Boot the application (load Homebrew data)
+	// Boot the application (load cached Homebrew data, fresh data is fetched in background)
 	if err := appService.Boot(); err != nil {
 		log.Fatalf("Failed to initialize: %v", err)
 	}
@@ -68,6 +81,42 @@ func main() {
 	}
 }
 
+// runCommand runs a CLI subcommand with its arguments and returns the process exit code.
+func runCommand(name string, args []string) int {
+	var err error
+	switch name {
+	case "news":
+		err = services.PrintNews(os.Stdout)
+	case "licenses":
+		fs := flag.NewFlagSet("licenses", flag.ContinueOnError)
+		format := fs.String("format", "csv", "Output format: csv or json")
+		if fs.Parse(args) != nil {
+			return 2
+		}
+		err = services.PrintLicenses(os.Stdout, *format)
+	case "sbom":
+		fs := flag.NewFlagSet("sbom", flag.ContinueOnError)
+		formatName := fs.String("format", "cyclonedx", "Output format: cyclonedx or spdx")
+		if fs.Parse(args) != nil {
+			return 2
+		}
+		var format services.SBOMFormat
+		if format, err = services.ParseSBOMFormat(*formatName); err == nil {
+			err = services.PrintSBOM(os.Stdout, format)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+		flag.Usage()
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
 // isFlagPassed checks if a flag was explicitly passed on the command line.
 func isFlagPassed(name string) bool {
 	found := false
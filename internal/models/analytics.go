@@ -0,0 +1,150 @@
+package models
+
+// AnalyticsPeriod is a Homebrew analytics time window.
+type AnalyticsPeriod int
+
+const (
+	Analytics30d AnalyticsPeriod = iota
+	Analytics90d
+	Analytics365d
+	analyticsPeriodCount
+)
+
+// AnalyticsPeriods lists all windows, shortest first.
+var AnalyticsPeriods = []AnalyticsPeriod{Analytics30d, Analytics90d, Analytics365d}
+
+// String returns the window as used in the analytics API URLs (e.g. "90d").
+func (p AnalyticsPeriod) String() string {
+	switch p {
+	case Analytics30d:
+		return "30d"
+	case Analytics365d:
+		return "365d"
+	default:
+		return "90d"
+	}
+}
+
+// Next cycles to the next window.
+func (p AnalyticsPeriod) Next() AnalyticsPeriod {
+	return (p + 1) % analyticsPeriodCount
+}
+
+// AnalyticsMetric is what an analytics count measures.
+type AnalyticsMetric int
+
+const (
+	MetricInstallOnRequest AnalyticsMetric = iota // Installs requested by the user (not as a dependency)
+	MetricInstall                                 // All installs, including as a dependency
+	MetricBuildError                              // Failed builds from source
+	analyticsMetricCount
+)
+
+// String returns a human-readable label for the metric.
+func (m AnalyticsMetric) String() string {
+	switch m {
+	case MetricInstall:
+		return "Installs"
+	case MetricBuildError:
+		return "Build Errors"
+	default:
+		return "Installs on Request"
+	}
+}
+
+// FormulaCategory returns the analytics API category for formulae.
+func (m AnalyticsMetric) FormulaCategory() string {
+	switch m {
+	case MetricInstall:
+		return "install"
+	case MetricBuildError:
+		return "build-error"
+	default:
+		return "install-on-request"
+	}
+}
+
+// CaskCategory returns the analytics API category for casks, or an empty string
+// when casks have no such metric. Casks are only counted on install, which is
+// always on request.
+func (m AnalyticsMetric) CaskCategory() string {
+	if m == MetricBuildError {
+		return ""
+	}
+	return "cask-install"
+}
+
+// Next cycles to the next metric.
+func (m AnalyticsMetric) Next() AnalyticsMetric {
+	return (m + 1) % analyticsMetricCount
+}
+
+// AnalyticsCount is the rank and count of a package in one analytics list.
+// Rank is 0 when the package does not appear in the list (or it was not loaded).
+type AnalyticsCount struct {
+	Rank  int
+	Count int
+}
+
+// PackageAnalytics holds a package's counts for every window of one metric.
+type PackageAnalytics [analyticsPeriodCount]AnalyticsCount
+
+// TrendPeriod is how far back trending compares analytics snapshots.
+type TrendPeriod int
+
+const (
+	TrendWeek TrendPeriod = iota
+	TrendMonth
+	trendPeriodCount
+)
+
+// String returns a human-readable label for the period.
+func (p TrendPeriod) String() string {
+	if p == TrendMonth {
+		return "month"
+	}
+	return "week"
+}
+
+// Days returns the length of the period in days.
+func (p TrendPeriod) Days() int {
+	if p == TrendMonth {
+		return 30
+	}
+	return 7
+}
+
+// Next cycles to the next period.
+func (p TrendPeriod) Next() TrendPeriod {
+	return (p + 1) % trendPeriodCount
+}
+
+// Trend compares a package's 90d popularity with an earlier analytics snapshot.
+// OldRank is 0 when the package was not ranked in the earlier snapshot.
+type Trend struct {
+	OldRank, NewRank   int
+	OldCount, NewCount int
+	OldListSize        int // Number of ranked packages in the earlier snapshot
+}
+
+// RankChange returns how many places the package climbed (negative when it fell).
+// Newly ranked packages climb from just below the end of the earlier list.
+func (t Trend) RankChange() int {
+	if t.NewRank == 0 {
+		return 0
+	}
+	oldRank := t.OldRank
+	if oldRank == 0 {
+		oldRank = t.OldListSize + 1
+	}
+	return oldRank - t.NewRank
+}
+
+// Score ranks trending packages: the relative rank improvement, so climbing from
+// #2000 to #500 counts more than from #20 to #10.
+func (t Trend) Score() float64 {
+	if t.NewRank == 0 {
+		return 0
+	}
+	return float64(t.NewRank+t.RankChange()) / float64(t.NewRank)
+}
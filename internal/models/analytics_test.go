@@ -0,0 +1,81 @@
+package models
+
+import "testing"
+
+func TestAnalyticsPeriod_StringAndNext(t *testing.T) {
+	tests := []struct {
+		period AnalyticsPeriod
+		want   string
+		next   AnalyticsPeriod
+	}{
+		{Analytics30d, "30d", Analytics90d},
+		{Analytics90d, "90d", Analytics365d},
+		{Analytics365d, "365d", Analytics30d},
+	}
+
+	for _, tt := range tests {
+		if got := tt.period.String(); got != tt.want {
+			t.Errorf("AnalyticsPeriod(%d).String() = %q, want %q", tt.period, got, tt.want)
+		}
+		if got := tt.period.Next(); got != tt.next {
+			t.Errorf("AnalyticsPeriod(%d).Next() = %d, want %d", tt.period, got, tt.next)
+		}
+	}
+}
+
+func TestAnalyticsMetric_Categories(t *testing.T) {
+	tests := []struct {
+		metric  AnalyticsMetric
+		formula string
+		cask    string
+		next    AnalyticsMetric
+	}{
+		{MetricInstallOnRequest, "install-on-request", "cask-install", MetricInstall},
+		{MetricInstall, "install", "cask-install", MetricBuildError},
+		{MetricBuildError, "build-error", "", MetricInstallOnRequest},
+	}
+
+	for _, tt := range tests {
+		if got := tt.metric.FormulaCategory(); got != tt.formula {
+			t.Errorf("%s.FormulaCategory() = %q, want %q", tt.metric, got, tt.formula)
+		}
+		if got := tt.metric.CaskCategory(); got != tt.cask {
+			t.Errorf("%s.CaskCategory() = %q, want %q", tt.metric, got, tt.cask)
+		}
+		if got := tt.metric.Next(); got != tt.next {
+			t.Errorf("%s.Next() = %s, want %s", tt.metric, got, tt.next)
+		}
+	}
+}
+
+func TestTrend_RankChangeAndScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		trend  Trend
+		change int
+		score  float64
+	}{
+		{"climbed", Trend{OldRank: 40, NewRank: 10, OldListSize: 100}, 30, 4},
+		{"fell", Trend{OldRank: 10, NewRank: 20, OldListSize: 100}, -10, 0.5},
+		{"new entry", Trend{NewRank: 50, OldListSize: 99}, 50, 2},
+		{"dropped out", Trend{OldRank: 5, OldListSize: 100}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.trend.RankChange(); got != tt.change {
+			t.Errorf("%s: RankChange() = %d, want %d", tt.name, got, tt.change)
+		}
+		if got := tt.trend.Score(); got != tt.score {
+			t.Errorf("%s: Score() = %v, want %v", tt.name, got, tt.score)
+		}
+	}
+}
+
+func TestTrendPeriod(t *testing.T) {
+	if TrendWeek.Days() != 7 || TrendMonth.Days() != 30 {
+		t.Error("unexpected trend period lengths")
+	}
+	if TrendWeek.Next() != TrendMonth || TrendMonth.Next() != TrendWeek {
+		t.Error("trend periods should cycle week -> month -> week")
+	}
+}
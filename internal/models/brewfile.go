@@ -7,6 +7,8 @@ type BrewfileEntry struct {
 	IsFlatpak bool
 	IsMas     bool
 	MasID     string // Mac App Store numeric ID
+
+	RestartService bool // restart_service: restart the formula's service after installing it
 }
 
 // BrewfileResult contains all parsed entries from a Brewfile
@@ -2,30 +2,46 @@ package models
 
 // Cask represents a Homebrew cask (GUI application).
 type Cask struct {
-	Token                 string             `json:"token"`
-	FullToken             string             `json:"full_token"`
-	OldTokens             []string           `json:"old_tokens"`
-	Tap                   string             `json:"tap"`
-	Name                  []string           `json:"name"`
-	Description           string             `json:"desc"`
-	Homepage              string             `json:"homepage"`
-	URL                   string             `json:"url"`
-	Version               string             `json:"version"`
-	Installed             *string            `json:"installed"`      // Null if not installed, version string if installed
-	InstalledTime         *int64             `json:"installed_time"` // Unix timestamp
-	Outdated              bool               `json:"outdated"`
-	AutoUpdates           bool               `json:"auto_updates"` // App updates itself; Homebrew's version lags behind
-	SHA256                string             `json:"sha256"`
-	Deprecated            bool               `json:"deprecated"`
-	DeprecationDate       interface{}        `json:"deprecation_date"`
-	DeprecationReason     interface{}        `json:"deprecation_reason"`
-	Disabled              bool               `json:"disabled"`
-	DisableDate           interface{}        `json:"disable_date"`
-	DisableReason         interface{}        `json:"disable_reason"`
-	TapGitHead            string             `json:"tap_git_head"`
-	RubySourcePath        string             `json:"ruby_source_path"`
-	RubySourceChecksum    RubySourceChecksum `json:"ruby_source_checksum"`
-	Analytics90dRank      int                // Internal: Populated from analytics
-	Analytics90dDownloads int                // Internal: Populated from analytics
-	LocallyInstalled      bool               `json:"-"` // Internal flag
+	Token                         string        `json:"token"`
+	FullToken                     string        `json:"full_token"`
+	OldTokens                     []string      `json:"old_tokens"`
+	Tap                           string        `json:"tap"`
+	Name                          []string      `json:"name"`
+	Description                   string        `json:"desc"`
+	Homepage                      string        `json:"homepage"`
+	URL                           string        `json:"url"`
+	Version                       string        `json:"version"`
+	Installed                     *string       `json:"installed"`      // Null if not installed, version string if installed
+	InstalledTime                 *int64        `json:"installed_time"` // Unix timestamp
+	Outdated                      bool          `json:"outdated"`
+	AutoUpdates                   bool          `json:"auto_updates"` // App updates itself; Homebrew's version lags behind
+	SHA256                        string        `json:"sha256"`
+	DependsOn                     CaskDependsOn `json:"depends_on"`
+	ConflictsWith                 CaskConflicts `json:"conflicts_with"`
+	Caveats                       string        `json:"caveats"`
+	Deprecated                    bool          `json:"deprecated"`
+	DeprecationDate               string        `json:"deprecation_date"`
+	DeprecationReason             string        `json:"deprecation_reason"`
+	DeprecationReplacementFormula string        `json:"deprecation_replacement_formula"`
+	DeprecationReplacementCask    string        `json:"deprecation_replacement_cask"`
+	Disabled                      bool          `json:"disabled"`
+	DisableDate                   string        `json:"disable_date"`
+	DisableReason                 string        `json:"disable_reason"`
+	DisableReplacementFormula     string        `json:"disable_replacement_formula"`
+	DisableReplacementCask        string        `json:"disable_replacement_cask"`
+	Analytics90dRank              int           // Internal: Populated from analytics
+	Analytics90dDownloads         int           // Internal: Populated from analytics
+	LocallyInstalled              bool          `json:"-"` // Internal flag
+}
+
+// CaskConflicts lists the casks and formulae that cannot be installed alongside a cask.
+type CaskConflicts struct {
+	Formula []string `json:"formula"`
+	Cask    []string `json:"cask"`
+}
+
+// CaskDependsOn lists the formulae and casks a cask requires.
+type CaskDependsOn struct {
+	Formula []string `json:"formula"`
+	Cask    []string `json:"cask"`
 }
@@ -0,0 +1,26 @@
+package models
+
+// DependencyNode is a package in a dependency tree, either a dependency of its
+// parent or, in a reverse tree, a package that depends on its parent.
+type DependencyNode struct {
+	Name      string
+	Type      PackageType
+	Installed bool
+	Missing   bool // Not in the catalogue (e.g. from an untapped tap)
+	Repeated  bool // Already expanded elsewhere in the tree; children are omitted
+	Children  []*DependencyNode
+}
+
+// Count returns the number of distinct packages below the node.
+func (n *DependencyNode) Count() int {
+	seen := make(map[string]bool)
+	var walk func(*DependencyNode)
+	walk = func(node *DependencyNode) {
+		for _, child := range node.Children {
+			seen[string(child.Type)+":"+child.Name] = true
+			walk(child)
+		}
+	}
+	walk(n)
+	return len(seen)
+}
@@ -0,0 +1,60 @@
+package models
+
+// Deprecation describes why Homebrew deprecated or disabled a package and what
+// replaces it, when maintainers named a replacement.
+type Deprecation struct {
+	Disabled        bool   // Disabled, not only deprecated
+	Reason          string // e.g. "does_not_build" or "unmaintained"
+	Date            string // When the package was deprecated or disabled
+	DisableDate     string // When it is (or was) disabled; deprecations often schedule one
+	Replacement     string // Name of the replacement, empty when there is none
+	ReplacementType PackageType
+}
+
+// Deprecation returns the deprecation of a deprecated or disabled formula or cask.
+func (p *Package) Deprecation() (Deprecation, bool) {
+	if !p.Deprecated && !p.Disabled {
+		return Deprecation{}, false
+	}
+	d := Deprecation{Disabled: p.Disabled}
+	var formula, cask string
+	switch {
+	case p.Formula != nil:
+		f := p.Formula
+		d.DisableDate = f.DisableDate
+		if p.Disabled {
+			d.Reason, d.Date = f.DisableReason, f.DisableDate
+			formula, cask = firstNonEmpty(f.DisableReplacementFormula, f.DisableReplacement), f.DisableReplacementCask
+		} else {
+			d.Reason, d.Date = f.DeprecationReason, f.DeprecationDate
+			formula, cask = firstNonEmpty(f.DeprecationReplacementFormula, f.DeprecationReplacement), f.DeprecationReplacementCask
+		}
+	case p.Cask != nil:
+		c := p.Cask
+		d.DisableDate = c.DisableDate
+		if p.Disabled {
+			d.Reason, d.Date = c.DisableReason, c.DisableDate
+			formula, cask = c.DisableReplacementFormula, c.DisableReplacementCask
+		} else {
+			d.Reason, d.Date = c.DeprecationReason, c.DeprecationDate
+			formula, cask = c.DeprecationReplacementFormula, c.DeprecationReplacementCask
+		}
+	}
+
+	switch {
+	case formula != "":
+		d.Replacement, d.ReplacementType = formula, PackageTypeFormula
+	case cask != "":
+		d.Replacement, d.ReplacementType = cask, PackageTypeCask
+	}
+	return d, true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
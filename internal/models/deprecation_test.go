@@ -0,0 +1,39 @@
+package models
+
+import "testing"
+
+func TestPackageDeprecation(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      Package
+		wantOK   bool
+		wantName string
+		wantType PackageType
+		wantDate string
+	}{
+		{"not deprecated", Package{Formula: &Formula{}}, false, "", "", ""},
+		{
+			"legacy formula replacement",
+			Package{Deprecated: true, Formula: &Formula{DeprecationReplacement: "wget2", DisableDate: "2026-04-01"}},
+			true, "wget2", PackageTypeFormula, "2026-04-01",
+		},
+		{
+			"disabled formula replaced by a cask",
+			Package{Disabled: true, Formula: &Formula{DisableReplacementCask: "docker-desktop", DisableDate: "2025-02-01", DeprecationReplacement: "ignored"}},
+			true, "docker-desktop", PackageTypeCask, "2025-02-01",
+		},
+		{
+			"cask replaced by a formula",
+			Package{Deprecated: true, Cask: &Cask{DeprecationReplacementFormula: "ffmpeg"}},
+			true, "ffmpeg", PackageTypeFormula, "",
+		},
+		{"no replacement", Package{Deprecated: true, Cask: &Cask{DeprecationReason: "discontinued"}}, true, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		d, ok := tt.pkg.Deprecation()
+		if ok != tt.wantOK || d.Replacement != tt.wantName || d.ReplacementType != tt.wantType || d.DisableDate != tt.wantDate {
+			t.Errorf("%s: Deprecation() = %+v, %v", tt.name, d, ok)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "fmt"
+
+// KegSize is the disk usage of one installed version of a package.
+type KegSize struct {
+	Version string
+	Bytes   int64
+}
+
+// DiskUsage is the disk usage of an installed package, in the Cellar for formulae
+// or the Caskroom for casks. It is zero until the installation has been measured.
+type DiskUsage struct {
+	Bytes    int64
+	Versions []KegSize // One entry per installed version, sorted by version
+}
+
+// FormatSize formats a byte count for display, e.g. "12.3 MB".
+func FormatSize(bytes int64) string {
+	const unit = 1000
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exp := float64(bytes)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "kMGTP"[exp])
+}
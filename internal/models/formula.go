@@ -1,62 +1,96 @@
 package models
 
+import "strings"
+
+// Formula represents a Homebrew formula.
+// Only the fields used by the UI and package operations are decoded: the
+// catalogue holds ~7k formulae, so untyped or unused API fields are left out.
 type Formula struct {
-	Name                    string             `json:"name"`
-	FullName                string             `json:"full_name"`
-	Tap                     string             `json:"tap"`
-	OldNames                []string           `json:"oldnames"`
-	Aliases                 []string           `json:"aliases"`
-	VersionedFormulae       []string           `json:"versioned_formulae"`
-	Description             string             `json:"desc"`
-	License                 string             `json:"license"`
-	Homepage                string             `json:"homepage"`
-	Versions                Versions           `json:"versions"`
-	Urls                    Urls               `json:"urls"`
-	Revision                int                `json:"revision"`
-	VersionScheme           int                `json:"version_scheme"`
-	Bottle                  Bottle             `json:"bottle"`
-	PourBottleOnlyIf        interface{}        `json:"pour_bottle_only_if"`
-	KegOnly                 bool               `json:"keg_only"`
-	KegOnlyReason           interface{}        `json:"keg_only_reason"`
-	Options                 []interface{}      `json:"options"`
-	BuildDependencies       []string           `json:"build_dependencies"`
-	Dependencies            []string           `json:"dependencies"`
-	TestDependencies        []interface{}      `json:"test_dependencies"`
-	RecommendedDependencies []interface{}      `json:"recommended_dependencies"`
-	OptionalDependencies    []interface{}      `json:"optional_dependencies"`
-	Requirements            []interface{}      `json:"requirements"`
-	ConflictsWith           []interface{}      `json:"conflicts_with"`
-	ConflictsWithReasons    []interface{}      `json:"conflicts_with_reasons"`
-	LinkOverwrite           []interface{}      `json:"link_overwrite"`
-	Caveats                 interface{}        `json:"caveats"`
-	Installed               []Installed        `json:"installed"`
-	LinkedKeg               string             `json:"linked_keg"`
-	Pinned                  bool               `json:"pinned"`
-	Outdated                bool               `json:"outdated"`
-	Deprecated              bool               `json:"deprecated"`
-	DeprecationDate         interface{}        `json:"deprecation_date"`
-	DeprecationReason       interface{}        `json:"deprecation_reason"`
-	DeprecationReplacement  interface{}        `json:"deprecation_replacement"`
-	Disabled                bool               `json:"disabled"`
-	DisableDate             interface{}        `json:"disable_date"`
-	DisableReason           interface{}        `json:"disable_reason"`
-	DisableReplacement      interface{}        `json:"disable_replacement"`
-	PostInstallDefined      bool               `json:"post_install_defined"`
-	Service                 interface{}        `json:"service"`
-	TapGitHead              string             `json:"tap_git_head"`
-	RubySourcePath          string             `json:"ruby_source_path"`
-	RubySourceChecksum      RubySourceChecksum `json:"ruby_source_checksum"`
-	Analytics90dRank        int
-	Analytics90dDownloads   int
-	LocallyInstalled        bool   `json:"-"` // Internal flag to indicate if the formula is installed locally [internal use]
-	LocalPath               string `json:"-"` // Internal path to the formula in the local Homebrew Cellar [internal use]
+	Name                          string        `json:"name"`
+	FullName                      string        `json:"full_name"`
+	Tap                           string        `json:"tap"`
+	OldNames                      []string      `json:"oldnames"`
+	Aliases                       []string      `json:"aliases"`
+	VersionedFormulae             []string      `json:"versioned_formulae"`
+	Description                   string        `json:"desc"`
+	License                       string        `json:"license"`
+	Homepage                      string        `json:"homepage"`
+	Versions                      Versions      `json:"versions"`
+	Urls                          Urls          `json:"urls"`
+	Revision                      int           `json:"revision"`
+	Bottle                        Bottle        `json:"bottle"`
+	KegOnly                       bool          `json:"keg_only"`
+	KegOnlyReason                 KegOnlyReason `json:"keg_only_reason"`
+	BuildDependencies             []string      `json:"build_dependencies"`
+	Dependencies                  []string      `json:"dependencies"`
+	ConflictsWith                 []string      `json:"conflicts_with"`
+	ConflictsWithReasons          []string      `json:"conflicts_with_reasons"`
+	Caveats                       string        `json:"caveats"`
+	Installed                     []Installed   `json:"installed"`
+	LinkedKeg                     string        `json:"linked_keg"`
+	Pinned                        bool          `json:"pinned"`
+	Outdated                      bool          `json:"outdated"`
+	Deprecated                    bool          `json:"deprecated"`
+	DeprecationDate               string        `json:"deprecation_date"`
+	DeprecationReason             string        `json:"deprecation_reason"`
+	DeprecationReplacement        string        `json:"deprecation_replacement"` // Formula name, written by older Homebrew versions
+	DeprecationReplacementFormula string        `json:"deprecation_replacement_formula"`
+	DeprecationReplacementCask    string        `json:"deprecation_replacement_cask"`
+	Disabled                      bool          `json:"disabled"`
+	DisableDate                   string        `json:"disable_date"`
+	DisableReason                 string        `json:"disable_reason"`
+	DisableReplacement            string        `json:"disable_replacement"` // Formula name, written by older Homebrew versions
+	DisableReplacementFormula     string        `json:"disable_replacement_formula"`
+	DisableReplacementCask        string        `json:"disable_replacement_cask"`
+	PostInstallDefined            bool          `json:"post_install_defined"`
+	Service                       *Service      `json:"service"` // nil if the formula defines no service
+	Analytics90dRank              int
+	Analytics90dDownloads         int
+	LocallyInstalled              bool   `json:"-"` // Internal flag to indicate if the formula is installed locally [internal use]
+	LocalPath                     string `json:"-"` // Internal path to the formula in the local Homebrew Cellar [internal use]
+}
+
+// Linked reports whether a version of the formula is symlinked into the Homebrew prefix.
+func (f *Formula) Linked() bool {
+	return f.LinkedKeg != ""
+}
+
+// KegOnlyReason explains why a formula is not linked into the Homebrew prefix.
+type KegOnlyReason struct {
+	Reason      string `json:"reason"`
+	Explanation string `json:"explanation"`
+}
+
+// kegOnlyReasons describes the symbolic reasons used by Homebrew formulae.
+var kegOnlyReasons = map[string]string{
+	":provided_by_macos":   "macOS already provides this software",
+	":shadowed_by_macos":   "macOS provides similar software",
+	":versioned_formula":   "this is an alternate version of another formula",
+	":provided_pre_mojave": "macOS provides this software before Mojave",
+}
+
+// String returns a readable reason, preferring the formula's own explanation.
+func (r KegOnlyReason) String() string {
+	if r.Explanation != "" {
+		return r.Explanation
+	}
+	if text, ok := kegOnlyReasons[r.Reason]; ok {
+		return text
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(r.Reason, ":"), "_", " ")
+}
+
+// Service describes the background service a formula can run via `brew services`.
+type Service struct {
+	RunType    string `json:"run_type"`
+	WorkingDir string `json:"working_dir"`
 }
 
 type Analytics struct {
 	Category   string          `json:"category"`
 	TotalItems int             `json:"total_items"`
-	StartDate  interface{}     `json:"start_date"`
-	EndDate    interface{}     `json:"end_date"`
+	StartDate  string          `json:"start_date"`
+	EndDate    string          `json:"end_date"`
 	TotalCount int             `json:"total_count"`
 	Items      []AnalyticsItem `json:"items"`
 }
@@ -81,12 +115,12 @@ type Urls struct {
 }
 
 type URL struct {
-	URL      string      `json:"url"`
-	Tag      interface{} `json:"tag"`
-	Revision interface{} `json:"revision"`
-	Using    interface{} `json:"using"`
-	Checksum string      `json:"checksum"`
-	Branch   string      `json:"branch"`
+	URL      string `json:"url"`
+	Tag      string `json:"tag"`
+	Revision string `json:"revision"`
+	Using    string `json:"using"`
+	Checksum string `json:"checksum"`
+	Branch   string `json:"branch"`
 }
 
 type Bottle struct {
@@ -105,9 +139,21 @@ type BottleFile struct {
 	Sha256 string `json:"sha256"`
 }
 
+// BottleStatus tells whether a formula can be poured from a bottle on the running platform.
+type BottleStatus struct {
+	Platform string // Bottle tag of the running platform, e.g. arm64_sonoma; empty if unknown
+	Tag      string // Tag of the bottle brew would pour; empty when it builds from source
+	Size     int64  // Download size in bytes, 0 until known
+}
+
+// Available reports whether a bottle can be poured, so no compilation is needed.
+func (b BottleStatus) Available() bool {
+	return b.Tag != ""
+}
+
 type Installed struct {
 	Version               string              `json:"version"`
-	UsedOptions           []interface{}       `json:"used_options"`
+	UsedOptions           []string            `json:"used_options"`
 	BuiltAsBottle         bool                `json:"built_as_bottle"`
 	PouredFromBottle      bool                `json:"poured_from_bottle"`
 	Time                  int64               `json:"time"`
@@ -123,7 +169,3 @@ type RuntimeDependency struct {
 	PkgVersion       string `json:"pkg_version"`
 	DeclaredDirectly bool   `json:"declared_directly"`
 }
-
-type RubySourceChecksum struct {
-	Sha256 string `json:"sha256"`
-}
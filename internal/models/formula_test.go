@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestFormulaLinked(t *testing.T) {
+	if !(&Formula{Name: "python@3.13", LinkedKeg: "3.13.1"}).Linked() {
+		t.Error("Linked() = false, want true when linked_keg is set")
+	}
+	if (&Formula{Name: "python@3.12"}).Linked() {
+		t.Error("Linked() = true, want false without linked_keg")
+	}
+}
+
+func TestKegOnlyReasonString(t *testing.T) {
+	tests := []struct {
+		reason KegOnlyReason
+		want   string
+	}{
+		{KegOnlyReason{Reason: ":versioned_formula"}, "this is an alternate version of another formula"},
+		{KegOnlyReason{Reason: ":provided_by_macos", Explanation: "macOS ships curl"}, "macOS ships curl"},
+		{KegOnlyReason{Reason: ":some_new_reason"}, "some new reason"},
+		{KegOnlyReason{Reason: "it conflicts with the system OpenSSL"}, "it conflicts with the system OpenSSL"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.reason.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.reason, got, tt.want)
+		}
+	}
+}
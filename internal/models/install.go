@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstallOptions are the brew install flags chosen for a package in the install dialog.
+type InstallOptions struct {
+	HEAD            bool   `json:"head,omitempty"`              // Formula: install the development version (--HEAD)
+	BuildFromSource bool   `json:"build_from_source,omitempty"` // Formula: compile instead of pouring a bottle
+	NoQuarantine    bool   `json:"no_quarantine,omitempty"`     // Cask: skip the Gatekeeper quarantine attribute
+	Force           bool   `json:"force,omitempty"`             // Cask: overwrite an existing app
+	AppDir          string `json:"appdir,omitempty"`            // Cask: install the app into this directory
+}
+
+// IsZero reports whether no option is set, i.e. a plain brew install.
+func (o InstallOptions) IsZero() bool {
+	return o == InstallOptions{}
+}
+
+// Flags returns the brew install flags for a package of the given type.
+func (o InstallOptions) Flags(pkgType PackageType) []string {
+	var flags []string
+	if pkgType == PackageTypeCask {
+		if o.NoQuarantine {
+			flags = append(flags, "--no-quarantine")
+		}
+		if o.Force {
+			flags = append(flags, "--force")
+		}
+		if o.AppDir != "" {
+			flags = append(flags, "--appdir="+o.AppDir)
+		}
+		return flags
+	}
+	if o.HEAD {
+		flags = append(flags, "--HEAD")
+	}
+	if o.BuildFromSource {
+		flags = append(flags, "--build-from-source")
+	}
+	return flags
+}
+
+// BrewfileArgs returns the args: option of a Brewfile entry, as read by brew bundle,
+// or an empty string when no option applies.
+// Formulae take a list (args: ["HEAD"]), casks a hash (args: { appdir: "~/Applications" }).
+func (o InstallOptions) BrewfileArgs(pkgType PackageType) string {
+	var args []string
+	if pkgType == PackageTypeCask {
+		if o.AppDir != "" {
+			args = append(args, fmt.Sprintf("appdir: %q", o.AppDir))
+		}
+		if o.NoQuarantine {
+			args = append(args, "no_quarantine: true")
+		}
+		if o.Force {
+			args = append(args, "force: true")
+		}
+		if len(args) == 0 {
+			return ""
+		}
+		return "args: { " + strings.Join(args, ", ") + " }"
+	}
+
+	if o.HEAD {
+		args = append(args, `"HEAD"`)
+	}
+	if o.BuildFromSource {
+		args = append(args, `"build-from-source"`)
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return "args: [" + strings.Join(args, ", ") + "]"
+}
+
+// VersionSwitch describes a move from one version of a formula to another,
+// e.g. python@3.11 to python@3.12.
+type VersionSwitch struct {
+	From, To       Package
+	MigrateService bool // Stop the service of From and start the one of To
+	RemoveOld      bool // Uninstall From once To is linked
+}
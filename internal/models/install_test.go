@@ -0,0 +1,40 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInstallOptionsFlags(t *testing.T) {
+	opts := InstallOptions{HEAD: true, BuildFromSource: true, NoQuarantine: true, Force: true, AppDir: "~/Applications"}
+
+	if got, want := opts.Flags(PackageTypeFormula), []string{"--HEAD", "--build-from-source"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("formula Flags() = %v, want %v", got, want)
+	}
+	if got, want := opts.Flags(PackageTypeCask), []string{"--no-quarantine", "--force", "--appdir=~/Applications"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cask Flags() = %v, want %v", got, want)
+	}
+	if got := (InstallOptions{}).Flags(PackageTypeFormula); len(got) != 0 {
+		t.Errorf("zero options Flags() = %v, want none", got)
+	}
+}
+
+func TestInstallOptionsBrewfileArgs(t *testing.T) {
+	tests := []struct {
+		opts    InstallOptions
+		pkgType PackageType
+		want    string
+	}{
+		{InstallOptions{HEAD: true}, PackageTypeFormula, `args: ["HEAD"]`},
+		{InstallOptions{HEAD: true, BuildFromSource: true}, PackageTypeFormula, `args: ["HEAD", "build-from-source"]`},
+		{InstallOptions{AppDir: "~/Applications", Force: true}, PackageTypeCask, `args: { appdir: "~/Applications", force: true }`},
+		{InstallOptions{NoQuarantine: true}, PackageTypeFormula, ""}, // cask-only option
+		{InstallOptions{}, PackageTypeCask, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.opts.BrewfileArgs(tt.pkgType); got != tt.want {
+			t.Errorf("%+v.BrewfileArgs(%s) = %q, want %q", tt.opts, tt.pkgType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// JobAction is the package operation a background job runs.
+type JobAction string
+
+const (
+	JobInstall JobAction = "install"
+	JobRemove  JobAction = "remove"
+	JobUpgrade JobAction = "upgrade"
+)
+
+// Verb returns the progressive form of the action, e.g. "Installing".
+func (a JobAction) Verb() string {
+	switch a {
+	case JobInstall:
+		return "Installing"
+	case JobRemove:
+		return "Removing"
+	default:
+		return "Upgrading"
+	}
+}
+
+// JobStatus is the state of a background job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a package operation queued to run in the background.
+type Job struct {
+	ID       int
+	Action   JobAction
+	Package  Package
+	Status   JobStatus
+	Error    string // Why the job failed
+	Started  time.Time
+	Finished time.Time
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j Job) Done() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+// Duration returns how long the job ran, or has been running so far.
+func (j Job) Duration() time.Duration {
+	switch {
+	case j.Started.IsZero():
+		return 0
+	case j.Finished.IsZero():
+		return time.Since(j.Started)
+	default:
+		return j.Finished.Sub(j.Started)
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// NewsKind is what changed about a package between two catalogue refreshes.
+type NewsKind string
+
+const (
+	NewsAdded      NewsKind = "added"
+	NewsRemoved    NewsKind = "removed"
+	NewsDeprecated NewsKind = "deprecated"
+	NewsDisabled   NewsKind = "disabled"
+)
+
+// NewsItem is one change found when the Homebrew catalogue was refreshed.
+type NewsItem struct {
+	Kind        NewsKind    `json:"kind"`
+	Type        PackageType `json:"type"`
+	Name        string      `json:"name"`
+	Description string      `json:"desc,omitempty"`
+	Time        time.Time   `json:"time"` // When the change was noticed
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // PackageType distinguishes between formulae and casks.
 type PackageType string
 
@@ -13,16 +15,21 @@ const (
 // Package represents a unified view of both Formula and Cask for UI display.
 type Package struct {
 	// Common fields
-	Name                  string      // Formula.Name or Cask.Token
-	DisplayName           string      // Formula.FullName or Cask.Name[0]
-	Description           string      // desc
-	Homepage              string      // homepage
-	Version               string      // versions.stable or version
-	LocallyInstalled      bool        // Is installed locally
-	Outdated              bool        // Needs update
-	Type                  PackageType // formula or cask
-	Analytics90dRank      int
-	Analytics90dDownloads int
+	Name                  string           // Formula.Name or Cask.Token
+	DisplayName           string           // Formula.FullName or Cask.Name[0]
+	Description           string           // desc
+	Homepage              string           // homepage
+	Version               string           // versions.stable or version
+	LocallyInstalled      bool             // Is installed locally
+	Outdated              bool             // Needs update
+	Pinned                bool             // Formula pinned with brew pin, never upgraded
+	Type                  PackageType      // formula or cask
+	Analytics90dRank      int              // 90d install-on-request rank, the stable popularity signal
+	Analytics90dDownloads int              // 90d install-on-request count
+	Analytics             PackageAnalytics // Every window of the selected analytics metric
+	DiskUsage             DiskUsage        // Installed size, zero until measured
+	Caveats               string           // Post-install notes from the formula or cask
+	Bottle                BottleStatus     // Formulae: bottle for the running platform
 
 	// Health status
 	Deprecated bool // Marked as deprecated by Homebrew maintainers
@@ -45,6 +52,21 @@ func (p *Package) Label() string {
 	return p.Name
 }
 
+// InstalledTime returns when the package was installed as a Unix timestamp, using
+// the most recent installed version of a formula. It is 0 when unknown.
+func (p *Package) InstalledTime() int64 {
+	var latest int64
+	switch {
+	case p.Formula != nil:
+		for _, installed := range p.Formula.Installed {
+			latest = max(latest, installed.Time)
+		}
+	case p.Cask != nil && p.Cask.InstalledTime != nil:
+		latest = *p.Cask.InstalledTime
+	}
+	return latest
+}
+
 // NewPackageFromFormula creates a Package from a Formula.
 func NewPackageFromFormula(f *Formula) Package {
 	installedOnRequest := false
@@ -60,6 +82,8 @@ func NewPackageFromFormula(f *Formula) Package {
 		Version:               f.Versions.Stable,
 		LocallyInstalled:      f.LocallyInstalled,
 		Outdated:              f.Outdated,
+		Pinned:                f.Pinned,
+		Caveats:               strings.TrimSpace(f.Caveats),
 		Type:                  PackageTypeFormula,
 		Analytics90dRank:      f.Analytics90dRank,
 		Analytics90dDownloads: f.Analytics90dDownloads,
@@ -97,6 +121,7 @@ func NewPackageFromCask(c *Cask) Package {
 		Version:               c.Version,
 		LocallyInstalled:      c.LocallyInstalled,
 		Outdated:              outdated,
+		Caveats:               strings.TrimSpace(c.Caveats),
 		Type:                  PackageTypeCask,
 		Analytics90dRank:      c.Analytics90dRank,
 		Analytics90dDownloads: c.Analytics90dDownloads,
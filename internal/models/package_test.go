@@ -14,6 +14,7 @@ func TestNewPackageFromFormula(t *testing.T) {
 		},
 		LocallyInstalled:      true,
 		Outdated:              false,
+		Pinned:                true,
 		Analytics90dRank:      5,
 		Analytics90dDownloads: 100000,
 	}
@@ -35,6 +36,9 @@ func TestNewPackageFromFormula(t *testing.T) {
 	if !pkg.InstalledOnRequest {
 		t.Error("InstalledOnRequest = false, want true")
 	}
+	if !pkg.Pinned {
+		t.Error("Pinned = false, want true")
+	}
 	if pkg.Formula != f {
 		t.Error("Formula pointer not preserved")
 	}
@@ -82,12 +86,17 @@ func TestNewPackageFromCask(t *testing.T) {
 		Version:               "120.0",
 		LocallyInstalled:      true,
 		Outdated:              true,
+		Caveats:               "Restart Firefox after updating.\n",
 		Analytics90dRank:      1,
 		Analytics90dDownloads: 500000,
 	}
 
 	pkg := NewPackageFromCask(c)
 
+	if pkg.Caveats != "Restart Firefox after updating." {
+		t.Errorf("Caveats = %q, want trimmed cask caveats", pkg.Caveats)
+	}
+
 	if pkg.Name != "firefox" {
 		t.Errorf("Name = %q, want %q", pkg.Name, "firefox")
 	}
@@ -254,3 +263,21 @@ func TestPackageLabel_CaskUsesName(t *testing.T) {
 		t.Errorf("Label() = %q, want %q", got, "firefox")
 	}
 }
+
+func TestPackage_InstalledTime(t *testing.T) {
+	formula := NewPackageFromFormula(&Formula{Name: "python@3.13", Installed: []Installed{{Time: 100}, {Time: 300}, {Time: 200}}})
+	if got := formula.InstalledTime(); got != 300 {
+		t.Errorf("formula InstalledTime() = %d, want the latest install (300)", got)
+	}
+
+	installed := int64(42)
+	cask := NewPackageFromCask(&Cask{Token: "firefox", InstalledTime: &installed})
+	if got := cask.InstalledTime(); got != 42 {
+		t.Errorf("cask InstalledTime() = %d, want 42", got)
+	}
+
+	notInstalled := NewPackageFromCask(&Cask{Token: "zed"})
+	if got := notInstalled.InstalledTime(); got != 0 {
+		t.Errorf("not installed InstalledTime() = %d, want 0", got)
+	}
+}
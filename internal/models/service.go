@@ -0,0 +1,17 @@
+package models
+
+// ServiceStatus is the state of a formula's background service, as reported by
+// `brew services info --json`.
+type ServiceStatus struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"` // started, stopped, scheduled, error, none, ...
+	Running  bool   `json:"running"`
+	Loaded   bool   `json:"loaded"`
+	User     string `json:"user"` // Empty when not loaded
+	PID      int    `json:"pid"`
+	ExitCode int    `json:"exit_code"`
+	File     string `json:"file"` // launchd plist or systemd unit
+}
+
+// ServiceActions lists the `brew services` subcommands that can be applied to a service.
+var ServiceActions = []string{"start", "stop", "restart", "run"}
@@ -4,9 +4,15 @@ package models
 type SortMode int
 
 const (
-	SortNone        SortMode = iota // No explicit sort (preserves natural/API order)
-	SortByDownloads                 // Most downloaded first
-	SortByName                      // Alphabetical A-Z
+	SortNone            SortMode = iota // No explicit sort (preserves natural/API order)
+	SortByDownloads                     // Most downloaded first
+	SortByName                          // Alphabetical A-Z
+	SortBySize                          // Largest installed size first
+	SortByInstalledDate                 // Most recently installed first
+	SortByOutdated                      // Outdated first, then installed, then the rest
+	SortByType                          // Formulae, casks, Flatpak, then Mac App Store apps
+	SortByDescription                   // Alphabetical by description
+	sortModeCount
 )
 
 func (s SortMode) String() string {
@@ -15,6 +21,16 @@ func (s SortMode) String() string {
 		return "Downloads"
 	case SortByName:
 		return "Name"
+	case SortBySize:
+		return "Size"
+	case SortByInstalledDate:
+		return "Installed Date"
+	case SortByOutdated:
+		return "Outdated"
+	case SortByType:
+		return "Type"
+	case SortByDescription:
+		return "Description"
 	default:
 		return "None"
 	}
@@ -22,5 +38,27 @@ func (s SortMode) String() string {
 
 // Next cycles to the next sort mode.
 func (s SortMode) Next() SortMode {
-	return (s + 1) % 3
+	return (s + 1) % sortModeCount
+}
+
+// Descending reports whether the mode naturally puts the largest values first
+// (most downloads, biggest size, newest install).
+func (s SortMode) Descending() bool {
+	switch s {
+	case SortByDownloads, SortBySize, SortByInstalledDate, SortByOutdated:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortKey is one level of a multi-key sort.
+type SortKey struct {
+	Mode    SortMode
+	Reverse bool // Flip the mode's natural direction
+}
+
+// Descending reports the effective direction of the key.
+func (k SortKey) Descending() bool {
+	return k.Mode.Descending() != k.Reverse
 }
@@ -10,6 +10,11 @@ func TestSortMode_String(t *testing.T) {
 		{SortNone, "None"},
 		{SortByDownloads, "Downloads"},
 		{SortByName, "Name"},
+		{SortBySize, "Size"},
+		{SortByInstalledDate, "Installed Date"},
+		{SortByOutdated, "Outdated"},
+		{SortByType, "Type"},
+		{SortByDescription, "Description"},
 	}
 
 	for _, tt := range tests {
@@ -26,7 +31,9 @@ func TestSortMode_Next(t *testing.T) {
 	}{
 		{SortNone, SortByDownloads},
 		{SortByDownloads, SortByName},
-		{SortByName, SortNone},
+		{SortByName, SortBySize},
+		{SortBySize, SortByInstalledDate},
+		{SortByDescription, SortNone},
 	}
 
 	for _, tt := range tests {
@@ -35,3 +42,22 @@ func TestSortMode_Next(t *testing.T) {
 		}
 	}
 }
+
+func TestSortKey_Descending(t *testing.T) {
+	tests := []struct {
+		key  SortKey
+		want bool
+	}{
+		{SortKey{Mode: SortByDownloads}, true},
+		{SortKey{Mode: SortByDownloads, Reverse: true}, false},
+		{SortKey{Mode: SortByName}, false},
+		{SortKey{Mode: SortByName, Reverse: true}, true},
+		{SortKey{Mode: SortByInstalledDate}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.key.Descending(); got != tt.want {
+			t.Errorf("%+v.Descending() = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
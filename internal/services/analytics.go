@@ -0,0 +1,220 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"bbrew/internal/models"
+)
+
+// analyticsAPIURLFormat is the Homebrew analytics endpoint for a category and window.
+const analyticsAPIURLFormat = "https://formulae.brew.sh/api/analytics/%s/%s.json"
+
+// analyticsList identifies one list of the Homebrew analytics API.
+type analyticsList struct {
+	category string // e.g. "install-on-request", "build-error", "cask-install"
+	period   models.AnalyticsPeriod
+}
+
+// The 90d install-on-request lists are always loaded: they are the popularity signal
+// used for relevance ranking and the downloads: query, whatever metric is displayed.
+var (
+	popularityFormulaList = analyticsList{models.MetricInstallOnRequest.FormulaCategory(), models.Analytics90d}
+	popularityCaskList    = analyticsList{models.MetricInstallOnRequest.CaskCategory(), models.Analytics90d}
+)
+
+func (l analyticsList) url() string {
+	return fmt.Sprintf(analyticsAPIURLFormat, l.category, l.period)
+}
+
+func (l analyticsList) cacheFile() string {
+	return fmt.Sprintf("analytics-%s-%s.json", l.category, l.period)
+}
+
+// metricLists returns the lists needed to show a metric: every window for formulae
+// and casks, plus the popularity lists.
+func metricLists(metric models.AnalyticsMetric) []analyticsList {
+	lists := []analyticsList{popularityFormulaList, popularityCaskList}
+	for _, category := range []string{metric.FormulaCategory(), metric.CaskCategory()} {
+		if category == "" {
+			continue
+		}
+		for _, period := range models.AnalyticsPeriods {
+			if list := (analyticsList{category, period}); list != popularityFormulaList && list != popularityCaskList {
+				lists = append(lists, list)
+			}
+		}
+	}
+	return lists
+}
+
+// parseAnalytics decodes an analytics list into items keyed by formula or cask name,
+// along with the last day the list covers.
+func parseAnalytics(data []byte) (map[string]models.AnalyticsItem, string, error) {
+	analytics := models.Analytics{}
+	if err := json.Unmarshal(data, &analytics); err != nil {
+		return nil, "", err
+	}
+
+	result := make(map[string]models.AnalyticsItem, len(analytics.Items))
+	for _, item := range analytics.Items {
+		switch {
+		case item.Formula != "":
+			result[item.Formula] = item
+		case item.Cask != "":
+			result[item.Cask] = item
+		}
+	}
+	return result, analytics.EndDate, nil
+}
+
+// GetAnalytics retrieves one analytics list from the API, optionally using cache.
+func (d *DataProvider) GetAnalytics(list analyticsList, forceRefresh bool) (map[string]models.AnalyticsItem, error) {
+	if err := ensureCacheDir(); err != nil {
+		return nil, err
+	}
+
+	if !forceRefresh {
+		if data := readCacheFile(list.cacheFile(), 100); data != nil {
+			if result, _, err := parseAnalytics(data); err == nil && len(result) > 0 {
+				return result, nil
+			}
+		}
+	}
+
+	body, err := fetchFromAPI(list.url())
+	if err != nil {
+		return nil, err
+	}
+
+	result, endDate, err := parseAnalytics(body)
+	if err != nil {
+		return nil, err
+	}
+
+	writeCacheFile(list.cacheFile(), body)
+	if list == popularityFormulaList || list == popularityCaskList {
+		// History for trending; a missing snapshot only delays trends, so errors are ignored
+		_ = recordAnalyticsSnapshot(list.category, endDate, result)
+	}
+	return result, nil
+}
+
+// loadAnalytics retrieves several analytics lists concurrently. When some fail, the
+// lists that loaded are returned with the first error.
+func (d *DataProvider) loadAnalytics(lists []analyticsList, forceRefresh bool) (map[analyticsList]map[string]models.AnalyticsItem, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		result   = make(map[analyticsList]map[string]models.AnalyticsItem, len(lists))
+	)
+
+	for _, list := range lists {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			items, err := d.GetAnalytics(list, forceRefresh)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get %s analytics (%s): %w", list.category, list.period, err)
+				}
+				return
+			}
+			result[list] = items
+		}()
+	}
+	wg.Wait()
+	return result, firstErr
+}
+
+// loadCachedAnalytics reads the cached analytics lists regardless of age, skipping missing ones.
+func loadCachedAnalytics(lists []analyticsList) map[analyticsList]map[string]models.AnalyticsItem {
+	result := make(map[analyticsList]map[string]models.AnalyticsItem, len(lists))
+	for _, list := range lists {
+		if data := readStaleCacheFile(list.cacheFile(), 100); data != nil {
+			if items, _, err := parseAnalytics(data); err == nil {
+				result[list] = items
+			}
+		}
+	}
+	return result
+}
+
+// setAnalytics replaces the loaded analytics lists and the metric they describe.
+func (d *DataProvider) setAnalytics(lists map[analyticsList]map[string]models.AnalyticsItem, metric models.AnalyticsMetric) {
+	d.analyticsMu.Lock()
+	defer d.analyticsMu.Unlock()
+	d.analytics = lists
+	d.analyticsMetric = metric
+}
+
+// SetAnalyticsMetric loads every window of a metric (from cache or the API) and
+// makes it the one GetPackages reports in Package.Analytics.
+func (d *DataProvider) SetAnalyticsMetric(metric models.AnalyticsMetric) error {
+	lists, err := d.loadAnalytics(metricLists(metric), false)
+	if err != nil {
+		return err
+	}
+	d.setAnalytics(lists, metric)
+	return nil
+}
+
+// analyticsCount converts an analytics item to a rank and count.
+func analyticsCount(items map[string]models.AnalyticsItem, name string) models.AnalyticsCount {
+	item, exists := items[name]
+	if !exists || item.Number <= 0 {
+		return models.AnalyticsCount{}
+	}
+	count, _ := strconv.Atoi(strings.ReplaceAll(item.Count, ",", ""))
+	return models.AnalyticsCount{Rank: item.Number, Count: count}
+}
+
+// packageEnricher returns a function applying the loaded analytics to a package.
+// The lists are looked up once, so it can be used for the whole catalogue.
+func (d *DataProvider) packageEnricher() func(pkg *models.Package) {
+	d.analyticsMu.RLock()
+	lists, metric := d.analytics, d.analyticsMetric
+	d.analyticsMu.RUnlock()
+
+	formulaWindows := make(map[models.AnalyticsPeriod]map[string]models.AnalyticsItem)
+	caskWindows := make(map[models.AnalyticsPeriod]map[string]models.AnalyticsItem)
+	for _, period := range models.AnalyticsPeriods {
+		formulaWindows[period] = lists[analyticsList{metric.FormulaCategory(), period}]
+		if category := metric.CaskCategory(); category != "" {
+			caskWindows[period] = lists[analyticsList{category, period}]
+		}
+	}
+	formulaPopularity, caskPopularity := lists[popularityFormulaList], lists[popularityCaskList]
+
+	return func(pkg *models.Package) {
+		popularity, windows := formulaPopularity, formulaWindows
+		if pkg.Type == models.PackageTypeCask {
+			popularity, windows = caskPopularity, caskWindows
+		}
+
+		if c := analyticsCount(popularity, pkg.Name); c.Rank > 0 {
+			pkg.Analytics90dRank = c.Rank
+			pkg.Analytics90dDownloads = c.Count
+		}
+		for _, period := range models.AnalyticsPeriods {
+			pkg.Analytics[period] = analyticsCount(windows[period], pkg.Name)
+		}
+	}
+}
+
+// ApplyAnalytics refreshes the analytics of already built packages in place,
+// e.g. after SetAnalyticsMetric, without rebuilding the package list.
+func (d *DataProvider) ApplyAnalytics(packages []models.Package) {
+	enrich := d.packageEnricher()
+	for i := range packages {
+		if packages[i].Type == models.PackageTypeFormula || packages[i].Type == models.PackageTypeCask {
+			enrich(&packages[i])
+		}
+	}
+}
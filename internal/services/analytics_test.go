@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestMetricLists(t *testing.T) {
+	lists := metricLists(models.MetricInstallOnRequest)
+	if len(lists) != 6 {
+		t.Errorf("install-on-request needs 6 lists (3 windows × formulae and casks), got %d: %v", len(lists), lists)
+	}
+
+	seen := make(map[analyticsList]bool)
+	for _, list := range metricLists(models.MetricBuildError) {
+		if seen[list] {
+			t.Errorf("duplicate list %v", list)
+		}
+		seen[list] = true
+		if list.category == "cask-install" && list != popularityCaskList {
+			t.Errorf("build errors should not load cask windows, got %v", list)
+		}
+	}
+	if !seen[popularityFormulaList] || !seen[popularityCaskList] {
+		t.Error("popularity lists must always be loaded")
+	}
+	if !seen[analyticsList{"build-error", models.Analytics365d}] {
+		t.Error("missing the 365d build-error list")
+	}
+}
+
+func TestAnalyticsList_URLAndCacheFile(t *testing.T) {
+	list := analyticsList{"install-on-request", models.Analytics30d}
+	if got, want := list.url(), "https://formulae.brew.sh/api/analytics/install-on-request/30d.json"; got != want {
+		t.Errorf("url() = %q, want %q", got, want)
+	}
+	if got, want := list.cacheFile(), "analytics-install-on-request-30d.json"; got != want {
+		t.Errorf("cacheFile() = %q, want %q", got, want)
+	}
+}
+
+func TestParseAnalytics(t *testing.T) {
+	data := []byte(`{"category":"install","end_date":"2026-10-18","items":[
+		{"number":1,"formula":"openssl@3","count":"1,234,567","percent":"5.1"},
+		{"number":2,"cask":"firefox","count":"89","percent":"0.1"}
+	]}`)
+
+	items, endDate, err := parseAnalytics(data)
+	if err != nil {
+		t.Fatalf("parseAnalytics() error: %v", err)
+	}
+	if endDate != "2026-10-18" {
+		t.Errorf("end date = %q, want 2026-10-18", endDate)
+	}
+	if got := analyticsCount(items, "openssl@3"); got != (models.AnalyticsCount{Rank: 1, Count: 1234567}) {
+		t.Errorf("openssl@3 = %+v, want rank 1 and count 1234567", got)
+	}
+	if got := analyticsCount(items, "firefox"); got.Count != 89 {
+		t.Errorf("firefox count = %d, want 89", got.Count)
+	}
+	if got := analyticsCount(items, "missing"); got != (models.AnalyticsCount{}) {
+		t.Errorf("missing package = %+v, want zero", got)
+	}
+
+	if _, _, err := parseAnalytics([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestApplyAnalytics(t *testing.T) {
+	item := func(name string, rank int, count string) map[string]models.AnalyticsItem {
+		return map[string]models.AnalyticsItem{name: {Number: rank, Formula: name, Count: count}}
+	}
+	d := NewDataProvider()
+	d.setAnalytics(map[analyticsList]map[string]models.AnalyticsItem{
+		popularityFormulaList:                        item("jq", 7, "700"),
+		{"install", models.Analytics30d}:             item("jq", 3, "300"),
+		{"install", models.Analytics90d}:             item("jq", 2, "900"),
+		{"install", models.Analytics365d}:            item("jq", 1, "3,600"),
+		{"install-on-request", models.Analytics365d}: item("jq", 9, "1"),
+	}, models.MetricInstall)
+
+	packages := []models.Package{
+		{Name: "jq", Type: models.PackageTypeFormula},
+		{Name: "jq", Type: models.PackageTypeCask}, // Cask lists are not loaded: stays empty
+	}
+	d.ApplyAnalytics(packages)
+
+	jq := packages[0]
+	if jq.Analytics90dRank != 7 || jq.Analytics90dDownloads != 700 {
+		t.Errorf("popularity = #%d/%d, want #7/700", jq.Analytics90dRank, jq.Analytics90dDownloads)
+	}
+	want := models.PackageAnalytics{{Rank: 3, Count: 300}, {Rank: 2, Count: 900}, {Rank: 1, Count: 3600}}
+	if jq.Analytics != want {
+		t.Errorf("Analytics = %+v, want %+v", jq.Analytics, want)
+	}
+	if packages[1].Analytics != (models.PackageAnalytics{}) {
+		t.Errorf("cask analytics = %+v, want empty", packages[1].Analytics)
+	}
+}
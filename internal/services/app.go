@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
-	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -40,14 +40,29 @@ type AppService struct {
 	packages         *[]models.Package
 	filteredPackages *[]models.Package
 	activeFilter     FilterType
-	activeSort       models.SortMode
+	sortKeys         []models.SortKey        // Active sort, primary key first; empty for no sort
+	activePeriod     models.AnalyticsPeriod  // Analytics window shown in the Downloads column
+	activeMetric     models.AnalyticsMetric  // Analytics metric shown in the Downloads column
+	showSize         bool                    // Show the installed Size column
+	showLicense      bool                    // Show the License column
+	diskScanMu       sync.Mutex              // Held while installed sizes are being measured
+	searchMatches    map[string]searchMatch  // Match details of the current search, keyed by package name
+	trends           map[string]models.Trend // Popularity changes for the Trending filter, keyed by packageKey
+	trendPeriod      models.TrendPeriod
+	trendSince       string          // Date of the snapshot trends are compared with
+	orphans          map[string]bool // Orphaned formulae for the Orphans filter, keyed by packageKey
+	marked           map[string]bool // Rows marked for a batch of jobs, keyed by packageKey
+	installOptions   installOptionsStore
 	brewVersion      string
+	latestVersion    string // Latest Bold Brew release, set by the background update check
 
 	// Brewfile support
 	brewfilePath     string
 	brewfilePackages *[]models.Package
 	brewfileTaps     []string // Taps required by the Brewfile
 
+	brewfileRestartServices map[string]bool // Formulae whose service is restarted after install (restart_service:)
+
 	brewService       BrewServiceInterface
 	flatpakService    FlatpakServiceInterface
 	masService        MasServiceInterface
@@ -73,6 +88,7 @@ var NewAppService = func() AppServiceInterface {
 		packages:         new([]models.Package),
 		filteredPackages: new([]models.Package),
 		activeFilter:     FilterNone,
+		activePeriod:     models.Analytics90d,
 		brewVersion:      "-",
 
 		brewfilePath:     "",
@@ -107,27 +123,30 @@ func (s *AppService) GetBrewfilePackages() *[]models.Package {
 	return s.brewfilePackages
 }
 
-// Boot initializes the application by setting up Homebrew and loading formulae data.
+// Boot initializes the application from cached data only, so the UI can be painted
+// instantly. It runs no brew commands and makes no network calls: the cache may be
+// stale (or empty on first run) and is revalidated in the background by BuildApp.
+// It only fails when Homebrew is missing, which the app cannot work without.
 func (s *AppService) Boot() (err error) {
-	if s.brewVersion, err = s.brewService.GetBrewVersion(); err != nil {
-		// This error is critical, as we need Homebrew to function
-		return fmt.Errorf("failed to get Homebrew version: %v", err)
+	if !s.brewService.IsBrewInstalled() {
+		return fmt.Errorf("homebrew not found: brew is not in the PATH")
 	}
 
-	// Load Homebrew data from cache for fast startup
-	// Installation status might be stale but will be refreshed in background by updateHomeBrew()
-	if err = s.dataProvider.SetupData(false); err != nil {
-		// Log error but don't fail - app can work with empty/partial data
-		fmt.Fprintf(os.Stderr, "Warning: failed to load Homebrew data (will retry in background): %v\n", err)
+	if data := readStaleCacheFile(cacheFileBrewVersion, 1); data != nil {
+		s.brewVersion = strings.TrimSpace(string(data))
 	}
 
+	// A missing cache is expected on first run: the table is filled in
+	// as soon as the background revalidation completes.
+	_ = s.dataProvider.LoadCachedData()
+
 	// Initialize packages and filteredPackages
 	s.packages = s.dataProvider.GetPackages()
 	*s.filteredPackages = *s.packages
 
 	// If Brewfile is specified, parse it and filter packages
 	if s.IsBrewfileMode() {
-		if err = s.loadBrewfilePackages(); err != nil {
+		if err = s.loadCachedBrewfilePackages(); err != nil {
 			return fmt.Errorf("failed to load Brewfile: %v", err)
 		}
 	}
@@ -135,6 +154,46 @@ func (s *AppService) Boot() (err error) {
 	return nil
 }
 
+// revalidate refreshes all data in the background while the UI shows cached results.
+// The header displays a refreshing indicator until fresh data has landed.
+func (s *AppService) revalidate() {
+	s.app.QueueUpdateDraw(func() {
+		s.layout.GetHeader().SetRefreshing(true)
+	})
+	defer s.app.QueueUpdateDraw(func() {
+		s.layout.GetHeader().SetRefreshing(false)
+	})
+
+	brewVersion, err := s.brewService.GetBrewVersion()
+	if err != nil {
+		// This error is critical, as we need Homebrew to function
+		s.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to get Homebrew version: %v", err))
+		})
+		return
+	}
+	if err := ensureCacheDir(); err == nil {
+		writeCacheFile(cacheFileBrewVersion, []byte(brewVersion))
+	}
+	s.app.QueueUpdateDraw(func() {
+		s.brewVersion = brewVersion
+		s.updateHeader()
+	})
+
+	// Load fresh local state (and any expired API data) before the slower `brew update`
+	if err := s.dataProvider.SetupData(false); err == nil {
+		s.refreshResults()
+	}
+	go s.refreshDiskUsage()
+
+	// In Brewfile mode, install missing taps first
+	if s.IsBrewfileMode() && len(s.brewfileTaps) > 0 {
+		s.installBrewfileTapsAtStartup()
+	}
+	// Then update Homebrew (which will reload all data including new taps)
+	s.updateHomeBrew()
+}
+
 // updateHomeBrew updates the Homebrew formulae and refreshes the results in the UI.
 func (s *AppService) updateHomeBrew() {
 	s.app.QueueUpdateDraw(func() {
@@ -153,19 +212,33 @@ func (s *AppService) updateHomeBrew() {
 	s.forceRefreshResults()
 }
 
+// updateHeader renders the header, including the new version notice if one was found.
+func (s *AppService) updateHeader() {
+	headerName := AppName
+	if s.IsBrewfileMode() {
+		headerName = fmt.Sprintf("%s [Brewfile Mode]", AppName)
+	}
+	displayVersion := AppVersion
+	if s.latestVersion != "" && s.latestVersion != AppVersion {
+		displayVersion = fmt.Sprintf("%s ([orange]New Version Available: %s[-])", AppVersion, s.latestVersion)
+	}
+	s.layout.GetHeader().Update(headerName, displayVersion, s.brewVersion)
+	if total := s.dataProvider.DiskUsageTotal(); total > 0 {
+		s.layout.GetHeader().SetDiskUsage(models.FormatSize(total))
+	}
+}
+
 // BuildApp builds the application layout, sets up event handlers, and initializes the UI components.
 func (s *AppService) BuildApp() {
 	// Build the layout
 	s.layout.Setup()
 
 	// Update header and enable Brewfile mode features if needed
-	headerName := AppName
 	if s.IsBrewfileMode() {
-		headerName = fmt.Sprintf("%s [Brewfile Mode]", AppName)
 		s.layout.GetSearch().Field().SetLabel("Search (Brewfile): ")
 		s.inputService.EnableBrewfileMode() // Add Install All action
 	}
-	s.layout.GetHeader().Update(headerName, AppVersion, s.brewVersion)
+	s.updateHeader()
 
 	// Evaluate if there is a new version available
 	// This is done in a goroutine to avoid blocking the UI during startup
@@ -180,12 +253,8 @@ func (s *AppService) BuildApp() {
 			return
 		}
 		s.app.QueueUpdateDraw(func() {
-			displayVersion := fmt.Sprintf("%s ([orange]New Version Available: %s[-])", AppVersion, latestVersion)
-			headerName := AppName
-			if s.IsBrewfileMode() {
-				headerName = fmt.Sprintf("%s [Brewfile Mode]", AppName)
-			}
-			s.layout.GetHeader().Update(headerName, displayVersion, s.brewVersion)
+			s.latestVersion = latestVersion
+			s.updateHeader()
 		})
 	}()
 
@@ -201,6 +270,14 @@ func (s *AppService) BuildApp() {
 	}
 	s.layout.GetTable().View().SetSelectionChangedFunc(tableSelectionChangedFunc)
 
+	// Clicking a column header sorts by that column
+	s.layout.GetTable().SetHeaderClickHandler(func(column int) {
+		if mode, ok := s.columnSortMode(column); ok {
+			key := s.SortByColumn(mode)
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Sort: %s", describeSortKey(key)))
+		}
+	})
+
 	// Search input handlers
 	inputDoneFunc := func(key tcell.Key) {
 		if key == tcell.KeyEnter || key == tcell.KeyEscape {
@@ -219,15 +296,8 @@ func (s *AppService) BuildApp() {
 	s.app.SetRoot(s.layout.Root(), true)
 	s.app.SetFocus(s.layout.GetTable().View())
 
-	// Start background tasks: install taps first (if Brewfile mode), then update Homebrew
-	go func() {
-		// In Brewfile mode, install missing taps first
-		if s.IsBrewfileMode() && len(s.brewfileTaps) > 0 {
-			s.installBrewfileTapsAtStartup()
-		}
-		// Then update Homebrew (which will reload all data including new taps)
-		s.updateHomeBrew()
-	}()
+	// Revalidate cached data in the background: the first paint below never waits on brew
+	go s.revalidate()
 
 	// Set initial results based on mode
 	if s.IsBrewfileMode() {
@@ -237,3 +307,28 @@ func (s *AppService) BuildApp() {
 		s.setResults(s.packages, true) // Show all packages
 	}
 }
+
+// installedPackages returns the installed packages matching a condition.
+func (s *AppService) installedPackages(match func(pkg *models.Package) bool) []models.Package {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var matched []models.Package
+	for i := range *s.packages {
+		if pkg := &(*s.packages)[i]; pkg.LocallyInstalled && match(pkg) {
+			matched = append(matched, *pkg)
+		}
+	}
+	return matched
+}
+
+// withCaveats returns the current data of the given packages that are installed and
+// have caveats, e.g. to show them after an install once the package list is refreshed.
+func (s *AppService) withCaveats(packages []models.Package) []models.Package {
+	wanted := make(map[string]bool, len(packages))
+	for _, pkg := range packages {
+		wanted[packageKey(pkg.Type, pkg.Name)] = true
+	}
+	return s.installedPackages(func(pkg *models.Package) bool {
+		return pkg.Caveats != "" && wanted[packageKey(pkg.Type, pkg.Name)]
+	})
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"bbrew/internal/models"
+)
+
+// macOSReleases maps macOS major versions to bottle tag names, newest first.
+var macOSReleases = []struct {
+	version string
+	name    string
+}{
+	{"26", "tahoe"},
+	{"15", "sequoia"},
+	{"14", "sonoma"},
+	{"13", "ventura"},
+	{"12", "monterey"},
+	{"11", "big_sur"},
+	{"10.15", "catalina"},
+	{"10.14", "mojave"},
+}
+
+var (
+	platformTagOnce sync.Once
+	platformTag     string
+)
+
+// platformBottleTag returns the bottle tag of the running platform, e.g. arm64_sonoma
+// or x86_64_linux, or an empty string when it cannot be determined.
+func platformBottleTag() string {
+	platformTagOnce.Do(func() {
+		var macVersion string
+		if runtime.GOOS == "darwin" {
+			if out, err := exec.Command("sw_vers", "-productVersion").Output(); err == nil {
+				macVersion = strings.TrimSpace(string(out))
+			}
+		}
+		platformTag = detectPlatformTag(runtime.GOOS, runtime.GOARCH, macVersion)
+	})
+	return platformTag
+}
+
+// detectPlatformTag builds the bottle tag of a platform.
+func detectPlatformTag(goos, goarch, macVersion string) string {
+	arch := "x86_64"
+	if goarch == "arm64" {
+		arch = "arm64"
+	}
+	switch goos {
+	case "linux":
+		return arch + "_linux"
+	case "darwin":
+		name := macOSReleaseName(macVersion)
+		if name == "" {
+			return ""
+		}
+		if arch == "arm64" {
+			return "arm64_" + name
+		}
+		return name
+	}
+	return ""
+}
+
+// macOSReleaseName returns the bottle name of a macOS version ("14.5" is sonoma).
+func macOSReleaseName(version string) string {
+	for _, release := range macOSReleases {
+		if version == release.version || strings.HasPrefix(version, release.version+".") {
+			return release.name
+		}
+	}
+	// A release newer than this list still runs the newest bottles
+	if major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0]); err == nil && major > 26 {
+		return macOSReleases[0].name
+	}
+	return ""
+}
+
+// bottleTagFor returns the tag of the bottle brew pours on a platform: the platform's
+// own, one built for all platforms, or on macOS the one for the newest older release.
+// It is empty when the formula has to be built from source.
+func bottleTagFor(files map[string]models.BottleFile, platform string) string {
+	if platform == "" {
+		return ""
+	}
+	if _, ok := files[platform]; ok {
+		return platform
+	}
+	if _, ok := files["all"]; ok {
+		return "all"
+	}
+	if strings.HasSuffix(platform, "_linux") {
+		return ""
+	}
+
+	prefix := ""
+	if strings.HasPrefix(platform, "arm64_") {
+		prefix = "arm64_"
+	}
+	older := false
+	for _, release := range macOSReleases {
+		tag := prefix + release.name
+		if tag == platform {
+			older = true
+			continue
+		}
+		if _, ok := files[tag]; ok && older {
+			return tag
+		}
+	}
+	return ""
+}
+
+// bottleStatus describes the bottle of a formula for the running platform.
+func bottleStatus(f *models.Formula, platform string) models.BottleStatus {
+	return models.BottleStatus{Platform: platform, Tag: bottleTagFor(f.Bottle.Stable.Files, platform)}
+}
+
+// bottleSizes caches the download size of bottles, keyed by formula name.
+var bottleSizes sync.Map
+
+// cachedBottleSize returns the download size of a formula's bottle if it was fetched.
+func cachedBottleSize(name string) (int64, bool) {
+	size, ok := bottleSizes.Load(name)
+	if !ok {
+		return 0, false
+	}
+	return size.(int64), true
+}
+
+// BottleSize returns the download size of the bottle brew would pour for a formula,
+// asking the bottle registry for it the first time.
+func (s *AppService) BottleSize(pkg models.Package) (int64, error) {
+	if size, ok := cachedBottleSize(pkg.Name); ok {
+		return size, nil
+	}
+	if !pkg.Bottle.Available() {
+		return 0, fmt.Errorf("no bottle for %s", pkg.Name)
+	}
+
+	// The compact catalogue keeps bottle tags only: load the full record for the URL
+	formula, err := s.dataProvider.GetFormulaDetails(pkg.Name)
+	if err != nil {
+		return 0, err
+	}
+	file, ok := formula.Bottle.Stable.Files[pkg.Bottle.Tag]
+	if !ok || file.URL == "" {
+		return 0, fmt.Errorf("no bottle URL for %s", pkg.Name)
+	}
+
+	size, err := fetchContentLength(file.URL)
+	if err != nil {
+		return 0, err
+	}
+	bottleSizes.Store(pkg.Name, size)
+	return size, nil
+}
+
+// fetchContentLength asks for the size of a download without fetching it. Homebrew's
+// bottles live on GitHub Packages, which accepts an anonymous token.
+func fetchContentLength(url string) (int64, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer QQ==")
+	resp, err := httpClient.Do(req) // #nosec G107 -- URL comes from the Homebrew API
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ContentLength <= 0 {
+		return 0, fmt.Errorf("bottle size unavailable (HTTP %d)", resp.StatusCode)
+	}
+	return resp.ContentLength, nil
+}
+
+// bottleNote tells, for an install confirmation, whether a formula is poured from a
+// bottle (with its download size when known) or built from source on this platform.
+func bottleNote(pkg models.Package, opts models.InstallOptions) string {
+	if pkg.Type != models.PackageTypeFormula || pkg.Bottle.Platform == "" || opts.HEAD || opts.BuildFromSource {
+		return ""
+	}
+	if !pkg.Bottle.Available() {
+		return fmt.Sprintf("⚠ No bottle for %s: %s will be built from source, which can take a long time.",
+			pkg.Bottle.Platform, pkg.Name)
+	}
+	note := "Bottle: " + pkg.Bottle.Tag
+	if pkg.Bottle.Size > 0 {
+		note += ", " + models.FormatSize(pkg.Bottle.Size) + " download"
+	}
+	return note
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestDetectPlatformTag(t *testing.T) {
+	tests := []struct {
+		goos, goarch, macVersion string
+		want                     string
+	}{
+		{"darwin", "arm64", "14.5", "arm64_sonoma"},
+		{"darwin", "amd64", "13.6.7", "ventura"},
+		{"darwin", "arm64", "15.0", "arm64_sequoia"},
+		{"darwin", "arm64", "10.15.7", "arm64_catalina"},
+		{"darwin", "arm64", "27.1", "arm64_tahoe"}, // newer than the known releases
+		{"darwin", "arm64", "", ""},
+		{"linux", "amd64", "", "x86_64_linux"},
+		{"linux", "arm64", "", "arm64_linux"},
+		{"windows", "amd64", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := detectPlatformTag(tt.goos, tt.goarch, tt.macVersion); got != tt.want {
+			t.Errorf("detectPlatformTag(%q, %q, %q) = %q, want %q", tt.goos, tt.goarch, tt.macVersion, got, tt.want)
+		}
+	}
+}
+
+func TestBottleTagFor(t *testing.T) {
+	files := func(tags ...string) map[string]models.BottleFile {
+		m := make(map[string]models.BottleFile)
+		for _, tag := range tags {
+			m[tag] = models.BottleFile{}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		files    map[string]models.BottleFile
+		platform string
+		want     string
+	}{
+		{"exact tag", files("arm64_sonoma", "sonoma", "x86_64_linux"), "arm64_sonoma", "arm64_sonoma"},
+		{"all platforms", files("all"), "x86_64_linux", "all"},
+		{"older macOS release", files("arm64_ventura", "ventura"), "arm64_sequoia", "arm64_ventura"},
+		{"newer macOS only", files("arm64_sequoia"), "arm64_sonoma", ""},
+		{"no Intel bottle", files("arm64_sonoma"), "sonoma", ""},
+		{"no Linux bottle", files("arm64_sonoma", "sonoma"), "x86_64_linux", ""},
+		{"unknown platform", files("arm64_sonoma"), "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := bottleTagFor(tt.files, tt.platform); got != tt.want {
+			t.Errorf("%s: bottleTagFor(%s) = %q, want %q", tt.name, tt.platform, got, tt.want)
+		}
+	}
+}
+
+func TestBottleNote(t *testing.T) {
+	pkg := models.Package{Name: "llvm", Type: models.PackageTypeFormula, Bottle: models.BottleStatus{Platform: "x86_64_linux"}}
+	if note := bottleNote(pkg, models.InstallOptions{}); !strings.Contains(note, "built from source") {
+		t.Errorf("bottleNote() = %q, want a build from source warning", note)
+	}
+	if note := bottleNote(pkg, models.InstallOptions{BuildFromSource: true}); note != "" {
+		t.Errorf("bottleNote() = %q, want nothing when building from source was asked for", note)
+	}
+
+	pkg.Bottle = models.BottleStatus{Platform: "arm64_sequoia", Tag: "arm64_sonoma", Size: 1_500_000}
+	if note := bottleNote(pkg, models.InstallOptions{}); note != "Bottle: arm64_sonoma, 1.5 MB download" {
+		t.Errorf("bottleNote() = %q", note)
+	}
+
+	cask := models.Package{Name: "firefox", Type: models.PackageTypeCask}
+	if note := bottleNote(cask, models.InstallOptions{}); note != "" {
+		t.Errorf("bottleNote(cask) = %q, want nothing", note)
+	}
+}
@@ -46,12 +46,22 @@ func brewCommandContext(ctx context.Context, args ...string) *exec.Cmd {
 // BrewService is a pure executor of brew commands - it does NOT hold data.
 // For data retrieval, use DataProviderInterface.
 type BrewServiceInterface interface {
+	IsBrewInstalled() bool
 	GetBrewVersion() (string, error)
 	UpdateHomebrew() error
 	UpdateAllPackages(output io.Writer) error
 	UpdatePackage(info models.Package, output io.Writer) error
 	RemovePackage(info models.Package, output io.Writer) error
-	InstallPackage(info models.Package, output io.Writer) error
+	RemovePackages(packages []models.Package, ignoreDependencies bool, output io.Writer) error
+	AutoremovePreview() ([]string, error)
+	PinPackage(info models.Package, output io.Writer) error
+	UnpinPackage(info models.Package, output io.Writer) error
+	LinkPackage(info models.Package, force bool, output io.Writer) error
+	UnlinkPackage(info models.Package, output io.Writer) error
+	GetServices() ([]models.ServiceStatus, error)
+	ServiceAction(action, name string, output io.Writer) error
+	Autoremove(output io.Writer) error
+	InstallPackage(info models.Package, opts models.InstallOptions, output io.Writer) error
 	InstallTap(tapName string, output io.Writer) error
 	IsTapInstalled(tapName string) bool
 }
@@ -67,6 +77,12 @@ var NewBrewService = func() BrewServiceInterface {
 	return &BrewService{}
 }
 
+// IsBrewInstalled checks if the brew binary exists in the PATH.
+func (s *BrewService) IsBrewInstalled() bool {
+	_, err := exec.LookPath("brew")
+	return err == nil
+}
+
 // GetBrewVersion retrieves the version of Homebrew installed on the system, caching it for future calls.
 func (s *BrewService) GetBrewVersion() (string, error) {
 	if s.brewVersion != "" {
@@ -117,14 +133,101 @@ func (s *BrewService) RemovePackage(info models.Package, output io.Writer) error
 	return ExecuteCommand(cmd, output)
 }
 
-// InstallPackage installs a package.
-func (s *BrewService) InstallPackage(info models.Package, output io.Writer) error {
-	var cmd *exec.Cmd
+// RemovePackages uninstalls several packages, casks first since they may depend on
+// formulae being removed. With ignoreDependencies, formulae are removed even when
+// installed packages still depend on them.
+func (s *BrewService) RemovePackages(packages []models.Package, ignoreDependencies bool, output io.Writer) error {
+	var formulae, casks []string
+	for _, pkg := range packages {
+		if pkg.Type == models.PackageTypeCask {
+			casks = append(casks, pkg.Name)
+		} else {
+			formulae = append(formulae, pkg.Name)
+		}
+	}
+
+	if len(casks) > 0 {
+		cmd := brewCommand(append([]string{"uninstall", "--cask"}, casks...)...) // #nosec G204
+		if err := ExecuteCommand(cmd, output); err != nil {
+			return err
+		}
+	}
+	if len(formulae) > 0 {
+		args := []string{"uninstall"}
+		if ignoreDependencies {
+			args = append(args, "--ignore-dependencies")
+		}
+		cmd := brewCommand(append(args, formulae...)...) // #nosec G204
+		return ExecuteCommand(cmd, output)
+	}
+	return nil
+}
+
+// AutoremovePreview returns the formulae `brew autoremove` would uninstall, without removing them.
+func (s *BrewService) AutoremovePreview() ([]string, error) {
+	output, err := brewCommand("autoremove", "--dry-run").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseAutoremoveDryRun(string(output)), nil
+}
+
+// parseAutoremoveDryRun extracts the formula names from `brew autoremove --dry-run`,
+// which lists one per line after a "==> Would autoremove N unneeded formulae:" heading.
+func parseAutoremoveDryRun(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "==>") || strings.HasPrefix(line, "Warning:") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names
+}
+
+// Autoremove uninstalls the formulae that were only installed as dependencies and are no longer needed.
+func (s *BrewService) Autoremove(output io.Writer) error {
+	cmd := brewCommand("autoremove")
+	return ExecuteCommand(cmd, output)
+}
+
+// PinPackage pins a formula at its installed version, so brew upgrade leaves it alone.
+func (s *BrewService) PinPackage(info models.Package, output io.Writer) error {
+	cmd := brewCommand("pin", info.Name) // #nosec G204
+	return ExecuteCommand(cmd, output)
+}
+
+// UnpinPackage unpins a formula, allowing brew upgrade to update it again.
+func (s *BrewService) UnpinPackage(info models.Package, output io.Writer) error {
+	cmd := brewCommand("unpin", info.Name) // #nosec G204
+	return ExecuteCommand(cmd, output)
+}
+
+// LinkPackage symlinks a formula into the Homebrew prefix. Keg-only formulae need force.
+func (s *BrewService) LinkPackage(info models.Package, force bool, output io.Writer) error {
+	args := []string{"link", info.Name}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := brewCommand(args...) // #nosec G204
+	return ExecuteCommand(cmd, output)
+}
+
+// UnlinkPackage removes a formula's symlinks from the Homebrew prefix, keeping it installed.
+func (s *BrewService) UnlinkPackage(info models.Package, output io.Writer) error {
+	cmd := brewCommand("unlink", info.Name) // #nosec G204
+	return ExecuteCommand(cmd, output)
+}
+
+// InstallPackage installs a package with the flags of the given install options.
+func (s *BrewService) InstallPackage(info models.Package, opts models.InstallOptions, output io.Writer) error {
+	args := []string{"install"}
 	if info.Type == models.PackageTypeCask {
-		cmd = brewCommand("install", "--cask", info.Name) // #nosec G204
-	} else {
-		cmd = brewCommand("install", info.Name) // #nosec G204
+		args = append(args, "--cask")
 	}
+	args = append(args, opts.Flags(info.Type)...)
+	cmd := brewCommand(append(args, info.Name)...) // #nosec G204
 	return ExecuteCommand(cmd, output)
 }
 
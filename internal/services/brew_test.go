@@ -0,0 +1,22 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseAutoremoveDryRun(t *testing.T) {
+	output := `==> Would autoremove 3 unneeded formulae:
+libidn2
+openssl@3
+ca-certificates
+`
+	want := []string{"libidn2", "openssl@3", "ca-certificates"}
+	if got := parseAutoremoveDryRun(output); !slices.Equal(got, want) {
+		t.Errorf("parseAutoremoveDryRun() = %v, want %v", got, want)
+	}
+
+	if got := parseAutoremoveDryRun(""); len(got) != 0 {
+		t.Errorf("nothing to remove should give no names, got %v", got)
+	}
+}
@@ -9,17 +9,18 @@
 //
 // Execution sequence (Brewfile mode only):
 //
-//  1. Boot() → loadBrewfilePackages()
-//     Initial load using cached tap data for fast startup.
+//  1. Boot() → loadCachedBrewfilePackages()
+//     Initial load from cached data only (no brew calls) for instant startup.
 //
-//  2. BuildApp() → goroutine:
+//  2. BuildApp() → revalidate() goroutine:
 //     a) installBrewfileTapsAtStartup()
 //     Installs any missing taps from the Brewfile.
 //     b) updateHomeBrew() → forceRefreshResults()
 //     Refreshes Homebrew data and reloads packages.
 //
 //  3. forceRefreshResults() → fetchTapPackages() + loadBrewfilePackages()
-//     Fetches fresh tap package info and rebuilds the package list.
+//     Fetches fresh tap package info, verifies installed status
+//     and rebuilds the package list.
 package services
 
 import (
@@ -29,6 +30,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -134,7 +136,8 @@ func parseBrewfileWithTaps(filepath string) (*models.BrewfileResult, error) {
 		if strings.HasPrefix(line, "brew ") {
 			if name, ok := extractQuotedValue(line); ok {
 				result.Packages = append(result.Packages, models.BrewfileEntry{
-					Name: name,
+					Name:           name,
+					RestartService: hasRestartService(line),
 				})
 			}
 		}
@@ -172,6 +175,17 @@ func parseBrewfileWithTaps(filepath string) (*models.BrewfileResult, error) {
 	return result, nil
 }
 
+// hasRestartService reports whether a brew line asks for its service to be restarted.
+// Format: brew "postgresql@16", restart_service: true (or :changed)
+func hasRestartService(line string) bool {
+	idx := strings.Index(line, "restart_service:")
+	if idx == -1 {
+		return false
+	}
+	value := strings.TrimSpace(line[idx+len("restart_service:"):])
+	return strings.HasPrefix(value, "true") || strings.HasPrefix(value, ":changed")
+}
+
 // extractMasID extracts the numeric ID from a mas Brewfile line.
 // Format: mas "App Name", id: 1234567
 func extractMasID(line string) string {
@@ -195,6 +209,78 @@ func extractMasID(line string) string {
 	return id.String()
 }
 
+// resolveRenamedEntries rewrites Brewfile entries that use an old name or alias of a
+// known formula, or an old token of a known cask, to the package's current name.
+func resolveRenamedEntries(entries []models.BrewfileEntry, packages []models.Package) []models.BrewfileEntry {
+	current := make(map[string]bool, len(packages))
+	renamed := make(map[string]string)
+	for _, pkg := range packages {
+		key := string(pkg.Type) + ":"
+		current[key+pkg.Name] = true
+		switch {
+		case pkg.Formula != nil:
+			for _, old := range slices.Concat(pkg.Formula.OldNames, pkg.Formula.Aliases) {
+				renamed[key+old] = pkg.Name
+			}
+		case pkg.Cask != nil:
+			for _, old := range pkg.Cask.OldTokens {
+				renamed[key+old] = pkg.Name
+			}
+		}
+	}
+
+	resolved := make([]models.BrewfileEntry, len(entries))
+	for i, entry := range entries {
+		resolved[i] = entry
+		if entry.IsFlatpak || entry.IsMas {
+			continue
+		}
+		key := string(models.PackageTypeFormula) + ":" + entry.Name
+		if entry.IsCask {
+			key = string(models.PackageTypeCask) + ":" + entry.Name
+		}
+		if name, ok := renamed[key]; ok && !current[key] {
+			resolved[i].Name = name
+		}
+	}
+	return resolved
+}
+
+// loadCachedBrewfilePackages parses the Brewfile and builds the package list from
+// already loaded packages and the tap cache, trusting their cached installed status.
+// It never runs brew, flatpak or mas; Flatpak and Mac App Store entries and
+// uncached tap packages appear once loadBrewfilePackages runs in the background.
+func (s *AppService) loadCachedBrewfilePackages() error {
+	result, err := parseBrewfileWithTaps(s.brewfilePath)
+	if err != nil {
+		return err
+	}
+
+	s.brewfileTaps = result.Taps
+	result.Packages = resolveRenamedEntries(result.Packages, *s.packages)
+
+	existingPackages := make(map[string]models.Package)
+	for _, pkg := range *s.packages {
+		existingPackages[pkg.Name] = pkg
+	}
+
+	foundPackages := make(map[string]bool)
+	*s.brewfilePackages = []models.Package{}
+	for _, pkg := range s.dataProvider.GetCachedTapPackages(result.Packages, existingPackages) {
+		if foundPackages[pkg.Name] {
+			continue
+		}
+		*s.brewfilePackages = append(*s.brewfilePackages, pkg)
+		foundPackages[pkg.Name] = true
+	}
+
+	sort.Slice(*s.brewfilePackages, func(i, j int) bool {
+		return (*s.brewfilePackages)[i].Name < (*s.brewfilePackages)[j].Name
+	})
+
+	return nil
+}
+
 // loadBrewfilePackages parses the Brewfile and creates a filtered package list.
 // Uses the DataProvider to load tap packages from cache or fetch via brew info.
 func (s *AppService) loadBrewfilePackages() error {
@@ -206,9 +292,16 @@ func (s *AppService) loadBrewfilePackages() error {
 	// Store taps for later installation
 	s.brewfileTaps = result.Taps
 
+	// Entries using an old name or alias resolve to the current package
+	result.Packages = resolveRenamedEntries(result.Packages, *s.packages)
+
 	// Create a map for quick lookup of Brewfile entries
 	packageMap := make(map[string]models.PackageType)
+	s.brewfileRestartServices = make(map[string]bool)
 	for _, entry := range result.Packages {
+		if entry.RestartService {
+			s.brewfileRestartServices[entry.Name] = true
+		}
 		if entry.IsMas {
 			continue
 		}
@@ -369,7 +462,8 @@ func (s *AppService) fetchTapPackages() {
 	}
 
 	// Use DataProvider to fetch all tap packages (force download to get fresh data)
-	tapPackages, _ := s.dataProvider.GetTapPackages(result.Packages, existingPackages, true)
+	entries := resolveRenamedEntries(result.Packages, *s.packages)
+	tapPackages, _ := s.dataProvider.GetTapPackages(entries, existingPackages, true)
 
 	// Add tap packages to s.packages (avoiding duplicates)
 	for _, pkg := range tapPackages {
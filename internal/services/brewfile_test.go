@@ -3,6 +3,8 @@ package services
 import (
 	"os"
 	"testing"
+
+	"bbrew/internal/models"
 )
 
 func TestExtractQuotedValue(t *testing.T) {
@@ -168,6 +170,24 @@ func TestExtractMasID(t *testing.T) {
 	}
 }
 
+func TestHasRestartService(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`brew "postgresql@16", restart_service: true`, true},
+		{`brew "redis", restart_service: :changed`, true},
+		{`brew "nginx", restart_service: false`, false},
+		{`brew "wget"`, false},
+	}
+
+	for _, tt := range tests {
+		if got := hasRestartService(tt.line); got != tt.want {
+			t.Errorf("hasRestartService(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
 func TestParseBrewfileWithTaps_FileNotFound(t *testing.T) {
 	_, err := parseBrewfileWithTaps("/nonexistent/path/Brewfile")
 	if err == nil {
@@ -264,3 +284,31 @@ func createTempBrewfile(t *testing.T, content string) string {
 	f.Close()
 	return f.Name()
 }
+
+func TestResolveRenamedEntries(t *testing.T) {
+	packages := []models.Package{
+		{Name: "python@3.13", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "python@3.13", Aliases: []string{"python3", "python"}}},
+		{Name: "ripgrep", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "ripgrep", Aliases: []string{"rg"}}},
+		{Name: "visual-studio-code", Type: models.PackageTypeCask, Cask: &models.Cask{Token: "visual-studio-code", OldTokens: []string{"vscode"}}},
+		{Name: "rg", Type: models.PackageTypeCask, Cask: &models.Cask{Token: "rg"}}, // Same name as a formula alias
+	}
+	entries := []models.BrewfileEntry{
+		{Name: "python3"},
+		{Name: "wget"},
+		{Name: "vscode", IsCask: true},
+		{Name: "rg", IsCask: true},
+		{Name: "rg"},
+		{Name: "python3", IsFlatpak: true},
+	}
+
+	got := resolveRenamedEntries(entries, packages)
+	want := []string{"python@3.13", "wget", "visual-studio-code", "rg", "ripgrep", "python3"}
+	for i, entry := range got {
+		if entry.Name != want[i] {
+			t.Errorf("entry %d resolved to %q, want %q", i, entry.Name, want[i])
+		}
+	}
+	if entries[0].Name != "python3" {
+		t.Error("resolveRenamedEntries must not modify its input")
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"bbrew/internal/models"
+)
+
+// GetServices returns the status of the services of installed formulae.
+func (s *BrewService) GetServices() ([]models.ServiceStatus, error) {
+	output, err := brewCommand("services", "info", "--all", "--json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("brew services failed: %w", err)
+	}
+	return parseServicesInfo(output)
+}
+
+// parseServicesInfo decodes the output of `brew services info --json`.
+func parseServicesInfo(data []byte) ([]models.ServiceStatus, error) {
+	var statuses []models.ServiceStatus
+	if err := json.Unmarshal(data, &statuses); err != nil {
+		return nil, fmt.Errorf("failed to parse brew services output: %w", err)
+	}
+	return statuses, nil
+}
+
+// ServiceAction runs `brew services <action>` (start, stop, restart or run) for a formula.
+func (s *BrewService) ServiceAction(action, name string, output io.Writer) error {
+	if !slices.Contains(models.ServiceActions, action) {
+		return fmt.Errorf("unknown service action %q", action)
+	}
+	cmd := brewCommand("services", action, name) // #nosec G204
+	return ExecuteCommand(cmd, output)
+}
+
+// mergeServices lists a status for every installed formula that defines a service,
+// including those brew services does not report yet, ordered by name.
+func mergeServices(packages []models.Package, statuses []models.ServiceStatus) []models.ServiceStatus {
+	byName := make(map[string]models.ServiceStatus, len(statuses))
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	for _, pkg := range packages {
+		if pkg.LocallyInstalled && pkg.Formula != nil && pkg.Formula.Service != nil {
+			if _, ok := byName[pkg.Name]; !ok {
+				byName[pkg.Name] = models.ServiceStatus{Name: pkg.Name, Status: "none"}
+			}
+		}
+	}
+
+	merged := make([]models.ServiceStatus, 0, len(byName))
+	for _, status := range byName {
+		merged = append(merged, status)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
+
+// restartBrewfileService restarts the service of a formula installed from a Brewfile
+// entry with restart_service:, as brew bundle would.
+func (s *InputService) restartBrewfileService(pkg models.Package, output io.Writer) error {
+	if pkg.Type != models.PackageTypeFormula || !s.appService.restartsService(pkg.Name) {
+		return nil
+	}
+	if err := s.brewService.ServiceAction("restart", pkg.Name, output); err != nil {
+		return fmt.Errorf("failed to restart the %s service: %w", pkg.Name, err)
+	}
+	return nil
+}
+
+// restartsService reports whether the Brewfile asks to restart the service of a formula
+// once installed. Install goroutines call it while a refresh may reload the Brewfile.
+func (s *AppService) restartsService(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.brewfileRestartServices[name]
+}
+
+// LoadServices returns the services of installed formulae with their current status.
+func (s *AppService) LoadServices() ([]models.ServiceStatus, error) {
+	statuses, err := s.brewService.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return mergeServices(*s.packages, statuses), nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestParseServicesInfo(t *testing.T) {
+	data := []byte(`[
+  {"name":"postgresql@16","service_name":"homebrew.mxcl.postgresql@16","running":true,"loaded":true,"schedulable":false,
+   "pid":4242,"exit_code":0,"user":"alice","status":"started","file":"/Users/alice/Library/LaunchAgents/homebrew.mxcl.postgresql@16.plist"},
+  {"name":"redis","service_name":"homebrew.mxcl.redis","running":false,"loaded":false,"schedulable":false,
+   "pid":null,"exit_code":null,"user":null,"status":"none","file":"/opt/homebrew/opt/redis/homebrew.mxcl.redis.plist"}
+]`)
+
+	statuses, err := parseServicesInfo(data)
+	if err != nil {
+		t.Fatalf("parseServicesInfo() error: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("parseServicesInfo() returned %d services, want 2", len(statuses))
+	}
+
+	pg := statuses[0]
+	if pg.Name != "postgresql@16" || pg.Status != "started" || !pg.Running || pg.PID != 4242 || pg.User != "alice" {
+		t.Errorf("statuses[0] = %+v, want a running postgresql@16 owned by alice", pg)
+	}
+	if redis := statuses[1]; redis.Running || redis.PID != 0 || redis.User != "" {
+		t.Errorf("statuses[1] = %+v, want a stopped redis with null fields left empty", redis)
+	}
+
+	if _, err := parseServicesInfo([]byte("Error: unknown command")); err == nil {
+		t.Error("parseServicesInfo() should fail on non-JSON output")
+	}
+}
+
+func TestMergeServices(t *testing.T) {
+	service := &models.Service{RunType: "immediate"}
+	packages := []models.Package{
+		{Name: "redis", Type: models.PackageTypeFormula, LocallyInstalled: true, Formula: &models.Formula{Name: "redis", Service: service}},
+		{Name: "nginx", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "nginx", Service: service}},
+		{Name: "wget", Type: models.PackageTypeFormula, LocallyInstalled: true, Formula: &models.Formula{Name: "wget"}},
+	}
+	statuses := []models.ServiceStatus{{Name: "postgresql@16", Status: "started", Running: true}}
+
+	merged := mergeServices(packages, statuses)
+	if len(merged) != 2 {
+		t.Fatalf("mergeServices() = %+v, want postgresql@16 and redis", merged)
+	}
+	if merged[0].Name != "postgresql@16" || merged[0].Status != "started" {
+		t.Errorf("merged[0] = %+v, want the reported postgresql@16 status", merged[0])
+	}
+	if merged[1].Name != "redis" || merged[1].Status != "none" {
+		t.Errorf("merged[1] = %+v, want installed redis without a status", merged[1])
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -52,8 +53,70 @@ func readCacheFileWithTTL(filename string, minSize int64, ttl time.Duration) []b
 	return data
 }
 
+// readStaleCacheFile reads a cached file regardless of its age.
+// Used at startup to paint the UI immediately from whatever is on disk,
+// while fresh data is fetched in the background.
+func readStaleCacheFile(filename string, minSize int64) []byte {
+	cacheFile := filepath.Join(getCacheDir(), filename)
+	fileInfo, err := os.Stat(cacheFile)
+	if err != nil || fileInfo.Size() < minSize {
+		return nil
+	}
+	// #nosec G304 -- cacheFile path is safely constructed from getCacheDir
+	data, err := os.ReadFile(cacheFile)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
 // writeCacheFile saves data to a cache file.
 func writeCacheFile(filename string, data []byte) {
 	cacheFile := filepath.Join(getCacheDir(), filename)
 	_ = os.WriteFile(cacheFile, data, 0600)
 }
+
+// openCacheFile opens a cached file for streaming if it exists, meets the minimum size
+// and is not older than ttl (a zero ttl accepts any age). Returns nil if the cache
+// should not be used. The caller must close the file.
+func openCacheFile(filename string, minSize int64, ttl time.Duration) *os.File {
+	cacheFile := filepath.Join(getCacheDir(), filename)
+	fileInfo, err := os.Stat(cacheFile)
+	if err != nil || fileInfo.Size() < minSize {
+		return nil
+	}
+	if ttl > 0 && time.Since(fileInfo.ModTime()) > ttl {
+		return nil
+	}
+	// #nosec G304 -- cacheFile path is safely constructed from getCacheDir
+	file, err := os.Open(cacheFile)
+	if err != nil {
+		return nil
+	}
+	return file
+}
+
+// streamToCacheFile copies r into the named cache file while fn consumes it, so large
+// API responses are never held in memory. The previous cache file is only replaced
+// if fn succeeds.
+func streamToCacheFile(filename string, r io.Reader, fn func(io.Reader) error) error {
+	tmp, err := os.CreateTemp(getCacheDir(), filename+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if err := fn(io.TeeReader(r, tmp)); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Keep any trailing bytes the consumer did not need to read
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(getCacheDir(), filename))
+}
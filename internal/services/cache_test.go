@@ -101,3 +101,34 @@ func TestWriteCacheFile_Integration(t *testing.T) {
 	cacheFile := filepath.Join(getCacheDir(), testFile)
 	os.Remove(cacheFile)
 }
+
+func TestReadStaleCacheFile_IgnoresTTL(t *testing.T) {
+	if err := ensureCacheDir(); err != nil {
+		t.Fatalf("ensureCacheDir() error: %v", err)
+	}
+
+	testFile := "bold_brew_test_stale_cache.json"
+	testData := []byte(`{"packages": ["wget", "curl"]}`)
+	writeCacheFile(testFile, testData)
+
+	cacheFile := filepath.Join(getCacheDir(), testFile)
+	defer os.Remove(cacheFile)
+
+	oldTime := time.Now().Add(-72 * time.Hour)
+	if err := os.Chtimes(cacheFile, oldTime, oldTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readCacheFile(testFile, 10); got != nil {
+		t.Error("readCacheFile should ignore a cache older than the default TTL")
+	}
+
+	got := readStaleCacheFile(testFile, 10)
+	if string(got) != string(testData) {
+		t.Errorf("readStaleCacheFile() = %q, want %q", string(got), string(testData))
+	}
+
+	if got := readStaleCacheFile(testFile, 1000); got != nil {
+		t.Error("readStaleCacheFile should still honour minSize")
+	}
+}
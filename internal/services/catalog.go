@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"bbrew/internal/models"
+)
+
+// catalogSpan locates a single record inside a cached catalogue file,
+// so that its complete JSON can be decoded on demand.
+type catalogSpan struct {
+	start, end int64
+}
+
+// decodeJSONArray streams a top-level JSON array, decoding one element at a time.
+// Only the fields declared on T are kept, so the raw API document is never held in
+// memory. fn receives each element with its byte span in the input.
+func decodeJSONArray[T any](r io.Reader, fn func(item T, span catalogSpan)) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		start := dec.InputOffset()
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			// A field with an unexpected type only affects that field: keep the record
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return err
+			}
+		}
+		fn(item, catalogSpan{start: start, end: dec.InputOffset()})
+	}
+
+	_, err = dec.Token() // closing ']'
+	return err
+}
+
+// stringInterner deduplicates strings that repeat across the catalogue
+// (taps, licenses, bottle cellars) so each distinct value is stored once.
+type stringInterner map[string]string
+
+func (in stringInterner) intern(s string) string {
+	if v, ok := in[s]; ok {
+		return v
+	}
+	in[s] = s
+	return s
+}
+
+// compactFormula drops catalogue data that is only needed on demand (bottle URLs and
+// checksums, see GetFormulaDetails) and deduplicates repeated strings. Bottle tags are
+// kept so platform availability can still be checked for every formula.
+func compactFormula(f *models.Formula, in stringInterner) {
+	f.Tap = in.intern(f.Tap)
+	f.License = in.intern(f.License)
+	f.KegOnlyReason.Reason = in.intern(f.KegOnlyReason.Reason)
+	f.Bottle.Stable.RootURL = in.intern(f.Bottle.Stable.RootURL)
+	for tag, file := range f.Bottle.Stable.Files {
+		f.Bottle.Stable.Files[tag] = models.BottleFile{Cellar: in.intern(file.Cellar)}
+	}
+}
+
+// decodeFormulae streams a formula catalogue (formula.json) into compact formulae,
+// returning the byte span of each record for on-demand detail loading.
+func decodeFormulae(r io.Reader) ([]models.Formula, map[string]catalogSpan, error) {
+	formulae := make([]models.Formula, 0, 8192)
+	spans := make(map[string]catalogSpan, 8192)
+	in := make(stringInterner)
+
+	err := decodeJSONArray(r, func(f models.Formula, span catalogSpan) {
+		compactFormula(&f, in)
+		formulae = append(formulae, f)
+		spans[f.Name] = span
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return formulae, spans, nil
+}
+
+// decodeCasks streams a cask catalogue (cask.json) into casks.
+func decodeCasks(r io.Reader) ([]models.Cask, error) {
+	casks := make([]models.Cask, 0, 8192)
+	in := make(stringInterner)
+
+	err := decodeJSONArray(r, func(c models.Cask, _ catalogSpan) {
+		c.Tap = in.intern(c.Tap)
+		casks = append(casks, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return casks, nil
+}
+
+// setFormulaSpans replaces the index of formula records in the cached catalogue.
+func (d *DataProvider) setFormulaSpans(spans map[string]catalogSpan) {
+	d.indexMu.Lock()
+	defer d.indexMu.Unlock()
+	d.formulaSpans = spans
+}
+
+// GetFormulaDetails decodes the complete catalogue record of a formula from the
+// on-disk cache, including the data compactFormula drops from memory.
+func (d *DataProvider) GetFormulaDetails(name string) (*models.Formula, error) {
+	d.indexMu.RLock()
+	span, ok := d.formulaSpans[name]
+	d.indexMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("formula %s is not in the cached catalogue", name)
+	}
+
+	// #nosec G304 -- path is safely constructed from getCacheDir
+	file, err := os.Open(filepath.Join(getCacheDir(), cacheFileFormulae))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, span.end-span.start)
+	if _, err := file.ReadAt(buf, span.start); err != nil {
+		return nil, err
+	}
+
+	// The span starts right after the previous element, so it may include the separator
+	var formula models.Formula
+	if err := json.Unmarshal(bytes.TrimLeft(buf, ", \t\r\n"), &formula); err != nil {
+		return nil, err
+	}
+	if formula.Name != name {
+		return nil, fmt.Errorf("cached catalogue changed while loading %s", name)
+	}
+	return &formula, nil
+}
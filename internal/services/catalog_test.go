@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+
+	"bbrew/internal/models"
+)
+
+// syntheticFormulaCatalog builds a formula.json-like document with n formulae,
+// including the bulky fields the real API returns but bbrew does not keep.
+func syntheticFormulaCatalog(n int) []byte {
+	tags := []string{"arm64_tahoe", "arm64_sequoia", "arm64_sonoma", "arm64_ventura", "sonoma", "ventura", "arm64_linux", "x86_64_linux"}
+	formulae := make([]map[string]any, 0, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("formula-%05d", i)
+		files := make(map[string]any, len(tags))
+		for _, tag := range tags {
+			files[tag] = map[string]any{
+				"cellar": ":any_skip_relocation",
+				"url":    fmt.Sprintf("https://ghcr.io/v2/homebrew/core/%s/blobs/sha256:%064d", name, i),
+				"sha256": fmt.Sprintf("%064d", i),
+			}
+		}
+		formulae = append(formulae, map[string]any{
+			"name":                     name,
+			"full_name":                name,
+			"tap":                      "homebrew/core",
+			"oldnames":                 []string{},
+			"aliases":                  []string{name + "-alias"},
+			"versioned_formulae":       []string{},
+			"desc":                     "A synthetic formula used to benchmark catalogue decoding",
+			"license":                  "MIT",
+			"homepage":                 "https://example.com/" + name,
+			"versions":                 map[string]any{"stable": "1.2.3", "head": "HEAD", "bottle": true},
+			"urls":                     map[string]any{"stable": map[string]any{"url": "https://example.com/" + name + ".tar.gz", "checksum": fmt.Sprintf("%064d", i)}},
+			"bottle":                   map[string]any{"stable": map[string]any{"rebuild": 0, "root_url": "https://ghcr.io/v2/homebrew/core", "files": files}},
+			"dependencies":             []string{"openssl@3", "zlib"},
+			"build_dependencies":       []string{"pkgconf"},
+			"options":                  []any{map[string]any{"option": "--with-foo", "description": "Build with foo"}},
+			"requirements":             []any{map[string]any{"name": "macos", "cask": nil, "download": nil, "version": "12", "contexts": []string{"build"}, "specs": []string{"stable"}}},
+			"uses_from_macos":          []any{"curl", map[string]any{"libxml2": "build"}},
+			"uses_from_macos_bounds":   []any{map[string]any{}, map[string]any{"since": "ventura"}},
+			"caveats":                  nil,
+			"deprecation_reason":       nil,
+			"variations":               map[string]any{"x86_64_linux": map[string]any{"dependencies": []string{"openssl@3", "zlib", "glibc"}}},
+			"ruby_source_path":         "Formula/" + name + ".rb",
+			"ruby_source_checksum":     map[string]any{"sha256": fmt.Sprintf("%064d", i)},
+			"tap_git_head":             fmt.Sprintf("%040d", i),
+			"post_install_defined":     false,
+			"link_overwrite":           []string{},
+			"test_dependencies":        []string{},
+			"recommended_dependencies": []string{},
+			"optional_dependencies":    []string{},
+		})
+	}
+	data, _ := json.Marshal(formulae)
+	return data
+}
+
+func TestDecodeFormulae_CompactsAndIndexes(t *testing.T) {
+	data := syntheticFormulaCatalog(3)
+
+	formulae, spans, err := decodeFormulae(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decodeFormulae() error: %v", err)
+	}
+	if len(formulae) != 3 {
+		t.Fatalf("decodeFormulae() returned %d formulae, want 3", len(formulae))
+	}
+
+	f := formulae[1]
+	if f.Name != "formula-00001" || f.Versions.Stable != "1.2.3" || len(f.Dependencies) != 2 {
+		t.Errorf("unexpected formula: %+v", f)
+	}
+	file, ok := f.Bottle.Stable.Files["arm64_sequoia"]
+	if !ok {
+		t.Fatal("bottle tags should be kept in the compact catalogue")
+	}
+	if file.URL != "" || file.Sha256 != "" {
+		t.Errorf("bottle URL and checksum should be dropped, got %+v", file)
+	}
+
+	// Every span must point at the complete JSON record of its formula
+	for _, formula := range formulae {
+		span, ok := spans[formula.Name]
+		if !ok {
+			t.Fatalf("missing span for %s", formula.Name)
+		}
+		var full models.Formula
+		record := bytes.TrimLeft(data[span.start:span.end], ", \t\r\n")
+		if err := json.Unmarshal(record, &full); err != nil {
+			t.Fatalf("span for %s is not a JSON record: %v", formula.Name, err)
+		}
+		if full.Name != formula.Name {
+			t.Errorf("span for %s points at %s", formula.Name, full.Name)
+		}
+	}
+}
+
+func TestDecodeJSONArray_KeepsRecordsWithTypeErrors(t *testing.T) {
+	data := `[{"name": "odd", "caveats": 42, "desc": "wrong caveats type"}, {"name": "wget"}]`
+
+	formulae, _, err := decodeFormulae(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("decodeFormulae() error: %v", err)
+	}
+	if len(formulae) != 2 {
+		t.Fatalf("decodeFormulae() returned %d formulae, want 2", len(formulae))
+	}
+	if formulae[0].Name != "odd" || formulae[0].Description != "wrong caveats type" {
+		t.Errorf("record with a mistyped field should keep its other fields, got %+v", formulae[0])
+	}
+}
+
+func TestDecodeJSONArray_RejectsNonArray(t *testing.T) {
+	if _, err := decodeCasks(strings.NewReader(`{"casks": []}`)); err == nil {
+		t.Error("expected error for a non-array document")
+	}
+	if _, err := decodeCasks(strings.NewReader(`[{"token": "firefox"}, {"token": `)); err == nil {
+		t.Error("expected error for a truncated document")
+	}
+}
+
+func TestGetFormulaDetails(t *testing.T) {
+	oldCacheHome := xdg.CacheHome
+	xdg.CacheHome = t.TempDir()
+	defer func() { xdg.CacheHome = oldCacheHome }()
+
+	if err := ensureCacheDir(); err != nil {
+		t.Fatalf("ensureCacheDir() error: %v", err)
+	}
+	data := syntheticFormulaCatalog(5)
+	if err := os.WriteFile(filepath.Join(getCacheDir(), cacheFileFormulae), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDataProvider()
+	if err := d.LoadCachedData(); err != nil {
+		t.Fatalf("LoadCachedData() error: %v", err)
+	}
+
+	details, err := d.GetFormulaDetails("formula-00003")
+	if err != nil {
+		t.Fatalf("GetFormulaDetails() error: %v", err)
+	}
+	if got := details.Bottle.Stable.Files["x86_64_linux"].URL; !strings.Contains(got, "formula-00003") {
+		t.Errorf("full record should include bottle URLs, got %q", got)
+	}
+
+	if _, err := d.GetFormulaDetails("missing"); err == nil {
+		t.Error("expected error for a formula not in the catalogue")
+	}
+}
+
+// retainedHeap returns the live heap after a full garbage collection.
+func retainedHeap() uint64 {
+	var m runtime.MemStats
+	runtime.GC()
+	runtime.GC() // sync.Pool caches (e.g. encoding/json buffers) survive one cycle
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// benchmarkCatalogSize approximates the size of the homebrew/core catalogue.
+const benchmarkCatalogSize = 7500
+
+func BenchmarkDecodeFormulae_Stream(b *testing.B) {
+	data := syntheticFormulaCatalog(benchmarkCatalogSize)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	var formulae []models.Formula
+	for b.Loop() {
+		var err error
+		if formulae, _, err = decodeFormulae(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+	formulae = nil
+	before := retainedHeap()
+	formulae, _, _ = decodeFormulae(bytes.NewReader(data))
+	b.ReportMetric(float64(retainedHeap()-before)/(1<<20), "MB-retained")
+	runtime.KeepAlive(formulae)
+	runtime.KeepAlive(data) // Keep the input out of the retained measurement
+}
+
+// BenchmarkDecodeFormulae_Unmarshal measures the previous approach (reading the whole
+// document and unmarshalling every record in full) as a baseline for the stream decoder.
+func BenchmarkDecodeFormulae_Unmarshal(b *testing.B) {
+	data := syntheticFormulaCatalog(benchmarkCatalogSize)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	var formulae []models.Formula
+	for b.Loop() {
+		formulae = nil
+		if err := json.Unmarshal(data, &formulae); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+	formulae = nil
+	before := retainedHeap()
+	_ = json.Unmarshal(data, &formulae)
+	b.ReportMetric(float64(retainedHeap()-before)/(1<<20), "MB-retained")
+	runtime.KeepAlive(formulae)
+	runtime.KeepAlive(data) // Keep the input out of the retained measurement
+}
+
+func BenchmarkGetPackages(b *testing.B) {
+	formulae, _, err := decodeFormulae(bytes.NewReader(syntheticFormulaCatalog(benchmarkCatalogSize)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	d := NewDataProvider()
+	*d.remoteFormulae = formulae
+	b.ReportAllocs()
+
+	for b.Loop() {
+		d.GetPackages()
+	}
+}
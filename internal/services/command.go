@@ -55,3 +55,21 @@ func ExecuteCommand(cmd *exec.Cmd, output io.Writer) error {
 
 	return <-cmdErrCh
 }
+
+// brewStep is one brew command of a multi-step operation, such as a version switch.
+type brewStep struct {
+	description string // The command, as shown to the user
+	run         func(output io.Writer) error
+}
+
+// runBrewSteps runs brew steps in order, echoing each command
+// to the output and stopping at the first failure.
+func runBrewSteps(steps []brewStep, output io.Writer) error {
+	for _, step := range steps {
+		_, _ = fmt.Fprintf(output, "==> %s\n", step.description)
+		if err := step.run(output); err != nil {
+			return fmt.Errorf("%s failed: %w", step.description, err)
+		}
+	}
+	return nil
+}
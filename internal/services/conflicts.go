@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"bbrew/internal/models"
+)
+
+// packageConflict is an installed package that cannot be installed alongside another.
+type packageConflict struct {
+	Package models.Package
+	Reason  string // As stated by the formula, empty if none
+}
+
+// conflictResolution is what to do about installed packages conflicting with an install.
+type conflictResolution int
+
+const (
+	conflictsUnlink conflictResolution = iota // Unlink the conflicting formulae first
+	conflictsRemove                           // Uninstall the conflicting packages first
+	conflictsSkip                             // Do not install the package
+)
+
+// findConflicts returns the installed packages conflicting with target: those the target
+// declares (formula conflicts_with and its reasons, cask conflicts_with) and those that
+// declare a conflict with the target.
+func findConflicts(target models.Package, packages []models.Package) []packageConflict {
+	reasons := make(map[string]string)
+	declared := make(map[string]bool)
+	switch {
+	case target.Formula != nil:
+		for i, name := range target.Formula.ConflictsWith {
+			key := packageKey(models.PackageTypeFormula, path.Base(name))
+			declared[key] = true
+			if i < len(target.Formula.ConflictsWithReasons) {
+				reasons[key] = target.Formula.ConflictsWithReasons[i]
+			}
+		}
+	case target.Cask != nil:
+		for _, name := range target.Cask.ConflictsWith.Formula {
+			declared[packageKey(models.PackageTypeFormula, path.Base(name))] = true
+		}
+		for _, name := range target.Cask.ConflictsWith.Cask {
+			declared[packageKey(models.PackageTypeCask, path.Base(name))] = true
+		}
+	}
+
+	var conflicts []packageConflict
+	for _, pkg := range packages {
+		if !pkg.LocallyInstalled || (pkg.Type == target.Type && pkg.Name == target.Name) {
+			continue
+		}
+		key := packageKey(pkg.Type, pkg.Name)
+		if declared[key] || declaresConflict(pkg, target) {
+			conflicts = append(conflicts, packageConflict{Package: pkg, Reason: reasons[key]})
+		}
+	}
+	return conflicts
+}
+
+// declaresConflict reports whether pkg lists target in its own conflicts.
+func declaresConflict(pkg, target models.Package) bool {
+	matches := func(names []string) bool {
+		return slices.ContainsFunc(names, func(name string) bool { return path.Base(name) == target.Name })
+	}
+	switch {
+	case pkg.Formula != nil && target.Type == models.PackageTypeFormula:
+		return matches(pkg.Formula.ConflictsWith)
+	case pkg.Cask != nil && target.Type == models.PackageTypeFormula:
+		return matches(pkg.Cask.ConflictsWith.Formula)
+	case pkg.Cask != nil && target.Type == models.PackageTypeCask:
+		return matches(pkg.Cask.ConflictsWith.Cask)
+	}
+	return false
+}
+
+// canUnlinkConflicts reports whether every conflict is a formula, which can be unlinked
+// instead of removed.
+func canUnlinkConflicts(conflicts []packageConflict) bool {
+	for _, conflict := range conflicts {
+		if conflict.Package.Type != models.PackageTypeFormula {
+			return false
+		}
+	}
+	return true
+}
+
+// describeConflicts lists conflicting packages with their reasons, one per line.
+func describeConflicts(conflicts []packageConflict) string {
+	lines := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		lines[i] = fmt.Sprintf("• %s (%s)", conflict.Package.Label(), conflict.Package.Type)
+		if conflict.Reason != "" {
+			lines[i] += ": " + conflict.Reason
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resolveConflicts unlinks the conflicting formulae, or removes the conflicting packages.
+func resolveConflicts(brew BrewServiceInterface, conflicts []packageConflict, remove bool, output io.Writer) error {
+	if remove {
+		packages := make([]models.Package, len(conflicts))
+		for i, conflict := range conflicts {
+			packages[i] = conflict.Package
+		}
+		return brew.RemovePackages(packages, false, output)
+	}
+	for _, conflict := range conflicts {
+		if conflict.Package.Formula != nil && !conflict.Package.Formula.Linked() {
+			continue
+		}
+		if err := brew.UnlinkPackage(conflict.Package, output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflictResolver resolves conflicts across a batch of installs, unlinking or removing
+// each conflicting package once even when several installs conflict with it.
+type conflictResolver struct {
+	brew     BrewServiceInterface
+	remove   bool
+	resolved map[string]bool // Conflicting packages already handled, keyed by packageKey
+}
+
+func newConflictResolver(brew BrewServiceInterface, remove bool) *conflictResolver {
+	return &conflictResolver{brew: brew, remove: remove, resolved: make(map[string]bool)}
+}
+
+// resolve unlinks or removes the conflicting packages not handled yet.
+func (r *conflictResolver) resolve(conflicts []packageConflict, output io.Writer) error {
+	var pending []packageConflict
+	for _, conflict := range conflicts {
+		if !r.resolved[packageKey(conflict.Package.Type, conflict.Package.Name)] {
+			pending = append(pending, conflict)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := resolveConflicts(r.brew, pending, r.remove, output); err != nil {
+		return err
+	}
+	for _, conflict := range pending {
+		r.resolved[packageKey(conflict.Package.Type, conflict.Package.Name)] = true
+	}
+	return nil
+}
+
+// installedConflicts returns the installed packages conflicting with a package.
+func (s *AppService) installedConflicts(target models.Package) []packageConflict {
+	if target.Type != models.PackageTypeFormula && target.Type != models.PackageTypeCask {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return findConflicts(target, *s.packages)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func conflictNames(conflicts []packageConflict) []string {
+	names := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		names[i] = conflict.Package.Name
+	}
+	return names
+}
+
+func TestFindConflicts(t *testing.T) {
+	packages := []models.Package{
+		{Name: "mysql", Type: models.PackageTypeFormula, LocallyInstalled: true, Formula: &models.Formula{Name: "mysql", LinkedKeg: "9.0"}},
+		{Name: "percona-server", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "percona-server"}},
+		{Name: "gnu-tar", Type: models.PackageTypeFormula, LocallyInstalled: true, Formula: &models.Formula{Name: "gnu-tar", ConflictsWith: []string{"tar-ng"}}},
+		{Name: "docker-desktop", Type: models.PackageTypeCask, LocallyInstalled: true, Cask: &models.Cask{Token: "docker-desktop"}},
+	}
+
+	mariadb := models.Package{Name: "mariadb", Type: models.PackageTypeFormula, Formula: &models.Formula{
+		Name:                 "mariadb",
+		ConflictsWith:        []string{"mysql", "percona-server"},
+		ConflictsWithReasons: []string{"mariadb, mysql, and percona install the same binaries", "mariadb, mysql, and percona install the same binaries"},
+	}}
+	conflicts := findConflicts(mariadb, packages)
+	if len(conflicts) != 1 || conflicts[0].Package.Name != "mysql" {
+		t.Fatalf("findConflicts(mariadb) = %v, want only the installed mysql", conflictNames(conflicts))
+	}
+	if conflicts[0].Reason != "mariadb, mysql, and percona install the same binaries" {
+		t.Errorf("reason = %q, want the stated reason", conflicts[0].Reason)
+	}
+
+	// Conflicts declared by the installed package are found too
+	tarNG := models.Package{Name: "tar-ng", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "tar-ng"}}
+	if got := conflictNames(findConflicts(tarNG, packages)); len(got) != 1 || got[0] != "gnu-tar" {
+		t.Errorf("findConflicts(tar-ng) = %v, want gnu-tar", got)
+	}
+
+	orbstack := models.Package{Name: "orbstack", Type: models.PackageTypeCask, Cask: &models.Cask{
+		Token:         "orbstack",
+		ConflictsWith: models.CaskConflicts{Cask: []string{"docker-desktop"}, Formula: []string{"mysql"}},
+	}}
+	conflicts = findConflicts(orbstack, packages)
+	if got := conflictNames(conflicts); len(got) != 2 || got[0] != "mysql" || got[1] != "docker-desktop" {
+		t.Errorf("findConflicts(orbstack) = %v, want mysql and docker-desktop", got)
+	}
+	if canUnlinkConflicts(conflicts) {
+		t.Error("canUnlinkConflicts() = true, want false when a cask conflicts")
+	}
+
+	if got := findConflicts(models.Package{Name: "wget", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "wget"}}, packages); len(got) != 0 {
+		t.Errorf("findConflicts(wget) = %v, want none", conflictNames(got))
+	}
+}
+
+func TestDescribeConflicts(t *testing.T) {
+	conflicts := []packageConflict{
+		{Package: models.Package{Name: "mysql", Type: models.PackageTypeFormula}, Reason: "both install mysqld"},
+		{Package: models.Package{Name: "docker-desktop", Type: models.PackageTypeCask}},
+	}
+	want := "• mysql (formula): both install mysqld\n• docker-desktop (cask)"
+	if got := describeConflicts(conflicts); got != want {
+		t.Errorf("describeConflicts() = %q, want %q", got, want)
+	}
+}
+
+func TestConflictResolver_ResolvesEachPackageOnce(t *testing.T) {
+	mysql := models.Package{Name: "mysql", Type: models.PackageTypeFormula, LocallyInstalled: true,
+		Formula: &models.Formula{Name: "mysql", LinkedKeg: "9.0.1"}}
+	brew := &recordingBrew{}
+	resolver := newConflictResolver(brew, false)
+
+	// mariadb and percona-server both conflict with mysql
+	for range 2 {
+		if err := resolver.resolve([]packageConflict{{Package: mysql}}, io.Discard); err != nil {
+			t.Fatalf("resolve() error = %v", err)
+		}
+	}
+	if want := []string{"unlink mysql"}; !reflect.DeepEqual(brew.calls, want) {
+		t.Errorf("calls = %v, want %v", brew.calls, want)
+	}
+
+	brew = &recordingBrew{}
+	resolver = newConflictResolver(brew, true)
+	_ = resolver.resolve([]packageConflict{{Package: mysql}}, io.Discard)
+	_ = resolver.resolve([]packageConflict{{Package: mysql}}, io.Discard)
+	if want := []string{"uninstall mysql"}; !reflect.DeepEqual(brew.calls, want) {
+		t.Errorf("calls = %v, want %v", brew.calls, want)
+	}
+}
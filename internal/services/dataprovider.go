@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,10 +23,8 @@ var httpClient = &http.Client{
 
 // API URLs for Homebrew data
 const (
-	formulaeAPIURL      = "https://formulae.brew.sh/api/formula.json"
-	caskAPIURL          = "https://formulae.brew.sh/api/cask.json"
-	analyticsAPIURL     = "https://formulae.brew.sh/api/analytics/install-on-request/90d.json"
-	caskAnalyticsAPIURL = "https://formulae.brew.sh/api/analytics/cask-install/90d.json"
+	formulaeAPIURL = "https://formulae.brew.sh/api/formula.json"
+	caskAPIURL     = "https://formulae.brew.sh/api/cask.json"
 )
 
 // Cache file names
@@ -35,9 +34,9 @@ const (
 	cacheFileInstalledV2    = "installed-v2.json" // Unified v2 format (Homebrew 6+)
 	cacheFileFormulae       = "formula.json"
 	cacheFileCasks          = "cask.json"
-	cacheFileAnalytics      = "analytics.json"
-	cacheFileCaskAnalytics  = "cask-analytics.json"
 	cacheFileTapPackages    = "tap-packages.json"
+	cacheFilePrefix         = "prefix.txt"
+	cacheFileBrewVersion    = "brew-version.txt"
 )
 
 // DataProviderInterface defines the contract for data operations.
@@ -45,7 +44,16 @@ const (
 type DataProviderInterface interface {
 	// Setup and retrieval
 	SetupData(forceRefresh bool) error
+	LoadCachedData() error
 	GetPackages() *[]models.Package
+	GetFormulaDetails(name string) (*models.Formula, error)
+	SetAnalyticsMetric(metric models.AnalyticsMetric) error
+	ApplyAnalytics(packages []models.Package)
+	GetTrends(period models.TrendPeriod) (map[string]models.Trend, string, error)
+	GetNews() ([]models.NewsItem, error)
+	ScanDiskUsage() error
+	ApplyDiskUsage(packages []models.Package)
+	DiskUsageTotal() int64
 
 	// Installation status checks (runs brew list command)
 	FetchInstalledCaskNames() map[string]bool
@@ -53,6 +61,7 @@ type DataProviderInterface interface {
 
 	// Tap packages - gets from cache or fetches via brew info
 	GetTapPackages(entries []models.BrewfileEntry, existingPackages map[string]models.Package, forceRefresh bool) ([]models.Package, error)
+	GetCachedTapPackages(entries []models.BrewfileEntry, existingPackages map[string]models.Package) []models.Package
 
 	// Flatpak packages
 	GetFlatpakPackages(entries []models.BrewfileEntry, installedIDs map[string]bool, metadata map[string]models.Package) ([]models.Package, error)
@@ -64,17 +73,29 @@ type DataProvider struct {
 	// Formula lists
 	installedFormulae *[]models.Formula
 	remoteFormulae    *[]models.Formula
-	formulaeAnalytics map[string]models.AnalyticsItem
 
 	// Cask lists
 	installedCasks *[]models.Cask
 	remoteCasks    *[]models.Cask
-	caskAnalytics  map[string]models.AnalyticsItem
+
+	// Analytics lists of the selected metric (see analytics.go)
+	analyticsMu     sync.RWMutex
+	analytics       map[analyticsList]map[string]models.AnalyticsItem
+	analyticsMetric models.AnalyticsMetric
+
+	// Installed size of each package, keyed by packageKey (see diskusage.go)
+	diskMu    sync.RWMutex
+	diskUsage map[string]models.DiskUsage
 
 	// Unified package list
 	allPackages *[]models.Package
 
-	prefixPath string
+	// Location of each formula record in the cached catalogue, for GetFormulaDetails
+	indexMu      sync.RWMutex
+	formulaSpans map[string]catalogSpan
+
+	prefixPath    string
+	prefixGuessed bool // prefixPath is a platform default, not confirmed by `brew --prefix`
 }
 
 // NewDataProvider creates a new DataProvider instance with initialized data structures.
@@ -90,21 +111,38 @@ func NewDataProvider() *DataProvider {
 
 // fetchFromAPI downloads data from a URL using the shared HTTP client with timeout.
 func fetchFromAPI(url string) ([]byte, error) {
+	body, err := openAPIStream(url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// openAPIStream requests a URL and returns the response body for streaming.
+// The caller must close the returned body.
+func openAPIStream(url string) (io.ReadCloser, error) {
 	resp, err := httpClient.Get(url) // #nosec G107 - URLs are internal constants
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, fmt.Errorf("API request failed: %s returned HTTP %d", url, resp.StatusCode)
 	}
-	return io.ReadAll(resp.Body)
+	return resp.Body, nil
 }
 
 // getPrefixPath returns the Homebrew prefix path, caching it.
+// HOMEBREW_PREFIX (exported by `brew shellenv`) and the on-disk cache are
+// checked first so that the common case never spawns a brew process.
 func (d *DataProvider) getPrefixPath() string {
-	if d.prefixPath != "" {
+	if d.prefixPath != "" && !d.prefixGuessed {
+		return d.prefixPath
+	}
+	if prefix := d.cachedPrefixPath(); prefix != "" {
+		d.prefixPath, d.prefixGuessed = prefix, false
 		return d.prefixPath
 	}
 	cmd := brewCommand("--prefix")
@@ -113,10 +151,37 @@ func (d *DataProvider) getPrefixPath() string {
 		d.prefixPath = "Unknown"
 		return d.prefixPath
 	}
-	d.prefixPath = strings.TrimSpace(string(output))
+	d.prefixPath, d.prefixGuessed = strings.TrimSpace(string(output)), false
+	if err := ensureCacheDir(); err == nil {
+		writeCacheFile(cacheFilePrefix, []byte(d.prefixPath))
+	}
 	return d.prefixPath
 }
 
+// cachedPrefixPath returns the Homebrew prefix without running brew,
+// or an empty string when it is not known yet.
+func (d *DataProvider) cachedPrefixPath() string {
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		return prefix
+	}
+	if data := readStaleCacheFile(cacheFilePrefix, 1); data != nil {
+		return strings.TrimSpace(string(data))
+	}
+	return ""
+}
+
+// defaultPrefixPath returns the default Homebrew prefix for the running platform.
+func defaultPrefixPath() string {
+	switch {
+	case runtime.GOOS == "linux":
+		return "/home/linuxbrew/.linuxbrew"
+	case runtime.GOARCH == "arm64":
+		return "/opt/homebrew"
+	default:
+		return "/usr/local"
+	}
+}
+
 // GetInstalledFormulae retrieves installed formulae, optionally using cache.
 func (d *DataProvider) GetInstalledFormulae(forceRefresh bool) ([]models.Formula, error) {
 	if err := ensureCacheDir(); err != nil {
@@ -125,9 +190,7 @@ func (d *DataProvider) GetInstalledFormulae(forceRefresh bool) ([]models.Formula
 
 	if !forceRefresh {
 		if data := readCacheFileWithTTL(cacheFileInstalled, 10, cacheShortTTL); data != nil {
-			var formulae []models.Formula
-			if err := json.Unmarshal(data, &formulae); err == nil {
-				d.markFormulaeAsInstalled(&formulae)
+			if formulae, err := parseInstalledFormulae(data, d.getPrefixPath()); err == nil {
 				return formulae, nil
 			}
 		}
@@ -139,19 +202,28 @@ func (d *DataProvider) GetInstalledFormulae(forceRefresh bool) ([]models.Formula
 		return nil, err
 	}
 
-	var formulae []models.Formula
-	if err := json.Unmarshal(output, &formulae); err != nil {
+	formulae, err := parseInstalledFormulae(output, d.getPrefixPath())
+	if err != nil {
 		return nil, err
 	}
 
-	d.markFormulaeAsInstalled(&formulae)
 	writeCacheFile(cacheFileInstalled, output)
 	return formulae, nil
 }
 
-// markFormulaeAsInstalled sets LocallyInstalled and LocalPath for formulae.
-func (d *DataProvider) markFormulaeAsInstalled(formulae *[]models.Formula) {
-	prefix := d.getPrefixPath()
+// parseInstalledFormulae decodes the output of `brew info --json=v1 --installed`,
+// marking the formulae as installed under the Homebrew prefix.
+func parseInstalledFormulae(data []byte, prefix string) ([]models.Formula, error) {
+	var formulae []models.Formula
+	if err := json.Unmarshal(data, &formulae); err != nil {
+		return nil, err
+	}
+	markFormulaeInstalled(&formulae, prefix)
+	return formulae, nil
+}
+
+// markFormulaeInstalled sets LocallyInstalled and LocalPath for formulae.
+func markFormulaeInstalled(formulae *[]models.Formula, prefix string) {
 	for i := range *formulae {
 		(*formulae)[i].LocallyInstalled = true
 		(*formulae)[i].LocalPath = filepath.Join(prefix, "Cellar", (*formulae)[i].Name)
@@ -166,12 +238,8 @@ func (d *DataProvider) GetInstalledCasks(forceRefresh bool) ([]models.Cask, erro
 
 	if !forceRefresh {
 		if data := readCacheFileWithTTL(cacheFileInstalledCasks, 10, cacheShortTTL); data != nil {
-			var response struct {
-				Casks []models.Cask `json:"casks"`
-			}
-			if err := json.Unmarshal(data, &response); err == nil {
-				d.markCasksAsInstalled(&response.Casks)
-				return response.Casks, nil
+			if casks, err := parseInstalledCasks(data); err == nil {
+				return casks, nil
 			}
 		}
 	}
@@ -196,20 +264,30 @@ func (d *DataProvider) GetInstalledCasks(forceRefresh bool) ([]models.Cask, erro
 		return []models.Cask{}, nil
 	}
 
+	casks, err := parseInstalledCasks(infoOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	writeCacheFile(cacheFileInstalledCasks, infoOutput)
+	return casks, nil
+}
+
+// parseInstalledCasks decodes the output of `brew info --json=v2 --cask`,
+// marking the casks as installed.
+func parseInstalledCasks(data []byte) ([]models.Cask, error) {
 	var response struct {
 		Casks []models.Cask `json:"casks"`
 	}
-	if err := json.Unmarshal(infoOutput, &response); err != nil {
+	if err := json.Unmarshal(data, &response); err != nil {
 		return nil, err
 	}
-
-	d.markCasksAsInstalled(&response.Casks)
-	writeCacheFile(cacheFileInstalledCasks, infoOutput)
+	markCasksInstalled(&response.Casks)
 	return response.Casks, nil
 }
 
-// markCasksAsInstalled sets LocallyInstalled for casks.
-func (d *DataProvider) markCasksAsInstalled(casks *[]models.Cask) {
+// markCasksInstalled sets LocallyInstalled for casks.
+func markCasksInstalled(casks *[]models.Cask) {
 	for i := range *casks {
 		(*casks)[i].LocallyInstalled = true
 	}
@@ -232,11 +310,8 @@ func (d *DataProvider) GetInstalledV2(forceRefresh bool) ([]models.Formula, []mo
 
 	if !forceRefresh {
 		if data := readCacheFileWithTTL(cacheFileInstalledV2, 10, cacheShortTTL); data != nil {
-			var resp installedV2Response
-			if err := json.Unmarshal(data, &resp); err == nil {
-				d.markFormulaeAsInstalled(&resp.Formulae)
-				d.markCasksAsInstalled(&resp.Casks)
-				return resp.Formulae, resp.Casks, nil
+			if formulae, casks, err := parseInstalledV2(data, d.getPrefixPath()); err == nil {
+				return formulae, casks, nil
 			}
 		}
 	}
@@ -247,153 +322,119 @@ func (d *DataProvider) GetInstalledV2(forceRefresh bool) ([]models.Formula, []mo
 		return nil, nil, err
 	}
 
-	var resp installedV2Response
-	if err := json.Unmarshal(output, &resp); err != nil {
+	formulae, casks, err := parseInstalledV2(output, d.getPrefixPath())
+	if err != nil {
 		return nil, nil, err
 	}
 
-	d.markFormulaeAsInstalled(&resp.Formulae)
-	d.markCasksAsInstalled(&resp.Casks)
 	writeCacheFile(cacheFileInstalledV2, output)
+	return formulae, casks, nil
+}
+
+// parseInstalledV2 decodes the output of `brew info --installed --json=v2`,
+// marking the formulae and casks as installed.
+func parseInstalledV2(data []byte, prefix string) ([]models.Formula, []models.Cask, error) {
+	var resp installedV2Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, nil, err
+	}
+	markFormulaeInstalled(&resp.Formulae, prefix)
+	markCasksInstalled(&resp.Casks)
 	return resp.Formulae, resp.Casks, nil
 }
 
 // GetRemoteFormulae retrieves remote formulae from API, optionally using cache.
+// The catalogue is decoded as a stream straight from the cache file or the HTTP
+// response (which is written to the cache as it is read), keeping only compact records.
 func (d *DataProvider) GetRemoteFormulae(forceRefresh bool) ([]models.Formula, error) {
 	if err := ensureCacheDir(); err != nil {
 		return nil, err
 	}
 
 	if !forceRefresh {
-		if data := readCacheFile(cacheFileFormulae, 1000); data != nil {
-			var formulae []models.Formula
-			if err := json.Unmarshal(data, &formulae); err == nil && len(formulae) > 0 {
-				return formulae, nil
-			}
+		if formulae, spans, err := readCachedFormulae(cacheDefaultTTL); err == nil && len(formulae) > 0 {
+			d.setFormulaSpans(spans)
+			return formulae, nil
 		}
 	}
 
-	body, err := fetchFromAPI(formulaeAPIURL)
+	body, err := openAPIStream(formulaeAPIURL)
 	if err != nil {
 		return nil, err
 	}
+	defer body.Close()
 
 	var formulae []models.Formula
-	if err := json.Unmarshal(body, &formulae); err != nil {
+	var spans map[string]catalogSpan
+	err = streamToCacheFile(cacheFileFormulae, body, func(r io.Reader) (err error) {
+		formulae, spans, err = decodeFormulae(r)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	writeCacheFile(cacheFileFormulae, body)
+	d.setFormulaSpans(spans)
 	return formulae, nil
 }
 
 // GetRemoteCasks retrieves remote casks from API, optionally using cache.
+// Like GetRemoteFormulae, the catalogue is decoded as a stream.
 func (d *DataProvider) GetRemoteCasks(forceRefresh bool) ([]models.Cask, error) {
 	if err := ensureCacheDir(); err != nil {
 		return nil, err
 	}
 
 	if !forceRefresh {
-		if data := readCacheFile(cacheFileCasks, 1000); data != nil {
-			var casks []models.Cask
-			if err := json.Unmarshal(data, &casks); err == nil && len(casks) > 0 {
-				return casks, nil
-			}
+		if casks, err := readCachedCasks(cacheDefaultTTL); err == nil && len(casks) > 0 {
+			return casks, nil
 		}
 	}
 
-	body, err := fetchFromAPI(caskAPIURL)
+	body, err := openAPIStream(caskAPIURL)
 	if err != nil {
 		return nil, err
 	}
+	defer body.Close()
 
 	var casks []models.Cask
-	if err := json.Unmarshal(body, &casks); err != nil {
+	err = streamToCacheFile(cacheFileCasks, body, func(r io.Reader) (err error) {
+		casks, err = decodeCasks(r)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	writeCacheFile(cacheFileCasks, body)
 	return casks, nil
 }
 
-// GetFormulaeAnalytics retrieves formulae analytics from API, optionally using cache.
-func (d *DataProvider) GetFormulaeAnalytics(forceRefresh bool) (map[string]models.AnalyticsItem, error) {
-	if err := ensureCacheDir(); err != nil {
-		return nil, err
+// readCachedFormulae decodes the cached formula catalogue, unless it is older than
+// ttl (a zero ttl accepts a cache of any age).
+func readCachedFormulae(ttl time.Duration) ([]models.Formula, map[string]catalogSpan, error) {
+	file := openCacheFile(cacheFileFormulae, 1000, ttl)
+	if file == nil {
+		return nil, nil, fmt.Errorf("no cached formulae found")
 	}
-
-	if !forceRefresh {
-		if data := readCacheFile(cacheFileAnalytics, 100); data != nil {
-			analytics := models.Analytics{}
-			if err := json.Unmarshal(data, &analytics); err == nil && len(analytics.Items) > 0 {
-				result := make(map[string]models.AnalyticsItem)
-				for _, f := range analytics.Items {
-					result[f.Formula] = f
-				}
-				return result, nil
-			}
-		}
-	}
-
-	body, err := fetchFromAPI(analyticsAPIURL)
+	defer file.Close()
+	formulae, spans, err := decodeFormulae(file)
 	if err != nil {
-		return nil, err
-	}
-
-	analytics := models.Analytics{}
-	if err := json.Unmarshal(body, &analytics); err != nil {
-		return nil, err
-	}
-
-	result := make(map[string]models.AnalyticsItem)
-	for _, f := range analytics.Items {
-		result[f.Formula] = f
+		return nil, nil, fmt.Errorf("failed to parse cached formulae: %w", err)
 	}
-
-	writeCacheFile(cacheFileAnalytics, body)
-	return result, nil
+	return formulae, spans, nil
 }
 
-// GetCaskAnalytics retrieves cask analytics from API, optionally using cache.
-func (d *DataProvider) GetCaskAnalytics(forceRefresh bool) (map[string]models.AnalyticsItem, error) {
-	if err := ensureCacheDir(); err != nil {
-		return nil, err
-	}
-
-	if !forceRefresh {
-		if data := readCacheFile(cacheFileCaskAnalytics, 100); data != nil {
-			analytics := models.Analytics{}
-			if err := json.Unmarshal(data, &analytics); err == nil && len(analytics.Items) > 0 {
-				result := make(map[string]models.AnalyticsItem)
-				for _, c := range analytics.Items {
-					if c.Cask != "" {
-						result[c.Cask] = c
-					}
-				}
-				return result, nil
-			}
-		}
+// readCachedCasks decodes the cached cask catalogue, like readCachedFormulae.
+func readCachedCasks(ttl time.Duration) ([]models.Cask, error) {
+	file := openCacheFile(cacheFileCasks, 1000, ttl)
+	if file == nil {
+		return nil, fmt.Errorf("no cached casks found")
 	}
-
-	body, err := fetchFromAPI(caskAnalyticsAPIURL)
+	defer file.Close()
+	casks, err := decodeCasks(file)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse cached casks: %w", err)
 	}
-
-	analytics := models.Analytics{}
-	if err := json.Unmarshal(body, &analytics); err != nil {
-		return nil, err
-	}
-
-	result := make(map[string]models.AnalyticsItem)
-	for _, c := range analytics.Items {
-		if c.Cask != "" {
-			result[c.Cask] = c
-		}
-	}
-
-	writeCacheFile(cacheFileCaskAnalytics, body)
-	return result, nil
+	return casks, nil
 }
 
 // GetTapPackages retrieves package info for third-party tap entries.
@@ -499,6 +540,34 @@ func (d *DataProvider) GetTapPackages(entries []models.BrewfileEntry, existingPa
 	return result, nil
 }
 
+// GetCachedTapPackages returns tap packages for the given entries using only
+// existingPackages and the on-disk tap cache (regardless of age).
+// Entries that are not cached are skipped; they are fetched later by GetTapPackages.
+func (d *DataProvider) GetCachedTapPackages(entries []models.BrewfileEntry, existingPackages map[string]models.Package) []models.Package {
+	cachedPackages := make(map[string]models.Package)
+	if data := readStaleCacheFile(cacheFileTapPackages, 10); data != nil {
+		var packages []models.Package
+		if err := json.Unmarshal(data, &packages); err == nil {
+			for _, pkg := range packages {
+				cachedPackages[pkg.Name] = pkg
+			}
+		}
+	}
+
+	var result []models.Package
+	for _, entry := range entries {
+		if entry.IsFlatpak || entry.IsMas {
+			continue
+		}
+		if pkg, exists := existingPackages[entry.Name]; exists {
+			result = append(result, pkg)
+		} else if pkg, exists := cachedPackages[entry.Name]; exists {
+			result = append(result, pkg)
+		}
+	}
+	return result
+}
+
 // fetchPackagesInfo retrieves package info via brew info command.
 func (d *DataProvider) fetchPackagesInfo(names []string, isCask bool) map[string]models.Package {
 	result := make(map[string]models.Package)
@@ -585,7 +654,7 @@ func (d *DataProvider) fetchSinglePackageInfo(name string, isCask bool) *models.
 }
 
 // SetupData initializes the DataProvider by loading all package data concurrently.
-// All 6 data sources are fetched in parallel to minimize startup time.
+// All data sources are fetched in parallel to minimize startup time.
 func (d *DataProvider) SetupData(forceRefresh bool) error {
 	var (
 		wg          sync.WaitGroup
@@ -593,10 +662,9 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 		firstErr    error
 		installed   []models.Formula
 		remote      []models.Formula
-		analytics   map[string]models.AnalyticsItem
 		instCasks   []models.Cask
 		remoteCasks []models.Cask
-		caskAnalyt  map[string]models.AnalyticsItem
+		analytics   map[analyticsList]map[string]models.AnalyticsItem
 	)
 
 	setErr := func(err error) {
@@ -615,9 +683,9 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 	if useV2 {
 		installed = v2Formulae
 		instCasks = v2Casks
-		wg.Add(4)
+		wg.Add(3)
 	} else {
-		wg.Add(6)
+		wg.Add(5)
 
 		go func() {
 			defer wg.Done()
@@ -656,18 +724,6 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 		mu.Unlock()
 	}()
 
-	go func() {
-		defer wg.Done()
-		result, err := d.GetFormulaeAnalytics(forceRefresh)
-		if err != nil {
-			setErr(fmt.Errorf("failed to get formulae analytics: %w", err))
-			return
-		}
-		mu.Lock()
-		analytics = result
-		mu.Unlock()
-	}()
-
 	go func() {
 		defer wg.Done()
 		result, err := d.GetRemoteCasks(forceRefresh)
@@ -680,15 +736,24 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 		mu.Unlock()
 	}()
 
+	d.analyticsMu.RLock()
+	metric := d.analyticsMetric
+	d.analyticsMu.RUnlock()
+
 	go func() {
 		defer wg.Done()
-		result, err := d.GetCaskAnalytics(forceRefresh)
+		// Analytics are best-effort: a list that fails to download falls back to its
+		// stale cache, or is left out, instead of failing the whole setup
+		result, err := d.loadAnalytics(metricLists(metric), forceRefresh)
 		if err != nil {
-			setErr(fmt.Errorf("failed to get cask analytics: %w", err))
-			return
+			for list, items := range loadCachedAnalytics(metricLists(metric)) {
+				if _, ok := result[list]; !ok {
+					result[list] = items
+				}
+			}
 		}
 		mu.Lock()
-		caskAnalyt = result
+		analytics = result
 		mu.Unlock()
 	}()
 
@@ -700,71 +765,152 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 
 	*d.installedFormulae = installed
 	*d.remoteFormulae = remote
-	d.formulaeAnalytics = analytics
 	*d.installedCasks = instCasks
 	*d.remoteCasks = remoteCasks
-	d.caskAnalytics = caskAnalyt
+
+	// Log what changed in the catalogue; news are informational, so errors are ignored
+	_ = updateNews(remote, remoteCasks, time.Now())
+
+	// A metric selected while refreshing wins over the lists loaded here, and
+	// lists already loaded are kept when none could be loaded now
+	d.analyticsMu.Lock()
+	if d.analyticsMetric == metric && len(analytics) > 0 {
+		d.analytics = analytics
+	}
+	d.analyticsMu.Unlock()
 
 	return nil
 }
 
-// enrichWithAnalytics applies analytics data (rank and download count) to a package.
-func (d *DataProvider) enrichWithAnalytics(pkg *models.Package, analytics map[string]models.AnalyticsItem, name string) {
-	if a, exists := analytics[name]; exists && a.Number > 0 {
-		downloads, _ := strconv.Atoi(strings.ReplaceAll(a.Count, ",", ""))
-		pkg.Analytics90dRank = a.Number
-		pkg.Analytics90dDownloads = downloads
+// LoadCachedData populates the DataProvider from on-disk caches only, ignoring TTLs.
+// It never runs brew or touches the network, so the UI can be painted instantly
+// with possibly stale data while SetupData revalidates in the background.
+// Returns an error when no catalogue has been cached yet (e.g. first run).
+func (d *DataProvider) LoadCachedData() error {
+	if d.prefixPath == "" {
+		if d.prefixPath = d.cachedPrefixPath(); d.prefixPath == "" {
+			d.prefixPath, d.prefixGuessed = defaultPrefixPath(), true
+		}
 	}
+
+	if data := readStaleCacheFile(cacheFileInstalledV2, 10); data != nil {
+		if formulae, casks, err := parseInstalledV2(data, d.prefixPath); err == nil {
+			*d.installedFormulae = formulae
+			*d.installedCasks = casks
+		}
+	} else {
+		if data := readStaleCacheFile(cacheFileInstalled, 10); data != nil {
+			if formulae, err := parseInstalledFormulae(data, d.prefixPath); err == nil {
+				*d.installedFormulae = formulae
+			}
+		}
+		if data := readStaleCacheFile(cacheFileInstalledCasks, 10); data != nil {
+			if casks, err := parseInstalledCasks(data); err == nil {
+				*d.installedCasks = casks
+			}
+		}
+	}
+
+	if casks, err := readCachedCasks(0); err == nil {
+		*d.remoteCasks = casks
+	}
+	d.analyticsMu.RLock()
+	metric := d.analyticsMetric
+	d.analyticsMu.RUnlock()
+	d.setAnalytics(loadCachedAnalytics(metricLists(metric)), metric)
+	d.setDiskUsage(loadCachedKegs())
+
+	formulae, spans, err := readCachedFormulae(0)
+	if err != nil {
+		return err
+	}
+	*d.remoteFormulae = formulae
+	d.setFormulaSpans(spans)
+	return nil
 }
 
 // GetPackages retrieves all packages (formulae + casks), merging remote and installed.
+// Packages point directly into the DataProvider's formula and cask slices instead
+// of holding copies, so the catalogue is kept in memory only once.
 func (d *DataProvider) GetPackages() *[]models.Package {
-	packageMap := make(map[string]models.Package)
-
-	for _, formula := range *d.remoteFormulae {
-		if _, exists := packageMap[formula.Name]; !exists {
-			f := formula
-			pkg := models.NewPackageFromFormula(&f)
-			d.enrichWithAnalytics(&pkg, d.formulaeAnalytics, formula.Name)
-			packageMap[formula.Name] = pkg
+	total := len(*d.remoteFormulae) + len(*d.remoteCasks)
+	packages := make([]models.Package, 0, total)
+	index := make(map[string]int, total)
+	enrich := d.packageEnricher()
+	prefix := d.prefixPath
+	if d.prefixGuessed || prefix == "Unknown" {
+		prefix = ""
+	}
+	platform := platformBottleTag()
+
+	// add appends a package, or replaces an existing one when override is set
+	// (installed data is more accurate than the remote catalogue).
+	add := func(pkg models.Package, override bool) {
+		pkg.Caveats = expandCaveats(pkg.Caveats, prefix)
+		if pkg.Formula != nil {
+			pkg.Bottle = bottleStatus(pkg.Formula, platform)
+		}
+		if i, exists := index[pkg.Name]; exists {
+			if override {
+				packages[i] = pkg
+			}
+			return
 		}
+		index[pkg.Name] = len(packages)
+		packages = append(packages, pkg)
 	}
 
-	for _, formula := range *d.installedFormulae {
-		f := formula
-		pkg := models.NewPackageFromFormula(&f)
-		d.enrichWithAnalytics(&pkg, d.formulaeAnalytics, formula.Name)
-		packageMap[formula.Name] = pkg
+	for i := range *d.remoteFormulae {
+		f := &(*d.remoteFormulae)[i]
+		pkg := models.NewPackageFromFormula(f)
+		enrich(&pkg)
+		add(pkg, false)
 	}
 
-	for _, cask := range *d.remoteCasks {
-		if _, exists := packageMap[cask.Token]; !exists {
-			c := cask
-			pkg := models.NewPackageFromCask(&c)
-			d.enrichWithAnalytics(&pkg, d.caskAnalytics, cask.Token)
-			packageMap[cask.Token] = pkg
-		}
+	for i := range *d.installedFormulae {
+		f := &(*d.installedFormulae)[i]
+		pkg := models.NewPackageFromFormula(f)
+		enrich(&pkg)
+		add(pkg, true)
 	}
 
-	for _, cask := range *d.installedCasks {
-		c := cask
-		pkg := models.NewPackageFromCask(&c)
-		d.enrichWithAnalytics(&pkg, d.caskAnalytics, cask.Token)
-		packageMap[cask.Token] = pkg
+	for i := range *d.remoteCasks {
+		c := &(*d.remoteCasks)[i]
+		pkg := models.NewPackageFromCask(c)
+		enrich(&pkg)
+		add(pkg, false)
 	}
 
-	*d.allPackages = make([]models.Package, 0, len(packageMap))
-	for _, pkg := range packageMap {
-		*d.allPackages = append(*d.allPackages, pkg)
+	for i := range *d.installedCasks {
+		c := &(*d.installedCasks)[i]
+		pkg := models.NewPackageFromCask(c)
+		enrich(&pkg)
+		add(pkg, true)
 	}
 
-	sort.Slice(*d.allPackages, func(i, j int) bool {
-		return (*d.allPackages)[i].Name < (*d.allPackages)[j].Name
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
 	})
+	d.ApplyDiskUsage(packages)
 
+	*d.allPackages = packages
 	return d.allPackages
 }
 
+// expandCaveats replaces the Homebrew prefix placeholders the API uses in caveats
+// with the actual prefix, when it is known.
+func expandCaveats(caveats, prefix string) string {
+	if caveats == "" || prefix == "" {
+		return caveats
+	}
+	return strings.NewReplacer("$HOMEBREW_PREFIX", prefix, "HOMEBREW_PREFIX_PLACEHOLDER", prefix).Replace(caveats)
+}
+
+// packageKey identifies a package in maps keyed by package; formulae and casks may share names.
+func packageKey(pkgType models.PackageType, name string) string {
+	return string(pkgType) + ":" + name
+}
+
 // fetchInstalledNames returns a map of installed package names for the given type.
 func (d *DataProvider) fetchInstalledNames(packageType string) map[string]bool {
 	result := make(map[string]bool)
@@ -104,3 +104,33 @@ func TestGetFlatpakPackages_Basic(t *testing.T) {
 		t.Errorf("result[0].DisplayName = %q, want %q", result[0].DisplayName, "Spotify")
 	}
 }
+
+func TestExpandCaveats(t *testing.T) {
+	caveats := "To start postgresql@16:\n  $HOMEBREW_PREFIX/opt/postgresql@16/bin/postgres -D HOMEBREW_PREFIX_PLACEHOLDER/var/postgresql@16"
+	want := "To start postgresql@16:\n  /opt/homebrew/opt/postgresql@16/bin/postgres -D /opt/homebrew/var/postgresql@16"
+	if got := expandCaveats(caveats, "/opt/homebrew"); got != want {
+		t.Errorf("expandCaveats() = %q, want %q", got, want)
+	}
+	if got := expandCaveats(caveats, ""); got != caveats {
+		t.Errorf("expandCaveats() without a prefix = %q, want it unchanged", got)
+	}
+}
+
+func TestParseInstalledV2(t *testing.T) {
+	data := []byte(`{"formulae":[{"name":"wget"}],"casks":[{"token":"firefox"}]}`)
+
+	formulae, casks, err := parseInstalledV2(data, "/opt/homebrew")
+	if err != nil {
+		t.Fatalf("parseInstalledV2() error = %v", err)
+	}
+	if len(formulae) != 1 || !formulae[0].LocallyInstalled || formulae[0].LocalPath != "/opt/homebrew/Cellar/wget" {
+		t.Errorf("formulae = %+v, want wget installed in the Cellar", formulae)
+	}
+	if len(casks) != 1 || !casks[0].LocallyInstalled {
+		t.Errorf("casks = %+v, want firefox installed", casks)
+	}
+
+	if _, _, err := parseInstalledV2([]byte("not json"), "/opt/homebrew"); err == nil {
+		t.Error("parseInstalledV2() should fail on invalid JSON")
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"bbrew/internal/models"
+)
+
+// sortByDisableDate orders deprecated and disabled packages by the date they were or
+// will be disabled, earliest first; packages without a date come last, by name.
+func sortByDisableDate(list []models.Package) {
+	slices.SortStableFunc(list, func(a, b models.Package) int {
+		da, _ := a.Deprecation()
+		db, _ := b.Deprecation()
+		switch {
+		case da.DisableDate == "" && db.DisableDate != "":
+			return 1
+		case da.DisableDate != "" && db.DisableDate == "":
+			return -1
+		}
+		return cmp.Or(strings.Compare(da.DisableDate, db.DisableDate), strings.Compare(a.Name, b.Name))
+	})
+}
+
+// deprecationTag describes a deprecation for the table, e.g. "[DISABLED 2025-03-01 → wget2]"
+// or "[DEPRECATED, disabled from 2026-01-01, no replacement]".
+func deprecationTag(pkg *models.Package) string {
+	d, ok := pkg.Deprecation()
+	if !ok {
+		return ""
+	}
+	tag := "DEPRECATED"
+	if d.Disabled {
+		tag = "DISABLED"
+	}
+	switch {
+	case d.Disabled && d.DisableDate != "":
+		tag += " " + d.DisableDate
+	case d.DisableDate != "":
+		tag += ", disabled from " + d.DisableDate
+	}
+	if d.Replacement != "" {
+		tag += " → " + d.Replacement
+	} else {
+		tag += ", no replacement"
+	}
+	return "[" + tag + "]"
+}
+
+// migrationTarget returns the replacement of a deprecated or disabled package from the catalogue.
+func (s *AppService) migrationTarget(pkg models.Package) (models.Package, error) {
+	d, ok := pkg.Deprecation()
+	switch {
+	case !ok:
+		return models.Package{}, fmt.Errorf("%s is neither deprecated nor disabled", pkg.Name)
+	case d.Replacement == "":
+		return models.Package{}, fmt.Errorf("no replacement is named for %s", pkg.Name)
+	}
+	target, found := s.findPackage(d.ReplacementType, d.Replacement)
+	if !found {
+		return models.Package{}, fmt.Errorf("replacement %s %s is not in the catalogue", d.ReplacementType, d.Replacement)
+	}
+	return target, nil
+}
+
+// planMigration lists the brew commands moving from a deprecated package to its
+// replacement: install the replacement unless it already is, then uninstall the old package.
+func planMigration(brew BrewServiceInterface, from, to models.Package, opts models.InstallOptions) []brewStep {
+	var steps []brewStep
+	if !to.LocallyInstalled {
+		flags := opts.Flags(to.Type)
+		if to.Type == models.PackageTypeCask {
+			flags = append([]string{"--cask"}, flags...)
+		}
+		steps = append(steps, brewStep{
+			description: commandLine("install", flags, to.Name),
+			run:         func(output io.Writer) error { return brew.InstallPackage(to, opts, output) },
+		})
+	}
+	var flags []string
+	if from.Type == models.PackageTypeCask {
+		flags = []string{"--cask"}
+	}
+	return append(steps, brewStep{
+		description: commandLine("uninstall", flags, from.Name),
+		run:         func(output io.Writer) error { return brew.RemovePackage(from, output) },
+	})
+}
+
+// brewfileDirective returns the Brewfile keyword of a Homebrew package type.
+func brewfileDirective(pkgType models.PackageType) string {
+	if pkgType == models.PackageTypeCask {
+		return "cask"
+	}
+	return "brew"
+}
+
+// replaceBrewfileEntry rewrites the Brewfile entry of a package to name its replacement.
+// Options after the name are kept when the replacement has the same type; if the
+// replacement is already listed, the old entry is dropped instead. It reports whether
+// the entry was found.
+func replaceBrewfileEntry(content string, from, to models.Package) (string, bool) {
+	lines := strings.Split(content, "\n")
+	entryIndex := func(pkg models.Package) int {
+		return slices.IndexFunc(lines, func(line string) bool {
+			line = strings.TrimSpace(line)
+			name, ok := extractQuotedValue(line)
+			return ok && strings.HasPrefix(line, brewfileDirective(pkg.Type)+" ") &&
+				(name == pkg.Name || strings.HasSuffix(name, "/"+pkg.Name))
+		})
+	}
+
+	i := entryIndex(from)
+	if i < 0 {
+		return content, false
+	}
+	if entryIndex(to) >= 0 {
+		return strings.Join(slices.Delete(lines, i, i+1), "\n"), true
+	}
+
+	line := lines[i]
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	entry := fmt.Sprintf("%s %q", brewfileDirective(to.Type), to.Name)
+	if to.Type == from.Type {
+		// Keep options and comments: the rest of the line after the quoted name
+		rest := strings.TrimLeft(line, " \t")
+		start := strings.Index(rest, `"`)
+		end := strings.Index(rest[start+1:], `"`)
+		entry += rest[start+1+end+1:]
+	}
+	lines[i] = indent + entry
+	return strings.Join(lines, "\n"), true
+}
+
+// updateBrewfileEntry replaces a migrated package by its replacement in the loaded
+// Brewfile. It reports whether the Brewfile listed the package.
+func (s *AppService) updateBrewfileEntry(from, to models.Package) (bool, error) {
+	if !s.IsBrewfileMode() {
+		return false, nil
+	}
+	info, err := os.Stat(s.brewfilePath)
+	if err != nil {
+		return false, err
+	}
+	// #nosec G304 -- brewfilePath is user-provided via CLI flag
+	data, err := os.ReadFile(s.brewfilePath)
+	if err != nil {
+		return false, err
+	}
+	content, found := replaceBrewfileEntry(string(data), from, to)
+	if !found {
+		return false, nil
+	}
+	if err := os.WriteFile(s.brewfilePath, []byte(content), info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to update Brewfile: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestSortByDisableDate(t *testing.T) {
+	list := []models.Package{
+		{Name: "c-nodate", Deprecated: true, Formula: &models.Formula{}},
+		{Name: "b-later", Deprecated: true, Formula: &models.Formula{DisableDate: "2026-06-01"}},
+		{Name: "a-nodate", Deprecated: true, Cask: &models.Cask{}},
+		{Name: "d-disabled", Disabled: true, Formula: &models.Formula{DisableDate: "2025-01-15"}},
+	}
+	sortByDisableDate(list)
+
+	var names []string
+	for _, pkg := range list {
+		names = append(names, pkg.Name)
+	}
+	if want := []string{"d-disabled", "b-later", "a-nodate", "c-nodate"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("sortByDisableDate() = %v, want %v", names, want)
+	}
+}
+
+func TestDeprecationTag(t *testing.T) {
+	tests := []struct {
+		pkg  models.Package
+		want string
+	}{
+		{models.Package{Disabled: true, Formula: &models.Formula{DisableDate: "2025-03-01", DisableReplacement: "wget2"}}, "[DISABLED 2025-03-01 → wget2]"},
+		{models.Package{Deprecated: true, Formula: &models.Formula{DisableDate: "2026-01-01"}}, "[DEPRECATED, disabled from 2026-01-01, no replacement]"},
+		{models.Package{Formula: &models.Formula{}}, ""},
+	}
+	for _, tt := range tests {
+		if got := deprecationTag(&tt.pkg); got != tt.want {
+			t.Errorf("deprecationTag() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	old := models.Package{Name: "youtube-dl", Type: models.PackageTypeFormula, LocallyInstalled: true}
+	replacement := models.Package{Name: "yt-dlp", Type: models.PackageTypeFormula}
+	got := stepDescriptions(planMigration(nil, old, replacement, models.InstallOptions{}))
+	if want := []string{"brew install yt-dlp", "brew uninstall youtube-dl"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("planMigration() = %v, want %v", got, want)
+	}
+
+	// An installed replacement is not installed again; casks get --cask
+	oldCask := models.Package{Name: "docker", Type: models.PackageTypeCask, LocallyInstalled: true}
+	installed := models.Package{Name: "orbstack", Type: models.PackageTypeCask, LocallyInstalled: true}
+	got = stepDescriptions(planMigration(nil, oldCask, installed, models.InstallOptions{}))
+	if want := []string{"brew uninstall --cask docker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("planMigration() = %v, want %v", got, want)
+	}
+}
+
+func TestReplaceBrewfileEntry(t *testing.T) {
+	from := models.Package{Name: "youtube-dl", Type: models.PackageTypeFormula}
+	to := models.Package{Name: "yt-dlp", Type: models.PackageTypeFormula}
+	toCask := models.Package{Name: "yt-dlp-app", Type: models.PackageTypeCask}
+
+	tests := []struct {
+		name      string
+		content   string
+		to        models.Package
+		want      string
+		wantFound bool
+	}{
+		{
+			"keeps options and comments",
+			"tap \"homebrew/core\"\n  brew \"youtube-dl\", args: [\"HEAD\"] # downloads\ncask \"firefox\"\n",
+			to,
+			"tap \"homebrew/core\"\n  brew \"yt-dlp\", args: [\"HEAD\"] # downloads\ncask \"firefox\"\n",
+			true,
+		},
+		{
+			"tap-qualified name, new type drops options",
+			"brew \"someone/tap/youtube-dl\", restart_service: true\n",
+			toCask,
+			"cask \"yt-dlp-app\"\n",
+			true,
+		},
+		{
+			"replacement already listed",
+			"brew \"youtube-dl\"\nbrew \"yt-dlp\"\n",
+			to,
+			"brew \"yt-dlp\"\n",
+			true,
+		},
+		{
+			"not listed",
+			"cask \"youtube-dl\"\n",
+			to,
+			"cask \"youtube-dl\"\n",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		got, found := replaceBrewfileEntry(tt.content, from, tt.to)
+		if got != tt.want || found != tt.wantFound {
+			t.Errorf("%s: replaceBrewfileEntry() = %q, %v, want %q, %v", tt.name, got, found, tt.want, tt.wantFound)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"bbrew/internal/models"
+)
+
+// dependencyGraph links catalogue packages to their direct dependencies, and
+// installed packages to what they depend on, for dependency trees in both directions.
+type dependencyGraph struct {
+	packages   map[string]*models.Package // By packageKey
+	dependents map[string][]string        // Installed packages depending directly on each key
+}
+
+// newDependencyGraph indexes a package list. The packages must outlive the graph.
+func newDependencyGraph(packages []models.Package) *dependencyGraph {
+	g := &dependencyGraph{
+		packages:   make(map[string]*models.Package, len(packages)),
+		dependents: make(map[string][]string),
+	}
+	for i := range packages {
+		pkg := &packages[i]
+		g.packages[packageKey(pkg.Type, pkg.Name)] = pkg
+	}
+	for key, pkg := range g.packages {
+		if !pkg.LocallyInstalled {
+			continue
+		}
+		for _, dep := range g.dependencies(pkg) {
+			g.dependents[dep] = append(g.dependents[dep], key)
+		}
+	}
+	for _, keys := range g.dependents {
+		sort.Strings(keys)
+	}
+	return g
+}
+
+// formulaKey returns the key of a formula dependency. Dependencies on tap formulae
+// use the full "user/tap/name" form, while packages are named by their short name.
+func formulaKey(fullName string) string {
+	return packageKey(models.PackageTypeFormula, fullName[strings.LastIndex(fullName, "/")+1:])
+}
+
+// dependencies returns the keys of a package's direct runtime dependencies. For an
+// installed formula they come from its install receipt, which records what it was
+// actually built against; otherwise from the catalogue.
+func (g *dependencyGraph) dependencies(pkg *models.Package) []string {
+	var keys []string
+	switch {
+	case pkg.Formula != nil:
+		if installed := pkg.Formula.Installed; pkg.LocallyInstalled && len(installed) > 0 && len(installed[0].RuntimeDependencies) > 0 {
+			for _, dep := range receiptDependencies(pkg.Formula) {
+				keys = append(keys, formulaKey(dep.FullName))
+			}
+			return keys
+		}
+		for _, dep := range pkg.Formula.Dependencies {
+			keys = append(keys, formulaKey(dep))
+		}
+	case pkg.Cask != nil:
+		for _, dep := range pkg.Cask.DependsOn.Formula {
+			keys = append(keys, formulaKey(dep))
+		}
+		for _, dep := range pkg.Cask.DependsOn.Cask {
+			keys = append(keys, packageKey(models.PackageTypeCask, dep[strings.LastIndex(dep, "/")+1:]))
+		}
+	}
+	return keys
+}
+
+// receiptDependencies returns the direct runtime dependencies recorded in the install
+// receipt of a formula. Receipts written before Homebrew recorded direct dependencies
+// mark none of them, and then every runtime dependency is returned.
+func receiptDependencies(f *models.Formula) []models.RuntimeDependency {
+	if len(f.Installed) == 0 {
+		return nil
+	}
+	deps := f.Installed[0].RuntimeDependencies
+	if !slices.ContainsFunc(deps, func(d models.RuntimeDependency) bool { return d.DeclaredDirectly }) {
+		return deps
+	}
+	var direct []models.RuntimeDependency
+	for _, dep := range deps {
+		if dep.DeclaredDirectly {
+			direct = append(direct, dep)
+		}
+	}
+	return direct
+}
+
+// node returns the tree node of a package key, without children.
+func (g *dependencyGraph) node(key string) *models.DependencyNode {
+	pkgType, name, _ := strings.Cut(key, ":")
+	node := &models.DependencyNode{Name: name, Type: models.PackageType(pkgType)}
+	if pkg, ok := g.packages[key]; ok {
+		node.Installed = pkg.LocallyInstalled
+	} else {
+		node.Missing = true
+	}
+	return node
+}
+
+// tree expands a package into a tree following next (dependencies or dependents).
+// Each package is expanded once: later occurrences are marked Repeated, which
+// also stops dependency cycles.
+func (g *dependencyGraph) tree(key string, next func(key string) []string) *models.DependencyNode {
+	expanded := map[string]bool{key: true}
+	var build func(node *models.DependencyNode, key string)
+	build = func(node *models.DependencyNode, key string) {
+		for _, childKey := range next(key) {
+			child := g.node(childKey)
+			node.Children = append(node.Children, child)
+			if expanded[childKey] {
+				child.Repeated = len(next(childKey)) > 0
+				continue
+			}
+			expanded[childKey] = true
+			build(child, childKey)
+		}
+	}
+
+	root := g.node(key)
+	build(root, key)
+	return root
+}
+
+// DependencyTree returns the full runtime dependency tree of a package.
+func (g *dependencyGraph) DependencyTree(pkgType models.PackageType, name string) *models.DependencyNode {
+	return g.tree(packageKey(pkgType, name), func(key string) []string {
+		if pkg, ok := g.packages[key]; ok {
+			return g.dependencies(pkg)
+		}
+		return nil
+	})
+}
+
+// DependentsTree returns the tree of installed packages that depend on a package,
+// directly or through other installed packages.
+func (g *dependencyGraph) DependentsTree(pkgType models.PackageType, name string) *models.DependencyNode {
+	return g.tree(packageKey(pkgType, name), func(key string) []string {
+		return g.dependents[key]
+	})
+}
+
+// InstalledDependents returns every installed package that depends on a package,
+// directly or through other installed packages, ordered by type and name.
+func (g *dependencyGraph) InstalledDependents(pkgType models.PackageType, name string) []models.Package {
+	seen := make(map[string]bool)
+	var walk func(key string)
+	walk = func(key string) {
+		for _, dependent := range g.dependents[key] {
+			if !seen[dependent] {
+				seen[dependent] = true
+				walk(dependent)
+			}
+		}
+	}
+	walk(packageKey(pkgType, name))
+	return g.packageList(seen)
+}
+
+// Orphans returns the installed formulae that were only installed as dependencies
+// and that no other installed package needs anymore, like `brew autoremove`.
+// Formulae needed only by other orphans are orphans too.
+func (g *dependencyGraph) Orphans() []models.Package {
+	orphans := make(map[string]bool)
+	for key, pkg := range g.packages {
+		if pkg.LocallyInstalled && pkg.Formula != nil && len(pkg.Formula.Installed) > 0 &&
+			pkg.Formula.Installed[0].InstalledAsDependency && !pkg.InstalledOnRequest {
+			orphans[key] = true
+		}
+	}
+
+	// Drop candidates still needed by a package that stays installed, until none changes
+	for changed := true; changed; {
+		changed = false
+		for key := range orphans {
+			for _, dependent := range g.dependents[key] {
+				if !orphans[dependent] {
+					delete(orphans, key)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return g.packageList(orphans)
+}
+
+// packageList returns the packages of a set of keys, ordered by type and name.
+func (g *dependencyGraph) packageList(set map[string]bool) []models.Package {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	packages := make([]models.Package, 0, len(keys))
+	for _, key := range keys {
+		packages = append(packages, *g.packages[key])
+	}
+	return packages
+}
+
+// dependencyTrees returns the dependency tree of a package and the tree of the
+// installed packages depending on it, from the full package list.
+func (s *AppService) dependencyTrees(pkg models.Package) (deps, usedBy *models.DependencyNode) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g := newDependencyGraph(*s.packages)
+	return g.DependencyTree(pkg.Type, pkg.Name), g.DependentsTree(pkg.Type, pkg.Name)
+}
+
+// selectPackageOfType moves the table selection to a package when it is visible.
+func (s *AppService) selectPackageOfType(pkgType models.PackageType, name string) bool {
+	for i, pkg := range *s.filteredPackages {
+		if pkg.Type == pkgType && pkg.Name == name {
+			s.layout.GetTable().View().Select(i+1, 0)
+			return true
+		}
+	}
+	return false
+}
+
+// installedDependents returns the installed packages that depend on a package.
+func (s *AppService) installedDependents(pkg models.Package) []models.Package {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return newDependencyGraph(*s.packages).InstalledDependents(pkg.Type, pkg.Name)
+}
+
+// orphanedFormulae returns the installed formulae no longer needed by anything.
+func (s *AppService) orphanedFormulae() []models.Package {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return newDependencyGraph(*s.packages).Orphans()
+}
+
+// LoadOrphans finds the orphaned formulae shown by the Orphans filter and returns
+// how many there are and the disk space removing them would free.
+func (s *AppService) LoadOrphans() (count int, reclaimable int64) {
+	orphans := s.orphanedFormulae()
+	s.orphans = make(map[string]bool, len(orphans))
+	for _, pkg := range orphans {
+		s.orphans[packageKey(pkg.Type, pkg.Name)] = true
+		reclaimable += pkg.DiskUsage.Bytes
+	}
+	return len(orphans), reclaimable
+}
+
+// installedSize returns the measured disk usage of the named installed formulae.
+func (s *AppService) installedSize(names []string) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var total int64
+	for _, pkg := range *s.packages {
+		if pkg.Type == models.PackageTypeFormula && wanted[pkg.Name] {
+			total += pkg.DiskUsage.Bytes
+		}
+	}
+	return total
+}
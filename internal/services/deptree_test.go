@@ -0,0 +1,219 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+// dependencyTestPackages returns a small catalogue: wget (installed) depends on
+// openssl@3 and libidn2, both depending on ca-certificates; curl is not installed.
+func dependencyTestPackages() []models.Package {
+	formula := func(name string, installed bool, deps ...string) models.Package {
+		f := &models.Formula{Name: name, Dependencies: deps}
+		return models.Package{Name: name, Type: models.PackageTypeFormula, LocallyInstalled: installed, Formula: f}
+	}
+
+	wget := formula("wget", true, "openssl@3", "libidn2", "gettext")
+	// The receipt records what wget was built against: no gettext, plus a tap formula
+	wget.Formula.Installed = []models.Installed{{RuntimeDependencies: []models.RuntimeDependency{
+		{FullName: "openssl@3", DeclaredDirectly: true},
+		{FullName: "libidn2", DeclaredDirectly: true},
+		{FullName: "ca-certificates", DeclaredDirectly: false},
+		{FullName: "someone/tap/extra", DeclaredDirectly: true},
+	}}}
+
+	return []models.Package{
+		wget,
+		formula("openssl@3", true, "ca-certificates"),
+		formula("libidn2", true, "ca-certificates"),
+		formula("ca-certificates", true),
+		formula("gettext", false),
+		formula("curl", false, "openssl@3"),
+		{Name: "docker", Type: models.PackageTypeCask, LocallyInstalled: true, Cask: &models.Cask{
+			Token: "docker", DependsOn: models.CaskDependsOn{Formula: []string{"libidn2"}},
+		}},
+	}
+}
+
+func childNames(node *models.DependencyNode) []string {
+	var names []string
+	for _, child := range node.Children {
+		names = append(names, child.Name)
+	}
+	return names
+}
+
+func TestDependencyTree_UsesInstallReceipt(t *testing.T) {
+	g := newDependencyGraph(dependencyTestPackages())
+	tree := g.DependencyTree(models.PackageTypeFormula, "wget")
+
+	names := childNames(tree)
+	want := []string{"openssl@3", "libidn2", "extra"}
+	if len(names) != len(want) {
+		t.Fatalf("direct dependencies = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("direct dependencies = %v, want %v", names, want)
+		}
+	}
+
+	openssl, libidn2, extra := tree.Children[0], tree.Children[1], tree.Children[2]
+	if !openssl.Installed || len(openssl.Children) != 1 || openssl.Children[0].Name != "ca-certificates" {
+		t.Errorf("openssl@3 = %+v, want installed with ca-certificates below", openssl)
+	}
+	// ca-certificates has no dependencies, so repeating it shows nothing to expand
+	if len(libidn2.Children) != 1 || libidn2.Children[0].Repeated {
+		t.Errorf("libidn2 children = %+v", libidn2.Children)
+	}
+	if !extra.Missing {
+		t.Error("tap formula missing from the catalogue should be marked Missing")
+	}
+	if got := tree.Count(); got != 4 {
+		t.Errorf("Count() = %d, want 4", got)
+	}
+}
+
+func TestDependencyTree_UsesCatalogueWhenNotInstalled(t *testing.T) {
+	g := newDependencyGraph(dependencyTestPackages())
+	tree := g.DependencyTree(models.PackageTypeFormula, "curl")
+
+	if len(tree.Children) != 1 || tree.Children[0].Name != "openssl@3" {
+		t.Fatalf("curl dependencies = %v, want [openssl@3]", childNames(tree))
+	}
+	if len(tree.Children[0].Children) != 1 {
+		t.Errorf("openssl@3 should be expanded below curl")
+	}
+}
+
+func TestDependencyTree_OldReceiptWithoutDirectDependencies(t *testing.T) {
+	packages := dependencyTestPackages()
+	// Receipts written before Homebrew recorded direct dependencies mark none of them
+	packages[0].Formula.Installed = []models.Installed{{RuntimeDependencies: []models.RuntimeDependency{
+		{FullName: "openssl@3"},
+		{FullName: "libidn2"},
+		{FullName: "ca-certificates"},
+	}}}
+	packages = packages[:len(packages)-1] // Without the docker cask, only wget needs libidn2
+	for i := range packages {
+		pkg := &packages[i]
+		pkg.InstalledOnRequest = pkg.Name == "wget"
+		if pkg.LocallyInstalled && pkg.Formula.Installed == nil {
+			pkg.Formula.Installed = []models.Installed{{InstalledAsDependency: true}}
+		}
+	}
+	g := newDependencyGraph(packages)
+
+	if names := childNames(g.DependencyTree(models.PackageTypeFormula, "wget")); strings.Join(names, " ") != "openssl@3 libidn2 ca-certificates" {
+		t.Errorf("wget dependencies = %v, want every runtime dependency", names)
+	}
+	if got := packageNames(g.InstalledDependents(models.PackageTypeFormula, "libidn2"), 10); got != "wget" {
+		t.Errorf("libidn2 dependents = %s, want wget", got)
+	}
+	if got := g.Orphans(); len(got) != 0 {
+		t.Errorf("every dependency is still needed by wget, got orphans %v", got)
+	}
+}
+
+func TestDependencyTree_MarksRepeatedAndStopsCycles(t *testing.T) {
+	formula := func(name string, deps ...string) models.Package {
+		return models.Package{Name: name, Type: models.PackageTypeFormula, Formula: &models.Formula{Name: name, Dependencies: deps}}
+	}
+	g := newDependencyGraph([]models.Package{
+		formula("a", "b", "c"),
+		formula("b", "c"),
+		formula("c", "a"),
+	})
+
+	tree := g.DependencyTree(models.PackageTypeFormula, "a")
+	b, c := tree.Children[0], tree.Children[1]
+	if len(b.Children) != 1 || b.Children[0].Name != "c" || b.Children[0].Repeated {
+		t.Fatalf("c should be expanded under b first, got %+v", b.Children)
+	}
+	if cycle := b.Children[0].Children; len(cycle) != 1 || cycle[0].Name != "a" || !cycle[0].Repeated || cycle[0].Children != nil {
+		t.Errorf("the cycle back to a should be a repeated leaf, got %+v", cycle)
+	}
+	if !c.Repeated || c.Children != nil {
+		t.Errorf("second occurrence of c should be repeated without children, got %+v", c)
+	}
+}
+
+func TestDependentsTree(t *testing.T) {
+	g := newDependencyGraph(dependencyTestPackages())
+
+	tree := g.DependentsTree(models.PackageTypeFormula, "libidn2")
+	names := childNames(tree)
+	if len(names) != 2 || names[0] != "docker" || names[1] != "wget" {
+		t.Fatalf("libidn2 dependents = %v, want [docker wget]", names)
+	}
+	if tree.Children[0].Type != models.PackageTypeCask {
+		t.Errorf("docker should be a cask, got %s", tree.Children[0].Type)
+	}
+
+	// Transitive dependents through installed packages; curl is not installed
+	tree = g.DependentsTree(models.PackageTypeFormula, "ca-certificates")
+	if names := childNames(tree); len(names) != 2 || names[0] != "libidn2" || names[1] != "openssl@3" {
+		t.Fatalf("ca-certificates dependents = %v, want [libidn2 openssl@3]", names)
+	}
+	if got := tree.Count(); got != 4 {
+		t.Errorf("Count() = %d, want 4 (libidn2, openssl@3, docker, wget)", got)
+	}
+}
+
+func TestInstalledDependents(t *testing.T) {
+	g := newDependencyGraph(dependencyTestPackages())
+
+	dependents := g.InstalledDependents(models.PackageTypeFormula, "ca-certificates")
+	var names []string
+	for _, pkg := range dependents {
+		names = append(names, pkg.Name)
+	}
+	want := "docker, libidn2, openssl@3, wget"
+	if got := packageNames(dependents, 10); got != want {
+		t.Errorf("dependents = %v, want %s", names, want)
+	}
+
+	if got := g.InstalledDependents(models.PackageTypeFormula, "wget"); len(got) != 0 {
+		t.Errorf("wget has no dependents, got %v", got)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	packages := dependencyTestPackages()
+	for i := range packages {
+		pkg := &packages[i]
+		pkg.InstalledOnRequest = pkg.Name == "wget" || pkg.Type == models.PackageTypeCask
+		if pkg.Formula != nil && pkg.LocallyInstalled && pkg.Formula.Installed == nil {
+			pkg.Formula.Installed = []models.Installed{{InstalledAsDependency: !pkg.InstalledOnRequest}}
+		}
+	}
+
+	if got := newDependencyGraph(packages).Orphans(); len(got) != 0 {
+		t.Fatalf("every dependency is still needed, got orphans %v", got)
+	}
+
+	// Once wget is gone nothing needs openssl@3; libidn2 is still required by the
+	// docker cask, and ca-certificates by libidn2
+	packages[0].LocallyInstalled = false
+	if got := packageNames(newDependencyGraph(packages).Orphans(), 10); got != "openssl@3" {
+		t.Errorf("orphans = %s, want openssl@3", got)
+	}
+
+	// Without the cask, the whole chain is orphaned
+	packages[len(packages)-1].LocallyInstalled = false
+	if got := packageNames(newDependencyGraph(packages).Orphans(), 10); got != "ca-certificates, libidn2, openssl@3" {
+		t.Errorf("orphans = %s, want ca-certificates, libidn2, openssl@3", got)
+	}
+}
+
+func TestPackageNames(t *testing.T) {
+	packages := []models.Package{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if got := packageNames(packages, 3); got != "a, b, c" {
+		t.Errorf("packageNames() = %q", got)
+	}
+	if got := packageNames(packages, 2); got != "a, b and 1 more" {
+		t.Errorf("packageNames() with limit = %q", got)
+	}
+}
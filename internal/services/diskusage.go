@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"bbrew/internal/models"
+)
+
+// cacheFileDiskUsage keeps the size of each keg, so only new or changed kegs are walked again.
+const cacheFileDiskUsage = "disk-usage.json"
+
+// kegRecord is the measured size of one installed version in the Cellar or Caskroom.
+type kegRecord struct {
+	Type    models.PackageType `json:"type"`
+	Name    string             `json:"name"`
+	Version string             `json:"version"`
+	ModTime int64              `json:"mtime"` // Keg directory modification time (Unix nanoseconds)
+	Bytes   int64              `json:"bytes"`
+}
+
+// dirSize returns the total size of the regular files under a directory.
+// Symlinks are not followed, so files linked into the prefix are counted once.
+func dirSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsPermission(err) {
+				return nil // Skip unreadable files rather than failing the whole keg
+			}
+			return err
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+// listKegs returns the installed versions found under a Cellar or Caskroom directory,
+// with their directory modification times. Sizes are not measured yet.
+func listKegs(root string, pkgType models.PackageType) []kegRecord {
+	packages, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var kegs []kegRecord
+	for _, pkg := range packages {
+		if !pkg.IsDir() || strings.HasPrefix(pkg.Name(), ".") {
+			continue
+		}
+		versions, err := os.ReadDir(filepath.Join(root, pkg.Name()))
+		if err != nil {
+			continue
+		}
+		for _, version := range versions {
+			if !version.IsDir() || strings.HasPrefix(version.Name(), ".") { // e.g. the Caskroom .metadata
+				continue
+			}
+			info, err := version.Info()
+			if err != nil {
+				continue
+			}
+			kegs = append(kegs, kegRecord{
+				Type: pkgType, Name: pkg.Name(), Version: version.Name(),
+				ModTime: info.ModTime().UnixNano(),
+			})
+		}
+	}
+	return kegs
+}
+
+// measureKegs fills in the size of each keg, reusing cached sizes of kegs whose
+// directory has not changed and walking the others concurrently.
+func measureKegs(kegs []kegRecord, kegPath func(kegRecord) string, cached map[string]kegRecord) {
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan int)
+	)
+	for range runtime.NumCPU() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// A keg that cannot be walked is reported as empty
+				kegs[i].Bytes, _ = dirSize(kegPath(kegs[i]))
+			}
+		}()
+	}
+
+	for i, keg := range kegs {
+		if prev, ok := cached[kegPath(keg)]; ok && prev.ModTime == keg.ModTime {
+			kegs[i].Bytes = prev.Bytes
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// diskUsageByPackage groups keg sizes by package, keyed by packageKey.
+func diskUsageByPackage(kegs []kegRecord) map[string]models.DiskUsage {
+	usage := make(map[string]models.DiskUsage)
+	for _, keg := range kegs {
+		key := packageKey(keg.Type, keg.Name)
+		u := usage[key]
+		u.Bytes += keg.Bytes
+		u.Versions = append(u.Versions, models.KegSize{Version: keg.Version, Bytes: keg.Bytes})
+		usage[key] = u
+	}
+	for _, u := range usage {
+		sort.Slice(u.Versions, func(i, j int) bool { return u.Versions[i].Version < u.Versions[j].Version })
+	}
+	return usage
+}
+
+// kegRoots returns the Cellar and Caskroom directories of a Homebrew prefix.
+func kegRoots(prefix string) map[models.PackageType]string {
+	return map[models.PackageType]string{
+		models.PackageTypeFormula: filepath.Join(prefix, "Cellar"),
+		models.PackageTypeCask:    filepath.Join(prefix, "Caskroom"),
+	}
+}
+
+// loadCachedKegs reads the keg sizes of the previous scan.
+func loadCachedKegs() []kegRecord {
+	data := readStaleCacheFile(cacheFileDiskUsage, 2)
+	if data == nil {
+		return nil
+	}
+	var kegs []kegRecord
+	if err := json.Unmarshal(data, &kegs); err != nil {
+		return nil
+	}
+	return kegs
+}
+
+// setDiskUsage replaces the measured disk usage.
+func (d *DataProvider) setDiskUsage(kegs []kegRecord) {
+	d.diskMu.Lock()
+	defer d.diskMu.Unlock()
+	d.diskUsage = diskUsageByPackage(kegs)
+}
+
+// ScanDiskUsage measures the installed size of every formula (Cellar keg) and cask
+// (Caskroom). Apps that casks move to /Applications are not counted. Keg sizes are
+// cached and only kegs whose directory changed since the last scan are walked again.
+func (d *DataProvider) ScanDiskUsage() error {
+	prefix := d.getPrefixPath()
+	if prefix == "" || prefix == "Unknown" {
+		return fmt.Errorf("homebrew prefix not found")
+	}
+	roots := kegRoots(prefix)
+	kegPath := func(k kegRecord) string { return filepath.Join(roots[k.Type], k.Name, k.Version) }
+
+	cached := make(map[string]kegRecord)
+	for _, keg := range loadCachedKegs() {
+		cached[kegPath(keg)] = keg
+	}
+
+	kegs := append(listKegs(roots[models.PackageTypeFormula], models.PackageTypeFormula),
+		listKegs(roots[models.PackageTypeCask], models.PackageTypeCask)...)
+	measureKegs(kegs, kegPath, cached)
+	d.setDiskUsage(kegs)
+
+	if err := ensureCacheDir(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(kegs)
+	if err != nil {
+		return err
+	}
+	writeCacheFile(cacheFileDiskUsage, data)
+	return nil
+}
+
+// ApplyDiskUsage sets the measured disk usage on packages in place.
+func (d *DataProvider) ApplyDiskUsage(packages []models.Package) {
+	d.diskMu.RLock()
+	defer d.diskMu.RUnlock()
+	for i := range packages {
+		packages[i].DiskUsage = d.diskUsage[packageKey(packages[i].Type, packages[i].Name)]
+	}
+}
+
+// DiskUsageTotal returns the size of everything in the Cellar and Caskroom,
+// including packages missing from the catalogue (e.g. from third-party taps).
+func (d *DataProvider) DiskUsageTotal() int64 {
+	d.diskMu.RLock()
+	defer d.diskMu.RUnlock()
+	var total int64
+	for _, usage := range d.diskUsage {
+		total += usage.Bytes
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// writeKegFile creates a file of the given size inside a fake Homebrew prefix.
+func writeKegFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanDiskUsage(t *testing.T) {
+	useTempCacheDir(t)
+	prefix := t.TempDir()
+	t.Setenv("HOMEBREW_PREFIX", prefix)
+
+	writeKegFile(t, filepath.Join(prefix, "Cellar", "python@3.13", "3.13.0", "bin", "python3"), 300)
+	writeKegFile(t, filepath.Join(prefix, "Cellar", "python@3.13", "3.13.1", "bin", "python3"), 200)
+	writeKegFile(t, filepath.Join(prefix, "Cellar", "python@3.13", "3.13.1", "README"), 25)
+	writeKegFile(t, filepath.Join(prefix, "Caskroom", "firefox", "131.0", "Firefox.app.zip"), 1000)
+	writeKegFile(t, filepath.Join(prefix, "Caskroom", "firefox", ".metadata", "131.0", "firefox.json"), 5000)
+	if err := os.Symlink(filepath.Join(prefix, "Cellar", "python@3.13", "3.13.0", "bin", "python3"),
+		filepath.Join(prefix, "Cellar", "python@3.13", "3.13.1", "bin", "python-link")); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDataProvider()
+	if err := d.ScanDiskUsage(); err != nil {
+		t.Fatalf("ScanDiskUsage() error: %v", err)
+	}
+
+	packages := []models.Package{
+		{Name: "python@3.13", Type: models.PackageTypeFormula},
+		{Name: "firefox", Type: models.PackageTypeCask},
+		{Name: "firefox", Type: models.PackageTypeFormula}, // Same name, other type
+	}
+	d.ApplyDiskUsage(packages)
+
+	python := packages[0].DiskUsage
+	if python.Bytes != 525 || len(python.Versions) != 2 {
+		t.Fatalf("python@3.13 usage = %+v, want 525 bytes in 2 versions", python)
+	}
+	if python.Versions[0] != (models.KegSize{Version: "3.13.0", Bytes: 300}) || python.Versions[1].Bytes != 225 {
+		t.Errorf("python@3.13 versions = %+v", python.Versions)
+	}
+	if got := packages[1].DiskUsage.Bytes; got != 1000 {
+		t.Errorf("firefox cask = %d bytes, want 1000 (metadata excluded)", got)
+	}
+	if got := packages[2].DiskUsage.Bytes; got != 0 {
+		t.Errorf("firefox formula = %d bytes, want 0", got)
+	}
+	if got := d.DiskUsageTotal(); got != 1525 {
+		t.Errorf("DiskUsageTotal() = %d, want 1525", got)
+	}
+
+	// Unchanged kegs reuse the cached size instead of being walked again
+	kegDir := filepath.Join(prefix, "Caskroom", "firefox", "131.0")
+	info, _ := os.Stat(kegDir)
+	writeKegFile(t, filepath.Join(kegDir, "Firefox.app.zip"), 10)
+	if err := os.Chtimes(kegDir, time.Now(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewDataProvider().ScanDiskUsage(); err != nil {
+		t.Fatalf("ScanDiskUsage() error: %v", err)
+	}
+	cached := NewDataProvider()
+	cached.setDiskUsage(loadCachedKegs())
+	cached.ApplyDiskUsage(packages)
+	if got := packages[1].DiskUsage.Bytes; got != 1000 {
+		t.Errorf("cached firefox size = %d, want 1000", got)
+	}
+}
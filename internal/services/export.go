@@ -26,6 +26,15 @@ func (s *AppService) ExportBrewfile() (string, error) {
 	var formulae []string
 	var casks []string
 
+	// entry appends the remembered install options (args:) to a Brewfile entry
+	entry := func(pkg models.Package) string {
+		line := fmt.Sprintf("%q", pkg.Name)
+		if args := s.InstallOptions(pkg).BrewfileArgs(pkg.Type); args != "" {
+			line += ", " + args
+		}
+		return line
+	}
+
 	tapSet := make(map[string]bool)
 
 	for _, pkg := range *packages {
@@ -35,9 +44,9 @@ func (s *AppService) ExportBrewfile() (string, error) {
 
 		switch pkg.Type {
 		case models.PackageTypeCask:
-			casks = append(casks, pkg.Name)
+			casks = append(casks, entry(pkg))
 		case models.PackageTypeFormula:
-			formulae = append(formulae, pkg.Name)
+			formulae = append(formulae, entry(pkg))
 			if pkg.Formula != nil && strings.Contains(pkg.Formula.FullName, "/") {
 				parts := strings.SplitN(pkg.Formula.FullName, "/", 3)
 				if len(parts) >= 3 {
@@ -69,14 +78,14 @@ func (s *AppService) ExportBrewfile() (string, error) {
 
 	if len(formulae) > 0 {
 		for _, f := range formulae {
-			fmt.Fprintf(&sb, "brew \"%s\"\n", f)
+			fmt.Fprintf(&sb, "brew %s\n", f)
 		}
 		sb.WriteString("\n")
 	}
 
 	if len(casks) > 0 {
 		for _, c := range casks {
-			fmt.Fprintf(&sb, "cask \"%s\"\n", c)
+			fmt.Fprintf(&sb, "cask %s\n", c)
 		}
 	}
 
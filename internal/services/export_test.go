@@ -155,3 +155,41 @@ func TestExportBrewfile_OnlyNonInstalled(t *testing.T) {
 		t.Error("should not contain any brew entries for non-installed packages")
 	}
 }
+
+func TestExportBrewfile_WithInstallOptions(t *testing.T) {
+	packages := []models.Package{
+		{Name: "neovim", Type: models.PackageTypeFormula, LocallyInstalled: true},
+		{Name: "firefox", Type: models.PackageTypeCask, LocallyInstalled: true},
+		{Name: "wget", Type: models.PackageTypeFormula, LocallyInstalled: true},
+	}
+
+	s := &AppService{
+		packages: &packages,
+	}
+	s.installOptions.options = map[string]models.InstallOptions{
+		packageKey(models.PackageTypeFormula, "neovim"): {HEAD: true},
+		packageKey(models.PackageTypeCask, "firefox"):   {AppDir: "~/Applications", NoQuarantine: true},
+	}
+
+	path, err := s.ExportBrewfile()
+	if err != nil {
+		t.Fatalf("ExportBrewfile() error: %v", err)
+	}
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read exported file: %v", err)
+	}
+
+	content := string(data)
+	for _, want := range []string{
+		`brew "neovim", args: ["HEAD"]` + "\n",
+		`brew "wget"` + "\n",
+		`cask "firefox", args: { appdir: "~/Applications", no_quarantine: true }` + "\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("exported Brewfile missing %q:\n%s", want, content)
+		}
+	}
+}
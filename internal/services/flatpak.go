@@ -13,6 +13,7 @@ type FlatpakServiceInterface interface {
 	IsFlatpakInstalled() bool
 	EnsureFlathubRemote(output io.Writer) error
 	GetInstalledPackages() (map[string]bool, error)
+	ListInstalledApps() []models.Package
 	GetRemoteMetadata() (map[string]models.Package, error)
 	InstallPackage(info models.Package, output io.Writer) error
 	RemovePackage(info models.Package, output io.Writer) error
@@ -69,6 +70,42 @@ func (s *FlatpakService) GetInstalledPackages() (map[string]bool, error) {
 	return installed, nil
 }
 
+// ListInstalledApps returns the installed Flatpak applications (user and system) with
+// their name and version, or nothing when flatpak is not available.
+func (s *FlatpakService) ListInstalledApps() []models.Package {
+	var apps []models.Package
+	for _, scope := range []string{"--user", "--system"} {
+		cmd := exec.Command("flatpak", "list", scope, "--app", "--columns=application,name,version") // #nosec G204 - scope is a hardcoded constant
+		output, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		apps = append(apps, parseFlatpakList(string(output))...)
+	}
+	return apps
+}
+
+// parseFlatpakList parses the tab-separated application, name and version columns
+// of `flatpak list`.
+func parseFlatpakList(output string) []models.Package {
+	var apps []models.Package
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if strings.TrimSpace(fields[0]) == "" {
+			continue
+		}
+		pkg := models.Package{Name: strings.TrimSpace(fields[0]), Type: models.PackageTypeFlatpak, LocallyInstalled: true}
+		if len(fields) > 1 {
+			pkg.DisplayName = strings.TrimSpace(fields[1])
+		}
+		if len(fields) > 2 {
+			pkg.Version = strings.TrimSpace(fields[2])
+		}
+		apps = append(apps, pkg)
+	}
+	return apps
+}
+
 // GetRemoteMetadata fetches metadata (name, version, description) for all applications in Flathub.
 // Results are cached in memory to avoid repeated expensive `flatpak remote-ls` calls.
 func (s *FlatpakService) GetRemoteMetadata() (map[string]models.Package, error) {
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestParseFlatpakList(t *testing.T) {
+	output := "org.mozilla.firefox\tFirefox\t131.0\ncom.spotify.Client\tSpotify\t\n"
+	apps := parseFlatpakList(output)
+	if len(apps) != 2 {
+		t.Fatalf("parseFlatpakList() = %+v, want 2 apps", apps)
+	}
+	if apps[0].Name != "org.mozilla.firefox" || apps[0].DisplayName != "Firefox" || apps[0].Version != "131.0" {
+		t.Errorf("first app = %+v", apps[0])
+	}
+	if apps[1].Name != "com.spotify.Client" || apps[1].Version != "" || apps[1].Type != models.PackageTypeFlatpak || !apps[1].LocallyInstalled {
+		t.Errorf("second app = %+v", apps[1])
+	}
+	if apps := parseFlatpakList(""); len(apps) != 0 {
+		t.Errorf("parseFlatpakList(\"\") = %+v, want none", apps)
+	}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"bbrew/internal/models"
+)
+
+// Fuzzy matching scores, modelled after fzf's v1 algorithm.
+const (
+	fuzzyScoreMatch        = 16
+	fuzzyScoreGapStart     = -3
+	fuzzyScoreGapExtension = -1
+	fuzzyBonusBoundary     = 8 // Match right after a delimiter (space, -, _, /, ., @)
+	fuzzyBonusCamel        = 7 // Match on an uppercase letter following a lowercase one
+	fuzzyBonusConsecutive  = 4 // Match right after the previous match
+	fuzzyBonusFirstChar    = 2 // Multiplier for the bonus of the first pattern character
+)
+
+// Relevance weights applied on top of the raw fuzzy scores.
+const (
+	relevanceNameWeight        = 3
+	relevanceDisplayNameWeight = 2
+	relevanceAltNameWeight     = 2 // Aliases, old names and versioned formulae
+	relevanceDescWeight        = 1
+	relevanceExactNameBonus    = 1000
+	relevancePrefixNameBonus   = 300
+	relevanceExactAltBonus     = 800
+	relevancePrefixAltBonus    = 200
+	relevancePopularityWeight  = 8 // Per order of magnitude of 90d downloads
+)
+
+// searchMatch holds the relevance of a package for a query and the byte positions
+// of the matched characters, used to highlight them in the table.
+type searchMatch struct {
+	score       int
+	label       []int  // Positions in Package.Label()
+	description []int  // Positions in Package.Description
+	via         string // Alternate name that matched, e.g. "alias python3"
+}
+
+// foldByte lowercases an ASCII letter; other bytes (including UTF-8 sequences) are compared as-is.
+func foldByte(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + ('a' - 'A')
+	}
+	return b
+}
+
+// isDelimiter reports whether b separates words in package names and descriptions.
+func isDelimiter(b byte) bool {
+	switch b {
+	case ' ', '-', '_', '/', '.', '@', ',', ':', '(', ')', '+':
+		return true
+	}
+	return false
+}
+
+// matchBonus returns the positional bonus for a match at text[i].
+func matchBonus(text string, i int) int {
+	if i == 0 || isDelimiter(text[i-1]) {
+		return fuzzyBonusBoundary
+	}
+	prev, cur := text[i-1], text[i]
+	if prev >= 'a' && prev <= 'z' && cur >= 'A' && cur <= 'Z' {
+		return fuzzyBonusCamel
+	}
+	return 0
+}
+
+// fuzzyMatch reports whether every byte of pattern appears in text in order (case-insensitively),
+// returning a score and the matched byte positions. pattern must be lowercase.
+// Like fzf, it finds the first occurrence, then narrows it to the shortest window
+// ending there, so that "rg" prefers the compact match in "ripgrep" over scattered letters.
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	if pattern == "" || len(pattern) > len(text) {
+		return 0, nil, false
+	}
+
+	// Forward pass: find where the first complete match ends
+	pi, end := 0, -1
+	for i := 0; i < len(text); i++ {
+		if foldByte(text[i]) == pattern[pi] {
+			pi++
+			if pi == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// Backward pass: find the latest start that still matches the whole pattern
+	pi, start := len(pattern)-1, end
+	for i := end; i >= 0; i-- {
+		if foldByte(text[i]) == pattern[pi] {
+			pi--
+			if pi < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	// Collect positions and score them
+	positions := make([]int, 0, len(pattern))
+	score, pi, prev, inGap := 0, 0, -1, false
+	firstBonus := 0
+	for i := start; i <= end && pi < len(pattern); i++ {
+		if foldByte(text[i]) != pattern[pi] {
+			if prev >= 0 {
+				if inGap {
+					score += fuzzyScoreGapExtension
+				} else {
+					score += fuzzyScoreGapStart
+					inGap = true
+				}
+			}
+			continue
+		}
+
+		bonus := matchBonus(text, i)
+		if prev >= 0 && prev == i-1 {
+			// Consecutive chunks keep the bonus of the chunk's first character
+			bonus = max(bonus, firstBonus, fuzzyBonusConsecutive)
+		} else {
+			firstBonus = bonus
+		}
+		if pi == 0 {
+			bonus *= fuzzyBonusFirstChar
+		}
+
+		score += fuzzyScoreMatch + bonus
+		positions = append(positions, i)
+		prev, inGap = i, false
+		pi++
+	}
+
+	return score, positions, true
+}
+
+// matchPackage scores a package against the (lowercase) search terms.
+// Every term must match the name, display name, an alternate name or the description.
+// Names are matched fuzzily and weigh more; descriptions only count when the term
+// appears as a compact match, so short queries do not match scattered letters in long sentences.
+func matchPackage(pkg *models.Package, terms []string) (searchMatch, bool) {
+	label := pkg.Label()
+	lowerName := strings.ToLower(pkg.Name)
+	match := searchMatch{}
+
+	for _, term := range terms {
+		best := 0
+		matched := false
+
+		if score, positions, ok := fuzzyMatch(term, label); ok {
+			best, matched = score*relevanceNameWeight, true
+			switch {
+			case lowerName == term:
+				best += relevanceExactNameBonus
+			case strings.HasPrefix(lowerName, term):
+				best += relevancePrefixNameBonus
+			}
+			match.label = append(match.label, positions...)
+		}
+		if pkg.DisplayName != label {
+			if score, _, ok := fuzzyMatch(term, pkg.DisplayName); ok && score*relevanceDisplayNameWeight > best {
+				best, matched = score*relevanceDisplayNameWeight, true
+			}
+		}
+		if score, via, ok := matchAltNames(pkg, term); ok && score > best {
+			best, matched = score, true
+			if match.via == "" {
+				match.via = via
+			}
+		}
+		if score, positions, ok := matchDescription(term, pkg.Description); ok {
+			if score*relevanceDescWeight > best {
+				best = score * relevanceDescWeight
+			}
+			matched = true
+			match.description = append(match.description, positions...)
+		}
+		if !matched {
+			return searchMatch{}, false
+		}
+		match.score += best
+	}
+
+	if pkg.Analytics90dDownloads > 0 {
+		match.score += int(math.Log10(float64(pkg.Analytics90dDownloads)) * relevancePopularityWeight)
+	}
+	return match, true
+}
+
+// matchDescription matches a term compactly in a description: the matched characters
+// must span less than twice the term. The first match found is not always the tightest
+// ("abc" in "abxxxxxxxxc abc"), so the match is retried after each rejected start.
+func matchDescription(term, desc string) (int, []int, bool) {
+	for offset := 0; offset < len(desc); {
+		score, positions, ok := fuzzyMatch(term, desc[offset:])
+		if !ok {
+			return 0, nil, false
+		}
+		if positions[len(positions)-1]-positions[0] < 2*len(term) {
+			for i := range positions {
+				positions[i] += offset
+			}
+			return score, positions, true
+		}
+		offset += positions[0] + 1
+	}
+	return 0, nil, false
+}
+
+// matchAltNames returns the best score of the term against the aliases, old names and
+// versioned formulae of a formula, or the old tokens of a cask, with a description of
+// the alternate name that matched.
+func matchAltNames(pkg *models.Package, term string) (int, string, bool) {
+	best, via := 0, ""
+	try := func(kind string, names []string) {
+		for _, name := range names {
+			score, _, ok := fuzzyMatch(term, name)
+			if !ok {
+				continue
+			}
+			score *= relevanceAltNameWeight
+			switch lower := strings.ToLower(name); {
+			case lower == term:
+				score += relevanceExactAltBonus
+			case strings.HasPrefix(lower, term):
+				score += relevancePrefixAltBonus
+			}
+			if score > best {
+				best, via = score, kind+" "+name
+			}
+		}
+	}
+
+	switch {
+	case pkg.Formula != nil:
+		try("alias", pkg.Formula.Aliases)
+		try("old name", pkg.Formula.OldNames)
+		try("versioned formula", pkg.Formula.VersionedFormulae)
+	case pkg.Cask != nil:
+		try("old token", pkg.Cask.OldTokens)
+	}
+	return best, via, best > 0
+}
+
+// rankPackages returns the packages matching all terms ordered by relevance
+// (best first), along with the match details keyed by package name.
+func rankPackages(list []models.Package, terms []string) ([]models.Package, map[string]searchMatch) {
+	type scored struct {
+		index, score int
+	}
+
+	matches := make(map[string]searchMatch)
+	hits := make([]scored, 0, 256)
+	for i := range list {
+		pkg := &list[i]
+		if _, seen := matches[pkg.Name]; seen {
+			continue
+		}
+		if match, ok := matchPackage(pkg, terms); ok {
+			matches[pkg.Name] = match
+			hits = append(hits, scored{index: i, score: match.score})
+		}
+	}
+
+	// Sort the small index entries rather than the packages themselves; equal
+	// scores keep the source order
+	slices.SortFunc(hits, func(a, b scored) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		return a.index - b.index
+	})
+
+	result := make([]models.Package, len(hits))
+	for i, hit := range hits {
+		result[i] = list[hit.index]
+	}
+	return result, matches
+}
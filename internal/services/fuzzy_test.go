@@ -0,0 +1,204 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		wantOK    bool
+		wantMatch []int
+	}{
+		{"rg", "ripgrep", true, []int{0, 3}},
+		{"rep", "ripgrep", true, []int{4, 5, 6}}, // Shortest window wins over r-e-p spread from 0
+		{"rgp", "ripgrep", true, []int{0, 3, 6}},
+		{"gr", "ripgrep", true, []int{3, 4}},
+		{"jq", "jq", true, []int{0, 1}},
+		{"JQ", "jq", false, nil}, // Pattern must be lowercase
+		{"qj", "jq", false, nil},
+		{"node", "Node.js", true, []int{0, 1, 2, 3}},
+		{"toolong", "tool", false, nil},
+		{"", "anything", false, nil},
+	}
+
+	for _, tt := range tests {
+		_, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+		if ok != tt.wantOK {
+			t.Errorf("fuzzyMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.wantOK)
+			continue
+		}
+		if fmt.Sprint(positions) != fmt.Sprint(tt.wantMatch) && tt.wantOK {
+			t.Errorf("fuzzyMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.wantMatch)
+		}
+	}
+}
+
+func TestFuzzyMatch_PrefersBoundariesAndRuns(t *testing.T) {
+	boundary, _, _ := fuzzyMatch("gh", "git-hub")
+	middle, _, _ := fuzzyMatch("gh", "xgxh")
+	if boundary <= middle {
+		t.Errorf("boundary match scored %d, want more than %d", boundary, middle)
+	}
+
+	run, _, _ := fuzzyMatch("rip", "ripgrep")
+	scattered, _, _ := fuzzyMatch("rip", "r-i-p")
+	if run <= scattered {
+		t.Errorf("consecutive match scored %d, want more than %d", run, scattered)
+	}
+}
+
+func TestRankPackages_NamesBeforeDescriptions(t *testing.T) {
+	packages := []models.Package{
+		{Name: "grep-tools", Description: "Search utilities", Type: models.PackageTypeFormula},
+		{Name: "burger", Description: "Fast drg tool", Type: models.PackageTypeFormula, Analytics90dDownloads: 1_000_000},
+		{Name: "ripgrep", Description: "Search tool like grep", Type: models.PackageTypeFormula, Analytics90dDownloads: 100_000},
+		{Name: "rg", Description: "Exact name", Type: models.PackageTypeFormula},
+		{Name: "unrelated", Description: "Really good thing", Type: models.PackageTypeFormula},
+	}
+
+	result, matches := rankPackages(packages, []string{"rg"})
+
+	var names []string
+	for _, pkg := range result {
+		names = append(names, pkg.Name)
+	}
+	if len(names) < 2 || names[0] != "rg" || names[1] != "ripgrep" {
+		t.Fatalf("ranking = %v, want rg then ripgrep first", names)
+	}
+	for _, name := range names {
+		if name == "unrelated" {
+			t.Errorf("scattered description letters should not match: %v", names)
+		}
+	}
+	if got := matches["ripgrep"].label; fmt.Sprint(got) != "[0 3]" {
+		t.Errorf("ripgrep label positions = %v, want [0 3]", got)
+	}
+}
+
+func TestRankPackages_AllTermsMustMatch(t *testing.T) {
+	packages := []models.Package{
+		{Name: "jq", Description: "Lightweight and flexible command-line JSON processor"},
+		{Name: "yq", Description: "Process YAML, JSON, XML, CSV and properties documents"},
+		{Name: "fx", Description: "Terminal JSON viewer"},
+	}
+
+	result, _ := rankPackages(packages, []string{"json", "yaml"})
+	if len(result) != 1 || result[0].Name != "yq" {
+		t.Errorf("rankPackages(json yaml) = %v, want only yq", result)
+	}
+}
+
+func TestMatchDescription_FindsLaterCompactMatch(t *testing.T) {
+	_, positions, ok := matchDescription("abc", "abxxxxxxxxc abc")
+	if !ok {
+		t.Fatal("matchDescription should find the substring after the scattered match")
+	}
+	if want := []int{12, 13, 14}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+	if _, _, ok := matchDescription("abc", "abxxxxxxxxc"); ok {
+		t.Error("matchDescription should reject scattered letters")
+	}
+
+	packages := []models.Package{{Name: "tool", Description: "abxxxxxxxxc abc"}}
+	if result, _ := rankPackages(packages, []string{"abc"}); len(result) != 1 {
+		t.Errorf("rankPackages(abc) = %v, want the package matched by its description", result)
+	}
+}
+
+func TestRankPackages_PopularityBreaksTies(t *testing.T) {
+	packages := []models.Package{
+		{Name: "node-a", Analytics90dDownloads: 10},
+		{Name: "node-b", Analytics90dDownloads: 1_000_000},
+	}
+
+	result, _ := rankPackages(packages, []string{"node"})
+	if result[0].Name != "node-b" {
+		t.Errorf("expected the more popular package first, got %s", result[0].Name)
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	tests := []struct {
+		text      string
+		positions []int
+		want      string
+	}{
+		{"ripgrep", nil, "ripgrep"},
+		{"ripgrep", []int{5, 6}, "ripgr[::bu]ep[::-]"},
+		{"ripgrep", []int{0, 1, 2, 3, 4, 5, 6}, "[::bu]ripgrep[::-]"},
+		{"ripgrep", []int{0, 3}, "[::bu]r[::-]ip[::bu]g[::-]rep"},
+		{"[tool]", []int{1}, "[[::bu]t[::-]ool]"},
+		{"café", []int{3}, "caf[::bu]é[::-]"},
+	}
+
+	for _, tt := range tests {
+		if got := highlightMatches(tt.text, tt.positions); got != tt.want {
+			t.Errorf("highlightMatches(%q, %v) = %q, want %q", tt.text, tt.positions, got, tt.want)
+		}
+	}
+}
+
+// syntheticPackages builds n packages with realistic name and description lengths.
+func syntheticPackages(n int) []models.Package {
+	words := []string{"fast", "json", "parser", "library", "terminal", "grep", "python", "server", "client", "tool", "image", "network"}
+	packages := make([]models.Package, n)
+	for i := range packages {
+		packages[i] = models.Package{
+			Name:                  fmt.Sprintf("%s-%s-%d", words[i%len(words)], words[(i/7)%len(words)], i),
+			Description:           fmt.Sprintf("A %s %s for %s and %s workflows", words[(i/3)%len(words)], words[(i/5)%len(words)], words[(i/11)%len(words)], words[(i/13)%len(words)]),
+			Type:                  models.PackageTypeFormula,
+			Analytics90dDownloads: i * 37 % 100_000,
+		}
+	}
+	return packages
+}
+
+func benchmarkRankPackages(b *testing.B, terms ...string) {
+	packages := syntheticPackages(15_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		rankPackages(packages, terms)
+	}
+}
+
+func BenchmarkRankPackages_Short(b *testing.B)     { benchmarkRankPackages(b, "rg") }
+func BenchmarkRankPackages_Word(b *testing.B)      { benchmarkRankPackages(b, "python") }
+func BenchmarkRankPackages_MultiTerm(b *testing.B) { benchmarkRankPackages(b, "json", "parser") }
+
+func TestRankPackages_AlternateNames(t *testing.T) {
+	packages := []models.Package{
+		{Name: "python-tools", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "python-tools"}},
+		{Name: "python@3.13", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "python@3.13", Aliases: []string{"python3"}}},
+		{Name: "visual-studio-code", Type: models.PackageTypeCask, Cask: &models.Cask{Token: "visual-studio-code", OldTokens: []string{"vscode"}}},
+		{Name: "httpie", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "httpie", OldNames: []string{"http-prompt-legacy"}}},
+	}
+
+	tests := []struct {
+		term    string
+		wantTop string
+		wantVia string
+	}{
+		{"python3", "python@3.13", "alias python3"},
+		{"vscode", "visual-studio-code", "old token vscode"},
+		{"http-prompt-legacy", "httpie", "old name http-prompt-legacy"},
+		{"httpie", "httpie", ""}, // The current name needs no explanation
+	}
+
+	for _, tt := range tests {
+		result, matches := rankPackages(packages, []string{tt.term})
+		if len(result) == 0 || result[0].Name != tt.wantTop {
+			t.Errorf("%q: top result = %v, want %s", tt.term, result, tt.wantTop)
+			continue
+		}
+		if via := matches[tt.wantTop].via; via != tt.wantVia {
+			t.Errorf("%q: via = %q, want %q", tt.term, via, tt.wantVia)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 
 	"bbrew/internal/models"
 	"bbrew/internal/ui"
@@ -20,6 +22,13 @@ const (
 	FilterLeaves
 	FilterCasks
 	FilterFormulae
+	FilterTrending
+	FilterOrphans
+	FilterPinned
+	FilterServices
+	FilterUnlinked
+	FilterNoBottle
+	FilterDeprecated
 )
 
 // InputAction represents a user action that can be triggered by a key event.
@@ -44,28 +53,57 @@ type InputService struct {
 	layout         ui.LayoutInterface
 	brewService    BrewServiceInterface
 	flatpakService FlatpakServiceInterface
+	jobs           *jobQueue
+	jobsPanelDone  chan struct{} // Closed when the jobs panel closes; nil while it is closed
 	keyActions     []*InputAction
 	legendEntries  []struct{ KeySlug, Name string }
 
 	// Actions for each key input
-	ActionSearch          *InputAction
-	ActionFilterInstalled *InputAction
-	ActionFilterOutdated  *InputAction
-	ActionFilterLeaves    *InputAction
-	ActionFilterCasks     *InputAction
-	ActionFilterFormulae  *InputAction
-	ActionSort            *InputAction
-	ActionExport          *InputAction
-	ActionVulnScan        *InputAction
-	ActionInstall         *InputAction
-	ActionUpdate          *InputAction
-	ActionRemove          *InputAction
-	ActionUpdateAll       *InputAction
-	ActionInstallAll      *InputAction
-	ActionRemoveAll       *InputAction
-	ActionHelp            *InputAction
-	ActionBack            *InputAction
-	ActionQuit            *InputAction
+	ActionSearch           *InputAction
+	ActionFilterInstalled  *InputAction
+	ActionFilterOutdated   *InputAction
+	ActionFilterLeaves     *InputAction
+	ActionFilterCasks      *InputAction
+	ActionFilterFormulae   *InputAction
+	ActionFilterTrending   *InputAction
+	ActionTrendPeriod      *InputAction
+	ActionFilterOrphans    *InputAction
+	ActionAutoremove       *InputAction
+	ActionFilterPinned     *InputAction
+	ActionPin              *InputAction
+	ActionServices         *InputAction
+	ActionFilterUnlinked   *InputAction
+	ActionFilterNoBottle   *InputAction
+	ActionFilterDeprecated *InputAction
+	ActionMigrate          *InputAction
+	ActionMark             *InputAction
+	ActionMarkAll          *InputAction
+	ActionJobs             *InputAction
+	ActionLink             *InputAction
+	ActionInstallOptions   *InputAction
+	ActionSwitchVersion    *InputAction
+	ActionUnlink           *InputAction
+	ActionFilterServices   *InputAction
+	ActionSort             *InputAction
+	ActionReverseSort      *InputAction
+	ActionAnalyticsPeriod  *InputAction
+	ActionAnalyticsMetric  *InputAction
+	ActionSizeColumn       *InputAction
+	ActionLicenseColumn    *InputAction
+	ActionExport           *InputAction
+	ActionNews             *InputAction
+	ActionDependencyTree   *InputAction
+	ActionCaveats          *InputAction
+	ActionVulnScan         *InputAction
+	ActionInstall          *InputAction
+	ActionUpdate           *InputAction
+	ActionRemove           *InputAction
+	ActionUpdateAll        *InputAction
+	ActionInstallAll       *InputAction
+	ActionRemoveAll        *InputAction
+	ActionHelp             *InputAction
+	ActionBack             *InputAction
+	ActionQuit             *InputAction
 }
 
 var NewInputService = func(appService *AppService, brewService BrewServiceInterface, flatpakService FlatpakServiceInterface) InputServiceInterface {
@@ -75,6 +113,7 @@ var NewInputService = func(appService *AppService, brewService BrewServiceInterf
 		brewService:    brewService,
 		flatpakService: flatpakService,
 	}
+	s.jobs = newJobQueue(s.runJob, s.handleJobChange, s.handleJobsIdle)
 
 	// Initialize actions with key bindings and handlers
 	s.ActionSearch = &InputAction{
@@ -101,14 +140,122 @@ var NewInputService = func(appService *AppService, brewService BrewServiceInterf
 		Key: tcell.KeyRune, Rune: 'F', KeySlug: "F", Name: "Formulae",
 		Action: s.handleFilterFormulaeEvent, HideFromLegend: true,
 	}
+	s.ActionFilterTrending = &InputAction{
+		Key: tcell.KeyRune, Rune: 't', KeySlug: "t", Name: "Trending",
+		Action: s.handleFilterTrendingEvent, HideFromLegend: true,
+	}
+	s.ActionTrendPeriod = &InputAction{
+		Key: tcell.KeyRune, Rune: 'T', KeySlug: "T", Name: "Trend Period",
+		Action: s.handleTrendPeriodEvent, HideFromLegend: true,
+	}
+	s.ActionFilterOrphans = &InputAction{
+		Key: tcell.KeyRune, Rune: 'O', KeySlug: "O", Name: "Orphans",
+		Action: s.handleFilterOrphansEvent, HideFromLegend: true,
+	}
+	s.ActionAutoremove = &InputAction{
+		Key: tcell.KeyRune, Rune: 'X', KeySlug: "X", Name: "Autoremove",
+		Action: s.handleAutoremoveEvent, HideFromLegend: true,
+	}
+	s.ActionFilterPinned = &InputAction{
+		Key: tcell.KeyRune, Rune: 'P', KeySlug: "P", Name: "Pinned",
+		Action: s.handleFilterPinnedEvent, HideFromLegend: true,
+	}
+	s.ActionPin = &InputAction{
+		Key: tcell.KeyRune, Rune: 'p', KeySlug: "p", Name: "Pin/Unpin",
+		Action: s.handlePinPackageEvent, HideFromLegend: true,
+	}
+	s.ActionFilterServices = &InputAction{
+		Key: tcell.KeyRune, Rune: 'B', KeySlug: "B", Name: "Services",
+		Action: s.handleFilterServicesEvent, HideFromLegend: true,
+	}
+	s.ActionServices = &InputAction{
+		Key: tcell.KeyRune, Rune: 'b', KeySlug: "b", Name: "Services",
+		Action: s.handleServicesEvent, HideFromLegend: true,
+	}
+	s.ActionFilterUnlinked = &InputAction{
+		Key: tcell.KeyRune, Rune: 'w', KeySlug: "w", Name: "Unlinked",
+		Action: s.handleFilterUnlinkedEvent, HideFromLegend: true,
+	}
+	s.ActionInstallOptions = &InputAction{
+		Key: tcell.KeyRune, Rune: 'I', KeySlug: "I", Name: "Install with options",
+		Action: s.handleInstallOptionsEvent, HideFromLegend: true,
+	}
+	s.ActionSwitchVersion = &InputAction{
+		Key: tcell.KeyRune, Rune: 'V', KeySlug: "V", Name: "Switch version",
+		Action: s.handleSwitchVersionEvent, HideFromLegend: true,
+	}
+	s.ActionFilterNoBottle = &InputAction{
+		Key: tcell.KeyRune, Rune: 'N', KeySlug: "N", Name: "No bottle",
+		Action: s.handleFilterNoBottleEvent, HideFromLegend: true,
+	}
+	s.ActionFilterDeprecated = &InputAction{
+		Key: tcell.KeyRune, Rune: 'D', KeySlug: "D", Name: "Deprecated",
+		Action: s.handleFilterDeprecatedEvent, HideFromLegend: true,
+	}
+	s.ActionMigrate = &InputAction{
+		Key: tcell.KeyRune, Rune: 'm', KeySlug: "m", Name: "Migrate",
+		Action: s.handleMigrateEvent, HideFromLegend: true,
+	}
+	s.ActionMark = &InputAction{
+		Key: tcell.KeyRune, Rune: ' ', KeySlug: "space", Name: "Mark",
+		Action: s.handleMarkEvent, HideFromLegend: true,
+	}
+	s.ActionMarkAll = &InputAction{
+		Key: tcell.KeyRune, Rune: '*', KeySlug: "*", Name: "Mark All",
+		Action: s.handleMarkAllEvent, HideFromLegend: true,
+	}
+	s.ActionJobs = &InputAction{
+		Key: tcell.KeyRune, Rune: 'J', KeySlug: "J", Name: "Jobs",
+		Action: s.handleJobsEvent, HideFromLegend: true,
+	}
+	s.ActionLink = &InputAction{
+		Key: tcell.KeyRune, Rune: 'K', KeySlug: "K", Name: "Link",
+		Action: s.handleLinkPackageEvent, HideFromLegend: true,
+	}
+	s.ActionUnlink = &InputAction{
+		Key: tcell.KeyRune, Rune: 'U', KeySlug: "U", Name: "Unlink",
+		Action: s.handleUnlinkPackageEvent, HideFromLegend: true,
+	}
 	s.ActionSort = &InputAction{
 		Key: tcell.KeyRune, Rune: 's', KeySlug: "s", Name: "Sort",
 		Action: s.handleSortEvent,
 	}
+	s.ActionReverseSort = &InputAction{
+		Key: tcell.KeyRune, Rune: 'S', KeySlug: "S", Name: "Reverse Sort",
+		Action: s.handleReverseSortEvent, HideFromLegend: true,
+	}
+	s.ActionAnalyticsPeriod = &InputAction{
+		Key: tcell.KeyRune, Rune: 'a', KeySlug: "a", Name: "Analytics Window",
+		Action: s.handleAnalyticsPeriodEvent, HideFromLegend: true,
+	}
+	s.ActionAnalyticsMetric = &InputAction{
+		Key: tcell.KeyRune, Rune: 'A', KeySlug: "A", Name: "Analytics Metric",
+		Action: s.handleAnalyticsMetricEvent, HideFromLegend: true,
+	}
+	s.ActionSizeColumn = &InputAction{
+		Key: tcell.KeyRune, Rune: 'Z', KeySlug: "Z", Name: "Size Column",
+		Action: s.handleSizeColumnEvent, HideFromLegend: true,
+	}
+	s.ActionLicenseColumn = &InputAction{
+		Key: tcell.KeyRune, Rune: 'L', KeySlug: "L", Name: "License Column",
+		Action: s.handleLicenseColumnEvent, HideFromLegend: true,
+	}
 	s.ActionExport = &InputAction{
 		Key: tcell.KeyRune, Rune: 'e', KeySlug: "e", Name: "Export",
 		Action: s.handleExportEvent,
 	}
+	s.ActionNews = &InputAction{
+		Key: tcell.KeyRune, Rune: 'n', KeySlug: "n", Name: "What's New",
+		Action: s.handleNewsEvent, HideFromLegend: true,
+	}
+	s.ActionDependencyTree = &InputAction{
+		Key: tcell.KeyRune, Rune: 'd', KeySlug: "d", Name: "Dependency Tree",
+		Action: s.handleDependencyTreeEvent, HideFromLegend: true,
+	}
+	s.ActionCaveats = &InputAction{
+		Key: tcell.KeyRune, Rune: 'C', KeySlug: "C", Name: "Caveats",
+		Action: s.handleCaveatsEvent, HideFromLegend: true,
+	}
 	s.ActionVulnScan = &InputAction{
 		Key: tcell.KeyRune, Rune: 'v', KeySlug: "v", Name: "Vuln Scan",
 		Action: s.handleVulnScanEvent,
@@ -154,8 +301,16 @@ var NewInputService = func(appService *AppService, brewService BrewServiceInterf
 	s.keyActions = []*InputAction{
 		s.ActionSearch, s.ActionFilterInstalled, s.ActionFilterOutdated,
 		s.ActionFilterLeaves, s.ActionFilterCasks, s.ActionFilterFormulae,
-		s.ActionSort, s.ActionExport, s.ActionVulnScan, s.ActionInstall,
-		s.ActionUpdate, s.ActionRemove, s.ActionUpdateAll, s.ActionHelp,
+		s.ActionFilterTrending, s.ActionTrendPeriod, s.ActionFilterOrphans, s.ActionAutoremove,
+		s.ActionFilterPinned, s.ActionFilterServices, s.ActionFilterUnlinked, s.ActionFilterNoBottle,
+		s.ActionFilterDeprecated,
+		s.ActionSort, s.ActionReverseSort, s.ActionAnalyticsPeriod, s.ActionAnalyticsMetric,
+		s.ActionSizeColumn, s.ActionLicenseColumn,
+		s.ActionExport, s.ActionNews, s.ActionDependencyTree, s.ActionCaveats, s.ActionServices, s.ActionVulnScan,
+		s.ActionInstall, s.ActionInstallOptions, s.ActionUpdate, s.ActionRemove, s.ActionPin,
+		s.ActionLink, s.ActionUnlink, s.ActionSwitchVersion, s.ActionMigrate,
+		s.ActionMark, s.ActionMarkAll, s.ActionJobs,
+		s.ActionUpdateAll, s.ActionHelp,
 		s.ActionBack, s.ActionQuit,
 	}
 
@@ -199,6 +354,10 @@ func (s *InputService) HandleKeyEventInput(event *tcell.EventKey) *tcell.EventKe
 	if s.layout.GetSearch().Field().HasFocus() {
 		return event
 	}
+	// Overlays handle their own keys (navigation and closing)
+	if s.overlayHasFocus() {
+		return event
+	}
 
 	for _, input := range s.keyActions {
 		if event.Modifiers() == tcell.ModNone && input.Key == event.Key() && input.Rune == event.Rune() { // Check Rune
@@ -217,9 +376,46 @@ func (s *InputService) HandleKeyEventInput(event *tcell.EventKey) *tcell.EventKe
 	return event
 }
 
+// overlayHasFocus reports whether an overlay with its own keys (news, dependency tree,
+// caveats, services, jobs, install options, version switch) is open.
+func (s *InputService) overlayHasFocus() bool {
+	if view := s.layout.GetNewsScreen().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	if view := s.layout.GetDependencyTree().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	if view := s.layout.GetCaveatsScreen().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	if view := s.layout.GetServicesPanel().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	if view := s.layout.GetJobsPanel().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	if view := s.layout.GetInstallOptionsDialog().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	if view := s.layout.GetVersionSwitchDialog().View(); view != nil && view.HasFocus() {
+		return true
+	}
+	return false
+}
+
+// setRoot replaces the screen, closing the jobs panel if it was open so its
+// watcher stops redrawing it.
+func (s *InputService) setRoot(root tview.Primitive) {
+	if s.jobsPanelDone != nil {
+		close(s.jobsPanelDone)
+		s.jobsPanelDone = nil
+	}
+	s.appService.GetApp().SetRoot(root, true)
+}
+
 // handleBack is called when the user presses the back key (Esc).
 func (s *InputService) handleBack() {
-	s.appService.GetApp().SetRoot(s.layout.Root(), true)
+	s.setRoot(s.layout.Root())
 	s.appService.GetApp().SetFocus(s.layout.GetTable().View())
 }
 
@@ -242,12 +438,329 @@ func (s *InputService) handleHelpEvent() {
 	// Set up key handler to close help on any key press
 	helpPages.SetInputCapture(func(_ *tcell.EventKey) *tcell.EventKey {
 		// Close help and return to main view
-		s.appService.GetApp().SetRoot(s.layout.Root(), true)
+		s.setRoot(s.layout.Root())
 		s.appService.GetApp().SetFocus(s.layout.GetTable().View())
 		return nil
 	})
 
-	s.appService.GetApp().SetRoot(helpPages, true)
+	s.setRoot(helpPages)
+}
+
+// handleNewsEvent shows the packages added, removed, deprecated or disabled in Homebrew.
+func (s *InputService) handleNewsEvent() {
+	items, err := s.appService.dataProvider.GetNews()
+	if err != nil {
+		s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to load news: %v", err))
+		return
+	}
+
+	newsScreen := s.layout.GetNewsScreen()
+	newsPages := newsScreen.Build(s.layout.Root(), items)
+	newsPages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' || event.Rune() == 'n' {
+			s.handleBack()
+			return nil
+		}
+		return event
+	})
+
+	s.setRoot(newsPages)
+	s.appService.GetApp().SetFocus(newsScreen.View())
+}
+
+// handleDependencyTreeEvent shows the full dependency tree of the selected package
+// and the installed packages that depend on it.
+func (s *InputService) handleDependencyTreeEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row <= 0 || row-1 >= len(*s.appService.filteredPackages) {
+		return
+	}
+	info := (*s.appService.filteredPackages)[row-1]
+	if info.Type != models.PackageTypeFormula && info.Type != models.PackageTypeCask {
+		s.layout.GetNotifier().ShowWarning("Dependency tree only available for Homebrew packages")
+		return
+	}
+
+	deps, usedBy := s.appService.dependencyTrees(info)
+	depTree := s.layout.GetDependencyTree()
+	treePages := depTree.Build(s.layout.Root(), &info, deps, usedBy, s.jumpToPackage)
+	treePages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q' || event.Rune() == 'd':
+			s.handleBack()
+			return nil
+		case event.Key() == tcell.KeyRight:
+			depTree.ExpandCurrent(true)
+			return nil
+		case event.Key() == tcell.KeyLeft:
+			depTree.ExpandCurrent(false)
+			return nil
+		}
+		return event
+	})
+
+	s.setRoot(treePages)
+	s.appService.GetApp().SetFocus(depTree.View())
+}
+
+// jumpToPackage closes the dependency tree and selects a package in the table,
+// clearing the search and filter when they hide it.
+func (s *InputService) jumpToPackage(pkgType models.PackageType, name string) {
+	s.handleBack()
+	if s.appService.selectPackageOfType(pkgType, name) {
+		return
+	}
+
+	s.appService.activeFilter = FilterNone
+	s.updateFilterUI()
+	if s.layout.GetSearch().Field().GetText() != "" {
+		s.layout.GetSearch().Field().SetText("") // Searches again through the changed handler
+	} else {
+		s.appService.search("", true)
+	}
+	if !s.appService.selectPackageOfType(pkgType, name) {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s is not in the package list", name))
+	}
+}
+
+// handleCaveatsEvent shows the caveats of the selected installed package again.
+func (s *InputService) handleCaveatsEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row <= 0 || row-1 >= len(*s.appService.filteredPackages) {
+		return
+	}
+	info := (*s.appService.filteredPackages)[row-1]
+	if !info.LocallyInstalled {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s is not installed", info.Label()))
+		return
+	}
+	if info.Caveats == "" {
+		s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("%s has no caveats", info.Label()))
+		return
+	}
+	s.showCaveats(info.Label(), []models.Package{info})
+}
+
+// showCaveatsAfter shows the caveats of packages just installed or upgraded, read from
+// the refreshed package list. It is called from the goroutine running the operation.
+func (s *InputService) showCaveatsAfter(title string, packages []models.Package) {
+	if withCaveats := s.appService.withCaveats(packages); len(withCaveats) > 0 {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.showCaveats(title, withCaveats)
+		})
+	}
+}
+
+// showCaveats opens the caveats overlay, which stays open until dismissed.
+func (s *InputService) showCaveats(title string, packages []models.Package) {
+	caveatsScreen := s.layout.GetCaveatsScreen()
+	caveatsPages := caveatsScreen.Build(s.layout.Root(), title, packages)
+	caveatsPages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter || event.Rune() == 'q' || event.Rune() == 'C' {
+			s.handleBack()
+			return nil
+		}
+		return event
+	})
+
+	s.setRoot(caveatsPages)
+	s.appService.GetApp().SetFocus(caveatsScreen.View())
+}
+
+// serviceKeys maps the keys of the services panel to brew services actions.
+var serviceKeys = map[rune]string{'s': "start", 'x': "stop", 'r': "restart", 'o': "run"}
+
+// handleServicesEvent opens the panel of formula background services (brew services).
+func (s *InputService) handleServicesEvent() {
+	panel := s.layout.GetServicesPanel()
+	panelPages := panel.Build(s.layout.Root())
+	panelPages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' || event.Rune() == 'b' {
+			s.handleBack()
+			return nil
+		}
+		if action, ok := serviceKeys[event.Rune()]; ok {
+			if name, ok := panel.Selected(); ok {
+				s.runServiceAction(action, name)
+			}
+			return nil
+		}
+		return event
+	})
+
+	s.setRoot(panelPages)
+	s.appService.GetApp().SetFocus(panel.View())
+	go s.refreshServices()
+}
+
+// refreshServices loads the service statuses into the services panel.
+func (s *InputService) refreshServices() {
+	services, err := s.appService.LoadServices()
+	s.appService.app.QueueUpdateDraw(func() {
+		if err != nil {
+			s.layout.GetServicesPanel().SetMessage(fmt.Sprintf("Failed to load services: %v", err))
+			return
+		}
+		s.layout.GetServicesPanel().SetServices(services)
+	})
+}
+
+// runServiceAction starts, stops, restarts or runs a service, then reloads the statuses.
+func (s *InputService) runServiceAction(action, name string) {
+	s.layout.GetOutput().Clear()
+	s.layout.GetNotifier().ShowWarning(fmt.Sprintf("brew services %s %s...", action, name))
+	go func() {
+		err := s.brewService.ServiceAction(action, name, s.outputWriter())
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to %s %s: see output for details", action, name))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("brew services %s %s: done", action, name))
+		})
+		s.refreshServices()
+	}()
+}
+
+// handleMarkEvent marks or unmarks the selected row for a batch of jobs.
+func (s *InputService) handleMarkEvent() {
+	if s.appService.toggleMarkSelected() {
+		s.showMarkedCount()
+	}
+}
+
+// handleMarkAllEvent marks all the rows shown, or clears the marks when they are all marked.
+func (s *InputService) handleMarkAllEvent() {
+	s.appService.markAllVisible()
+	s.showMarkedCount()
+}
+
+// showMarkedCount tells how many rows are marked and what to do with them.
+func (s *InputService) showMarkedCount() {
+	count := len(s.appService.marked)
+	if count == 0 {
+		s.layout.GetNotifier().ShowSuccess("No package marked")
+		return
+	}
+	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("%d package%s marked: i, r or u queues them", count, pluralS(count)))
+}
+
+// enqueueMarked asks to queue an action for the marked packages, leaving out those
+// it does not apply to. While jobs are running, the selected row is queued when none
+// is marked, so that it does not run next to them. It reports false when the action
+// is not queued.
+func (s *InputService) enqueueMarked(action models.JobAction) bool {
+	marked := s.appService.markedPackages()
+	if len(marked) == 0 && s.jobs.Pending() > 0 {
+		row, _ := s.layout.GetTable().View().GetSelection()
+		if row > 0 && row-1 < len(*s.appService.filteredPackages) {
+			marked = []models.Package{(*s.appService.filteredPackages)[row-1]}
+		}
+	}
+	if len(marked) == 0 {
+		return false
+	}
+
+	var runnable []models.Package
+	var skipped []string
+	for _, pkg := range marked {
+		if reason, skip := jobSkipReason(action, pkg); skip {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", pkg.Label(), reason))
+			continue
+		}
+		runnable = append(runnable, pkg)
+	}
+	if len(runnable) == 0 {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Nothing to %s: %s", action, strings.Join(skipped, ", ")))
+		return true
+	}
+
+	text := fmt.Sprintf("Queue %s of %d package%s?\n\n%s",
+		action, len(runnable), pluralS(len(runnable)), packageNames(runnable, 8))
+	if len(skipped) > 0 {
+		text += fmt.Sprintf("\n\nSkipped: %s", strings.Join(skipped, ", "))
+	}
+	s.showModal(text, func() {
+		s.closeModal()
+		added := s.jobs.Enqueue(action, runnable)
+		s.appService.clearMarks()
+		s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Queued %d job%s: press J to follow them", len(added), pluralS(len(added))))
+	}, s.closeModal)
+	return true
+}
+
+// handleJobsEvent opens the panel of queued, running and finished jobs.
+func (s *InputService) handleJobsEvent() {
+	panel := s.layout.GetJobsPanel()
+	panelPages := panel.Build(s.layout.Root(), func(job models.Job) {
+		panel.SetLog(s.jobs.Log(job.ID))
+	})
+	panelPages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q' || event.Rune() == 'J':
+			s.handleBack()
+		case event.Rune() == 'r':
+			if job, ok := panel.Selected(); ok {
+				if !s.jobs.Retry(job.ID) {
+					s.layout.GetNotifier().ShowWarning("Only failed jobs can be retried")
+				}
+				s.refreshJobsPanel()
+			}
+		case event.Rune() == 'c':
+			s.jobs.ClearSucceeded()
+			s.refreshJobsPanel()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	s.setRoot(panelPages) // Also stops the watcher of a panel opened before
+	s.appService.GetApp().SetFocus(panel.View())
+	s.jobsPanelDone = make(chan struct{})
+	s.refreshJobsPanel()
+	go s.watchJobsPanel(s.jobsPanelDone)
+}
+
+// refreshJobsPanel shows the current jobs and the output of the selected one,
+// while the jobs panel is open.
+func (s *InputService) refreshJobsPanel() {
+	if s.jobsPanelDone == nil {
+		return
+	}
+	panel := s.layout.GetJobsPanel()
+	panel.SetJobs(s.jobs.Jobs())
+	if job, ok := panel.Selected(); ok {
+		panel.SetLog(s.jobs.Log(job.ID))
+	}
+}
+
+// handleJobChange is called by the job queue when a job starts or finishes.
+func (s *InputService) handleJobChange() {
+	s.appService.app.QueueUpdateDraw(func() {
+		s.refreshJobsPanel()
+		for _, job := range s.jobs.Jobs() {
+			if job.Status == models.JobRunning {
+				left := s.jobs.Pending()
+				s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s %s... (%d job%s left, J to follow)",
+					job.Action.Verb(), job.Package.Label(), left, pluralS(left)))
+			}
+		}
+	})
+}
+
+// handleJobsIdle is called by the job queue once it has run all jobs: the package
+// lists are reloaded and the outcome is reported.
+func (s *InputService) handleJobsIdle(succeeded, failed int) {
+	s.appService.forceRefreshResults()
+	s.appService.app.QueueUpdateDraw(func() {
+		s.refreshJobsPanel()
+		if failed > 0 {
+			s.layout.GetNotifier().ShowError(fmt.Sprintf("Jobs done: %d succeeded, %d failed (press J for the logs)", succeeded, failed))
+			return
+		}
+		s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Jobs done: %d succeeded", succeeded))
+	})
 }
 
 // handleFilterEvent toggles the filter for packages based on the provided filter type.
@@ -273,11 +786,18 @@ func (s *InputService) updateFilterUI() {
 		suffix  string
 		keySlug string
 	}{
-		FilterInstalled: {"Installed", s.ActionFilterInstalled.KeySlug},
-		FilterOutdated:  {"Outdated", s.ActionFilterOutdated.KeySlug},
-		FilterLeaves:    {"Leaves", s.ActionFilterLeaves.KeySlug},
-		FilterCasks:     {"Casks", s.ActionFilterCasks.KeySlug},
-		FilterFormulae:  {"Formulae", s.ActionFilterFormulae.KeySlug},
+		FilterInstalled:  {"Installed", s.ActionFilterInstalled.KeySlug},
+		FilterOutdated:   {"Outdated", s.ActionFilterOutdated.KeySlug},
+		FilterLeaves:     {"Leaves", s.ActionFilterLeaves.KeySlug},
+		FilterCasks:      {"Casks", s.ActionFilterCasks.KeySlug},
+		FilterFormulae:   {"Formulae", s.ActionFilterFormulae.KeySlug},
+		FilterTrending:   {"Trending", s.ActionFilterTrending.KeySlug},
+		FilterOrphans:    {"Orphans", s.ActionFilterOrphans.KeySlug},
+		FilterPinned:     {"Pinned", s.ActionFilterPinned.KeySlug},
+		FilterServices:   {"Services", s.ActionFilterServices.KeySlug},
+		FilterUnlinked:   {"Unlinked", s.ActionFilterUnlinked.KeySlug},
+		FilterNoBottle:   {"No bottle", s.ActionFilterNoBottle.KeySlug},
+		FilterDeprecated: {"Deprecated", s.ActionFilterDeprecated.KeySlug},
 	}
 
 	baseLabel := "Search"
@@ -328,14 +848,189 @@ func (s *InputService) handleFilterFormulaeEvent() {
 	s.handleFilterEvent(FilterFormulae)
 }
 
-// handleSortEvent cycles through sort modes (Downloads → Name → Installed).
+// handleFilterTrendingEvent toggles the filter for packages climbing in popularity.
+// Trends are read from the analytics history each time the filter is turned on.
+func (s *InputService) handleFilterTrendingEvent() {
+	if s.appService.activeFilter != FilterTrending {
+		if err := s.appService.LoadTrends(); err != nil {
+			s.layout.GetNotifier().ShowError(fmt.Sprintf("Trending unavailable: %v", err))
+			return
+		}
+		s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Trending over the past %s (since %s)", s.appService.trendPeriod, s.appService.trendSince))
+	}
+	s.handleFilterEvent(FilterTrending)
+}
+
+// handleFilterOrphansEvent toggles the filter for formulae installed as dependencies
+// that nothing depends on anymore, reporting the space removing them would free.
+func (s *InputService) handleFilterOrphansEvent() {
+	if s.appService.activeFilter != FilterOrphans {
+		count, reclaimable := s.appService.LoadOrphans()
+		switch {
+		case count == 0:
+			s.layout.GetNotifier().ShowSuccess("No orphaned dependencies")
+		case reclaimable > 0:
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("%d orphaned dependenc%s · %s reclaimable (X to autoremove)",
+				count, pluralY(count), models.FormatSize(reclaimable)))
+		default:
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("%d orphaned dependenc%s (X to autoremove)", count, pluralY(count)))
+		}
+	}
+	s.handleFilterEvent(FilterOrphans)
+}
+
+// handleAutoremoveEvent previews what `brew autoremove` would uninstall and runs it once confirmed.
+func (s *InputService) handleAutoremoveEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	s.layout.GetNotifier().ShowWarning("Checking for unneeded dependencies...")
+	go func() {
+		names, err := s.brewService.AutoremovePreview()
+		s.appService.app.QueueUpdateDraw(func() {
+			switch {
+			case err != nil:
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Autoremove preview failed: %v", err))
+				return
+			case len(names) == 0:
+				s.layout.GetNotifier().ShowSuccess("No unneeded dependencies to remove")
+				return
+			}
+
+			text := fmt.Sprintf("brew autoremove will uninstall %d unneeded formul%s:\n\n%s",
+				len(names), pluralAE(len(names)), strings.Join(names, ", "))
+			if reclaimable := s.appService.installedSize(names); reclaimable > 0 {
+				text += fmt.Sprintf("\n\nThis frees %s.", models.FormatSize(reclaimable))
+			}
+			s.layout.GetNotifier().Clear()
+			s.showModal(text, s.runAutoremove, s.closeModal)
+		})
+	}()
+}
+
+// runAutoremove runs `brew autoremove` and refreshes the package list.
+func (s *InputService) runAutoremove() {
+	s.closeModal()
+	s.layout.GetOutput().Clear()
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning("Removing unneeded dependencies...")
+		})
+		err := s.brewService.Autoremove(s.outputWriter())
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError("Autoremove failed: see output for details")
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess("Removed unneeded dependencies")
+		})
+		if err == nil {
+			s.appService.forceRefreshResults()
+		}
+	}()
+}
+
+// handleFilterPinnedEvent toggles the filter for pinned formulae
+func (s *InputService) handleFilterPinnedEvent() {
+	s.handleFilterEvent(FilterPinned)
+}
+
+// handleFilterServicesEvent toggles the filter for formulae that define a background service
+func (s *InputService) handleFilterServicesEvent() {
+	s.handleFilterEvent(FilterServices)
+}
+
+// handleFilterUnlinkedEvent toggles the filter for installed formulae not linked into the prefix
+func (s *InputService) handleFilterUnlinkedEvent() {
+	s.handleFilterEvent(FilterUnlinked)
+}
+
+// handleFilterNoBottleEvent toggles the filter for formulae without a bottle for this platform
+func (s *InputService) handleFilterNoBottleEvent() {
+	s.handleFilterEvent(FilterNoBottle)
+}
+
+// handleFilterDeprecatedEvent toggles the filter for installed packages that are
+// deprecated or disabled, listed by the date they are disabled
+func (s *InputService) handleFilterDeprecatedEvent() {
+	s.handleFilterEvent(FilterDeprecated)
+}
+
+// handleTrendPeriodEvent switches the trending comparison between a week and a month.
+func (s *InputService) handleTrendPeriodEvent() {
+	period, err := s.appService.CycleTrendPeriod()
+	if err != nil {
+		s.layout.GetNotifier().ShowError(fmt.Sprintf("Trending unavailable: %v", err))
+		return
+	}
+	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Trend period: %s (since %s)", period, s.appService.trendSince))
+}
+
+// handleSortEvent cycles through sort modes (Downloads → Name → Size → Installed Date → ...).
 func (s *InputService) handleSortEvent() {
 	newSort := s.appService.CycleSortMode()
-	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Sort: %s", newSort))
+	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Sort: %s", describeSortKey(models.SortKey{Mode: newSort})))
+}
+
+// handleReverseSortEvent toggles the sort direction between ascending and descending.
+func (s *InputService) handleReverseSortEvent() {
+	key, ok := s.appService.ReverseSort()
+	if !ok {
+		s.layout.GetNotifier().ShowWarning("No active sort to reverse")
+		return
+	}
+	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Sort: %s", describeSortKey(key)))
+}
+
+// handleAnalyticsPeriodEvent cycles the analytics window (30d, 90d, 365d).
+func (s *InputService) handleAnalyticsPeriodEvent() {
+	period := s.appService.CycleAnalyticsPeriod()
+	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Analytics window: %s", period))
+}
+
+// handleAnalyticsMetricEvent cycles the analytics metric (installs on request, installs, build errors).
+func (s *InputService) handleAnalyticsMetricEvent() {
+	s.appService.CycleAnalyticsMetric()
+}
+
+// handleSizeColumnEvent shows or hides the installed size column.
+func (s *InputService) handleSizeColumnEvent() {
+	if s.appService.ToggleSizeColumn() {
+		s.layout.GetNotifier().ShowSuccess("Size column shown")
+	} else {
+		s.layout.GetNotifier().ShowSuccess("Size column hidden")
+	}
+}
+
+// handleLicenseColumnEvent shows or hides the license column.
+func (s *InputService) handleLicenseColumnEvent() {
+	if s.appService.ToggleLicenseColumn() {
+		s.layout.GetNotifier().ShowSuccess("License column shown")
+	} else {
+		s.layout.GetNotifier().ShowSuccess("License column hidden")
+	}
 }
 
-// handleExportEvent exports installed packages to ~/Brewfile.
+// handleExportEvent asks what to export the installed packages as: a Brewfile,
+// or a CycloneDX or SPDX software bill of materials.
 func (s *InputService) handleExportEvent() {
+	modal := s.layout.GetModal().BuildChoice(
+		"Export the installed packages as:\n\n"+
+			"Brewfile: ~/Brewfile, to reinstall them elsewhere\n"+
+			"CycloneDX or SPDX: a software bill of materials (SBOM) for audits, in your home directory",
+		[]string{"Brewfile", "CycloneDX", "SPDX", "Cancel"},
+		[]func(){
+			s.exportBrewfile,
+			func() { s.exportSBOM(SBOMCycloneDX) },
+			func() { s.exportSBOM(SBOMSPDX) },
+			s.closeModal,
+		})
+	s.setRoot(modal)
+}
+
+// exportBrewfile exports installed packages to ~/Brewfile.
+func (s *InputService) exportBrewfile() {
+	s.closeModal()
 	path, err := s.appService.ExportBrewfile()
 	if err != nil {
 		s.layout.GetNotifier().ShowError(fmt.Sprintf("Export failed: %v", err))
@@ -344,6 +1039,23 @@ func (s *InputService) handleExportEvent() {
 	s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Exported to %s", path))
 }
 
+// exportSBOM writes an SBOM of the installed packages in the background, since
+// listing Flatpak and App Store apps runs their command-line tools.
+func (s *InputService) exportSBOM(format SBOMFormat) {
+	s.closeModal()
+	s.layout.GetNotifier().ShowWarning("Exporting SBOM...")
+	go func() {
+		path, err := s.appService.ExportSBOM(format)
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("SBOM export failed: %v", err))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("SBOM exported to %s", path))
+		})
+	}()
+}
+
 // handleVulnScanEvent scans the selected package for known vulnerabilities using brew vulns.
 func (s *InputService) handleVulnScanEvent() {
 	if !s.appService.vulnsService.IsAvailable() {
@@ -391,6 +1103,9 @@ func (s *InputService) handleVulnScanEvent() {
 
 // handleVulnInstallPrompt asks the user to install brew vulns when it's not available.
 func (s *InputService) handleVulnInstallPrompt() {
+	if s.jobsRunning() {
+		return
+	}
 	s.showModal(
 		"brew vulns is not installed.\n\nInstall it now to enable vulnerability scanning?",
 		func() {
@@ -421,98 +1136,308 @@ func (s *InputService) handleVulnInstallPrompt() {
 // This is used for actions like installing, removing, or updating packages, invoking user confirmation.
 func (s *InputService) showModal(text string, confirmFunc func(), cancelFunc func()) {
 	modal := s.layout.GetModal().Build(text, confirmFunc, cancelFunc)
-	s.appService.app.SetRoot(modal, true)
+	s.setRoot(modal)
 }
 
 // closeModal closes the currently displayed modal dialog and returns focus to the main table view.
 func (s *InputService) closeModal() {
-	s.appService.app.SetRoot(s.layout.Root(), true)
+	s.setRoot(s.layout.Root())
 	s.appService.app.SetFocus(s.layout.GetTable().View())
 }
 
 // handleInstallPackageEvent is called when the user presses the installation key (i).
+// Homebrew packages are installed with the options remembered from the install dialog.
+// When rows are marked, their installs are queued instead.
 func (s *InputService) handleInstallPackageEvent() {
+	if s.enqueueMarked(models.JobInstall) {
+		return
+	}
 	row, _ := s.layout.GetTable().View().GetSelection()
 	if row > 0 && row-1 < len(*s.appService.filteredPackages) {
 		info := (*s.appService.filteredPackages)[row-1]
-		s.showModal(
-			fmt.Sprintf("Are you sure you want to install the package: %s?", info.Label()),
-			func() {
-				s.closeModal()
-				s.layout.GetOutput().Clear()
-				go func() {
-					s.appService.app.QueueUpdateDraw(func() {
-						s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Installing %s...", info.Label()))
-					})
-					var err error
-					switch info.Type {
-					case models.PackageTypeFlatpak:
-						err = s.flatpakService.InstallPackage(info, s.outputWriter())
-					case models.PackageTypeMas:
-						err = s.appService.masService.InstallApp(info, s.outputWriter())
-					default:
-						err = s.brewService.InstallPackage(info, s.outputWriter())
-					}
+		opts := s.appService.InstallOptions(info)
+		if conflicts := s.appService.installedConflicts(info); len(conflicts) > 0 {
+			s.confirmConflictingInstall(info, opts, conflicts)
+			return
+		}
+		if size, ok := cachedBottleSize(info.Name); ok {
+			info.Bottle.Size = size
+		}
+		confirmText := func(pkg models.Package) string {
+			text := fmt.Sprintf("Are you sure you want to install the package: %s?", pkg.Label())
+			if flags := opts.Flags(pkg.Type); len(flags) > 0 {
+				text += fmt.Sprintf("\n\nWith %s (press I to change)", strings.Join(flags, " "))
+			}
+			if note := bottleNote(pkg, opts); note != "" {
+				text += "\n\n" + note
+			}
+			return text
+		}
+		text := confirmText(info)
+		s.showModal(text, func() {
+			s.closeModal()
+			s.installPackage(info, opts, nil)
+		}, s.closeModal)
 
-					s.appService.app.QueueUpdateDraw(func() {
-						if err != nil {
-							s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to install %s", info.Label()))
-							return
-						}
-						s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Installed %s", info.Label()))
-					})
-					if err == nil {
-						s.appService.forceRefreshResults()
-					}
-				}()
-			}, s.closeModal)
+		// Add the download size to the confirmation once the bottle registry answers
+		if info.Bottle.Available() && info.Bottle.Size == 0 {
+			go func() {
+				size, err := s.appService.BottleSize(info)
+				if err != nil {
+					return
+				}
+				withSize := info
+				withSize.Bottle.Size = size
+				s.appService.app.QueueUpdateDraw(func() {
+					s.layout.GetModal().ReplaceText(text, confirmText(withSize))
+				})
+			}()
+		}
+	}
+}
+
+// confirmConflictingInstall lists the installed packages that conflict with a package,
+// with the reasons given, and offers to unlink or remove them before installing it.
+func (s *InputService) confirmConflictingInstall(info models.Package, opts models.InstallOptions, conflicts []packageConflict) {
+	installAfter := func(remove bool) func() {
+		return func() {
+			s.closeModal()
+			s.installPackage(info, opts, func() error {
+				return resolveConflicts(s.brewService, conflicts, remove, s.outputWriter())
+			})
+		}
+	}
+
+	var labels []string
+	var handlers []func()
+	if canUnlinkConflicts(conflicts) {
+		labels = append(labels, "Unlink")
+		handlers = append(handlers, installAfter(false))
+	}
+	labels = append(labels, "Remove", "Cancel")
+	handlers = append(handlers, installAfter(true), s.closeModal)
+
+	modal := s.layout.GetModal().BuildChoice(
+		fmt.Sprintf("%s conflicts with %d installed package%s:\n\n%s\n\nUnlink or remove the conflicting package%s before installing?",
+			info.Label(), len(conflicts), pluralS(len(conflicts)), describeConflicts(conflicts), pluralS(len(conflicts))),
+		labels, handlers)
+	s.setRoot(modal)
+}
+
+// handleInstallOptionsEvent is called when the user presses the install with options key (I).
+// It offers the other versions of a formula, --HEAD and --build-from-source, or cask flags,
+// and remembers the choice for the package that gets installed.
+func (s *InputService) handleInstallOptionsEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row <= 0 || row-1 >= len(*s.appService.filteredPackages) {
+		return
+	}
+	info := (*s.appService.filteredPackages)[row-1]
+	if info.Type != models.PackageTypeFormula && info.Type != models.PackageTypeCask {
+		s.layout.GetNotifier().ShowWarning("Install options are only available for Homebrew packages")
+		return
 	}
+
+	dialog := s.layout.GetInstallOptionsDialog()
+	hasHead := func(version string) bool {
+		if version == info.Name {
+			return info.Formula != nil && info.Formula.Urls.Head.URL != ""
+		}
+		return s.appService.headAvailable(version)
+	}
+	dialogPages := dialog.Build(s.layout.Root(), info, versionChoices(info), hasHead, s.appService.InstallOptions(info),
+		func(version string, opts models.InstallOptions) {
+			s.closeModal()
+			target := info
+			if version != info.Name {
+				pkg, ok := s.appService.findPackage(models.PackageTypeFormula, version)
+				if !ok {
+					s.layout.GetNotifier().ShowError(fmt.Sprintf("%s was not found in the catalogue", version))
+					return
+				}
+				target = pkg
+			}
+			if err := s.appService.SetInstallOptions(target, opts); err != nil {
+				s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Could not remember the options of %s: %v", target.Name, err))
+			}
+			if conflicts := s.appService.installedConflicts(target); len(conflicts) > 0 {
+				s.confirmConflictingInstall(target, opts, conflicts)
+				return
+			}
+			s.installPackage(target, opts, nil)
+		}, s.closeModal)
+
+	s.setRoot(dialogPages)
+	s.appService.GetApp().SetFocus(dialog.View())
+}
+
+// installPackage installs a package, streaming the output, then shows its caveats.
+// before, if set, runs first (e.g. to resolve conflicts) and stops the install on error.
+func (s *InputService) installPackage(info models.Package, opts models.InstallOptions, before func() error) {
+	s.layout.GetOutput().Clear()
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Installing %s...", info.Label()))
+		})
+		var err error
+		if before != nil {
+			err = before()
+		}
+		if err == nil {
+			switch info.Type {
+			case models.PackageTypeFlatpak:
+				err = s.flatpakService.InstallPackage(info, s.outputWriter())
+			case models.PackageTypeMas:
+				err = s.appService.masService.InstallApp(info, s.outputWriter())
+			default:
+				if err = s.brewService.InstallPackage(info, opts, s.outputWriter()); err == nil {
+					err = s.restartBrewfileService(info, s.outputWriter())
+				}
+			}
+		}
+
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to install %s", info.Label()))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Installed %s", info.Label()))
+		})
+		if err == nil {
+			s.appService.forceRefreshResults()
+			s.showCaveatsAfter(fmt.Sprintf("Installed %s", info.Label()), []models.Package{info})
+		}
+	}()
 }
 
 // handleRemovePackageEvent is called when the user presses the removal key (r).
+// When installed packages depend on a Homebrew package, they are listed and the
+// user can remove them too or force the removal, instead of brew refusing it.
+// When rows are marked, their removals are queued instead.
 func (s *InputService) handleRemovePackageEvent() {
+	if s.enqueueMarked(models.JobRemove) {
+		return
+	}
 	row, _ := s.layout.GetTable().View().GetSelection()
-	if row > 0 && row-1 < len(*s.appService.filteredPackages) {
-		info := (*s.appService.filteredPackages)[row-1]
+	if row <= 0 || row-1 >= len(*s.appService.filteredPackages) {
+		return
+	}
+	info := (*s.appService.filteredPackages)[row-1]
+
+	var dependents []models.Package
+	if info.Type == models.PackageTypeFormula || info.Type == models.PackageTypeCask {
+		dependents = s.appService.installedDependents(info)
+	}
+	if len(dependents) == 0 {
 		s.showModal(
 			fmt.Sprintf("Are you sure you want to remove the package: %s?", info.Label()),
-			func() {
-				s.closeModal()
-				s.layout.GetOutput().Clear()
-				go func() {
-					s.appService.app.QueueUpdateDraw(func() {
-						s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Removing %s...", info.Label()))
-					})
-					var err error
-					switch info.Type {
-					case models.PackageTypeFlatpak:
-						err = s.flatpakService.RemovePackage(info, s.outputWriter())
-					case models.PackageTypeMas:
-						err = s.appService.masService.RemoveApp(info, s.outputWriter())
-					default:
-						err = s.brewService.RemovePackage(info, s.outputWriter())
-					}
+			func() { s.removePackages([]models.Package{info}, false) },
+			s.closeModal)
+		return
+	}
 
-					s.appService.app.QueueUpdateDraw(func() {
-						if err != nil {
-							s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to remove %s: may need sudo in terminal", info.Label()))
-							return
-						}
-						s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Removed %s", info.Label()))
-					})
-					if err == nil {
-						s.appService.forceRefreshResults()
-					}
-				}()
-			}, s.closeModal)
+	modal := s.layout.GetModal().BuildChoice(
+		fmt.Sprintf("%s is required by %d installed package%s:\n\n%s\n\nRemoving it alone may break them.",
+			info.Label(), len(dependents), pluralS(len(dependents)), packageNames(dependents, 8)),
+		[]string{"Remove All", "Force", "Cancel"},
+		[]func(){
+			func() { s.removePackages(append(dependents, info), false) },
+			func() { s.removePackages([]models.Package{info}, true) },
+			s.closeModal,
+		})
+	s.setRoot(modal)
+}
+
+// removePackages uninstalls packages, the selected one last. After a Homebrew removal
+// it offers to remove the dependencies that are no longer needed by anything.
+func (s *InputService) removePackages(targets []models.Package, ignoreDependencies bool) {
+	s.closeModal()
+	s.layout.GetOutput().Clear()
+
+	info := targets[len(targets)-1]
+	label := info.Label()
+	if len(targets) > 1 {
+		label = fmt.Sprintf("%s and %d other package%s", label, len(targets)-1, pluralS(len(targets)-1))
 	}
+	isBrew := info.Type == models.PackageTypeFormula || info.Type == models.PackageTypeCask
+	var orphansBefore []models.Package
+	if isBrew {
+		orphansBefore = s.appService.orphanedFormulae()
+	}
+
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Removing %s...", label))
+		})
+		var err error
+		switch info.Type {
+		case models.PackageTypeFlatpak:
+			err = s.flatpakService.RemovePackage(info, s.outputWriter())
+		case models.PackageTypeMas:
+			err = s.appService.masService.RemoveApp(info, s.outputWriter())
+		default:
+			err = s.brewService.RemovePackages(targets, ignoreDependencies, s.outputWriter())
+		}
+
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to remove %s: see output for details", label))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Removed %s", label))
+		})
+		if err != nil {
+			return
+		}
+		s.appService.forceRefreshResults()
+		if isBrew {
+			s.offerOrphanRemoval(orphansBefore, label)
+		}
+	}()
+}
+
+// offerOrphanRemoval asks to remove the formulae that became orphans after a removal,
+// leaving alone those that were already orphaned before it.
+func (s *InputService) offerOrphanRemoval(before []models.Package, removed string) {
+	wasOrphan := make(map[string]bool, len(before))
+	for _, pkg := range before {
+		wasOrphan[pkg.Name] = true
+	}
+	var orphans []models.Package
+	for _, pkg := range s.appService.orphanedFormulae() {
+		if !wasOrphan[pkg.Name] {
+			orphans = append(orphans, pkg)
+		}
+	}
+	if len(orphans) == 0 {
+		return
+	}
+
+	s.appService.app.QueueUpdateDraw(func() {
+		s.showModal(
+			fmt.Sprintf("Removing %s left %d dependenc%s unused:\n\n%s\n\nRemove the unused dependencies too?",
+				removed, len(orphans), pluralY(len(orphans)), packageNames(orphans, 8)),
+			func() { s.removePackages(orphans, false) },
+			s.closeModal)
+	})
 }
 
 // handleUpdatePackageEvent is called when the user presses the update key (u).
+// When rows are marked, their upgrades are queued instead.
 func (s *InputService) handleUpdatePackageEvent() {
+	if s.enqueueMarked(models.JobUpgrade) {
+		return
+	}
 	row, _ := s.layout.GetTable().View().GetSelection()
 	if row > 0 && row-1 < len(*s.appService.filteredPackages) {
 		info := (*s.appService.filteredPackages)[row-1]
+		if info.Pinned {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s is pinned: unpin it (p) to update", info.Name))
+			return
+		}
 		s.showModal(
 			fmt.Sprintf("Are you sure you want to update the package: %s?", info.Label()),
 			func() {
@@ -542,17 +1467,286 @@ func (s *InputService) handleUpdatePackageEvent() {
 					})
 					if err == nil {
 						s.appService.forceRefreshResults()
+						s.showCaveatsAfter(fmt.Sprintf("Updated %s", info.Label()), []models.Package{info})
 					}
 				}()
 			}, s.closeModal)
 	}
 }
 
+// handlePinPackageEvent pins the selected formula, or unpins it when already pinned.
+func (s *InputService) handlePinPackageEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	info, ok := s.selectedInstalledFormula("pinned")
+	if !ok {
+		return
+	}
+
+	action, verb, done := "pin", "Pinning", "Pinned"
+	if info.Pinned {
+		action, verb, done = "unpin", "Unpinning", "Unpinned"
+	}
+	s.layout.GetOutput().Clear()
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s %s...", verb, info.Name))
+		})
+		var err error
+		if info.Pinned {
+			err = s.brewService.UnpinPackage(info, s.outputWriter())
+		} else {
+			err = s.brewService.PinPackage(info, s.outputWriter())
+		}
+
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to %s %s", action, info.Name))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("%s %s", done, info.Name))
+		})
+		if err == nil {
+			s.appService.forceRefreshResults()
+		}
+	}()
+}
+
+// selectedInstalledFormula returns the selected package if it is an installed formula,
+// warning otherwise.
+func (s *InputService) selectedInstalledFormula(action string) (models.Package, bool) {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row <= 0 || row-1 >= len(*s.appService.filteredPackages) {
+		return models.Package{}, false
+	}
+	info := (*s.appService.filteredPackages)[row-1]
+	if info.Type != models.PackageTypeFormula || !info.LocallyInstalled || info.Formula == nil {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Only installed formulae can be %s", action))
+		return models.Package{}, false
+	}
+	return info, true
+}
+
+// handleLinkPackageEvent is called when the user presses the link key (K).
+// Keg-only formulae are only linked with --force, after explaining why they are keg-only.
+func (s *InputService) handleLinkPackageEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	info, ok := s.selectedInstalledFormula("linked")
+	if !ok {
+		return
+	}
+	if info.Formula.Linked() {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s is already linked (%s)", info.Name, info.Formula.LinkedKeg))
+		return
+	}
+
+	if !info.Formula.KegOnly {
+		s.showModal(
+			fmt.Sprintf("Link %s into the Homebrew prefix?", info.Name),
+			func() {
+				s.closeModal()
+				s.runLinkAction(info, "link", false)
+			}, s.closeModal)
+		return
+	}
+
+	text := fmt.Sprintf("%s is keg-only: %s.\n\nLinking it with --force puts it on your PATH and may shadow other versions. Link anyway?",
+		info.Name, info.Formula.KegOnlyReason)
+	s.showModal(text, func() {
+		s.closeModal()
+		s.runLinkAction(info, "link", true)
+	}, s.closeModal)
+}
+
+// handleUnlinkPackageEvent is called when the user presses the unlink key (U).
+func (s *InputService) handleUnlinkPackageEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	info, ok := s.selectedInstalledFormula("unlinked")
+	if !ok {
+		return
+	}
+	if !info.Formula.Linked() {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s is not linked", info.Name))
+		return
+	}
+	s.showModal(
+		fmt.Sprintf("Unlink %s? It stays installed but leaves your PATH.", info.Name),
+		func() {
+			s.closeModal()
+			s.runLinkAction(info, "unlink", false)
+		}, s.closeModal)
+}
+
+// runLinkAction links or unlinks a formula, streaming the brew output.
+func (s *InputService) runLinkAction(info models.Package, action string, force bool) {
+	s.layout.GetOutput().Clear()
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Running brew %s %s...", action, info.Name))
+		})
+		var err error
+		if action == "unlink" {
+			err = s.brewService.UnlinkPackage(info, s.outputWriter())
+		} else {
+			err = s.brewService.LinkPackage(info, force, s.outputWriter())
+		}
+
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to %s %s: see output for details", action, info.Name))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("brew %s %s: done", action, info.Name))
+		})
+		if err == nil {
+			s.appService.forceRefreshResults()
+		}
+	}()
+}
+
+// handleSwitchVersionEvent is called when the user presses the switch version key (V).
+// It moves from the selected formula to another of its versions, e.g. postgresql@15 to @16.
+func (s *InputService) handleSwitchVersionEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	from, ok := s.selectedInstalledFormula("switched")
+	if !ok {
+		return
+	}
+	var targets []models.Package
+	for _, name := range versionChoices(from)[1:] {
+		if pkg, ok := s.appService.findPackage(models.PackageTypeFormula, name); ok {
+			targets = append(targets, pkg)
+		}
+	}
+	if len(targets) == 0 {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s has no other versions", from.Name))
+		return
+	}
+
+	dialog := s.layout.GetVersionSwitchDialog()
+	dialogPages := dialog.Build(s.layout.Root(), from, targets, func(sw models.VersionSwitch) {
+		s.closeModal()
+		s.switchVersion(sw)
+	}, s.closeModal)
+
+	s.setRoot(dialogPages)
+	s.appService.GetApp().SetFocus(dialog.View())
+}
+
+// switchVersion runs the brew commands of a version switch, streaming their output.
+func (s *InputService) switchVersion(sw models.VersionSwitch) {
+	steps := planVersionSwitch(s.brewService, sw, s.appService.InstallOptions(sw.To))
+	s.layout.GetOutput().Clear()
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Switching from %s to %s...", sw.From.Name, sw.To.Name))
+		})
+		err := runBrewSteps(steps, s.outputWriter())
+
+		s.appService.app.QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to switch to %s: %v", sw.To.Name, err))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Switched from %s to %s", sw.From.Name, sw.To.Name))
+		})
+		// Refresh even on failure: the steps that ran may have changed links
+		s.appService.forceRefreshResults()
+		if err == nil && !sw.To.LocallyInstalled {
+			s.showCaveatsAfter(fmt.Sprintf("Installed %s", sw.To.Name), []models.Package{sw.To})
+		}
+	}()
+}
+
+// handleMigrateEvent is called when the user presses the migrate key (m) on an installed,
+// deprecated or disabled package. It installs the replacement Homebrew names, removes
+// the old package and updates its entry in the loaded Brewfile.
+func (s *InputService) handleMigrateEvent() {
+	if s.jobsRunning() {
+		return
+	}
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row <= 0 || row-1 >= len(*s.appService.filteredPackages) {
+		return
+	}
+	from := (*s.appService.filteredPackages)[row-1]
+	if !from.LocallyInstalled {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("%s is not installed", from.Label()))
+		return
+	}
+	to, err := s.appService.migrationTarget(from)
+	if err != nil {
+		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Cannot migrate: %v", err))
+		return
+	}
+
+	steps := planMigration(s.brewService, from, to, s.appService.InstallOptions(to))
+	text := fmt.Sprintf("Migrate from %s to its replacement %s?\n\n", from.Name, to.Name)
+	for _, step := range steps {
+		text += step.description + "\n"
+	}
+	if s.appService.IsBrewfileMode() {
+		text += "\nThe Brewfile entry is updated too."
+	}
+	s.showModal(text, func() {
+		s.closeModal()
+		s.migrate(from, to, steps)
+	}, s.closeModal)
+}
+
+// migrate runs the steps of a migration, then updates the Brewfile entry.
+func (s *InputService) migrate(from, to models.Package, steps []brewStep) {
+	s.layout.GetOutput().Clear()
+	go func() {
+		s.appService.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Migrating from %s to %s...", from.Name, to.Name))
+		})
+		err := runBrewSteps(steps, s.outputWriter())
+		updated := false
+		if err == nil {
+			updated, err = s.appService.updateBrewfileEntry(from, to)
+		}
+
+		s.appService.app.QueueUpdateDraw(func() {
+			switch {
+			case err != nil:
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to migrate to %s: %v", to.Name, err))
+			case updated:
+				s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Migrated from %s to %s, Brewfile updated", from.Name, to.Name))
+			default:
+				s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Migrated from %s to %s", from.Name, to.Name))
+			}
+		})
+		// Refresh even on failure: the replacement may be installed already
+		s.appService.forceRefreshResults()
+		if err == nil && !to.LocallyInstalled {
+			s.showCaveatsAfter(fmt.Sprintf("Installed %s", to.Name), []models.Package{to})
+		}
+	}()
+}
+
 // handleUpdateAllPackagesEvent is called when the user presses the update all key (Ctrl+U).
+// Pinned formulae are listed, since brew upgrade skips them.
 func (s *InputService) handleUpdateAllPackagesEvent() {
-	s.showModal("Are you sure you want to update all Packages?", func() {
+	if s.jobsRunning() {
+		return
+	}
+	text := "Are you sure you want to update all Packages?"
+	pinned := s.appService.installedPackages(func(pkg *models.Package) bool { return pkg.Outdated && pkg.Pinned })
+	if len(pinned) > 0 {
+		text += fmt.Sprintf("\n\n%d pinned package%s will be skipped:\n%s", len(pinned), pluralS(len(pinned)), packageNames(pinned, 8))
+	}
+	s.showModal(text, func() {
 		s.closeModal()
 		s.layout.GetOutput().Clear()
+		outdated := s.appService.installedPackages(func(pkg *models.Package) bool { return pkg.Outdated && !pkg.Pinned })
 		go func() {
 			s.appService.app.QueueUpdateDraw(func() {
 				s.layout.GetNotifier().ShowWarning("Updating all Homebrew packages...")
@@ -580,6 +1774,7 @@ func (s *InputService) handleUpdateAllPackagesEvent() {
 				s.layout.GetNotifier().ShowSuccess("Updated all Packages")
 			})
 			s.appService.forceRefreshResults()
+			s.showCaveatsAfter("Updated packages", outdated)
 		}()
 	}, s.closeModal)
 }
@@ -590,7 +1785,22 @@ type batchOperation struct {
 	actionTag     string // "INSTALL" or "REMOVE"
 	skipCondition func(pkg models.Package) bool
 	skipReason    string
+	skipOther     func(pkg models.Package) string // Optional: reason to skip a package for another cause
 	execute       func(pkg models.Package) error
+	confirmed     bool // Already confirmed by the user (e.g. in the conflicts dialog): run without asking again
+}
+
+// skip reports whether a package is skipped, and why.
+func (op batchOperation) skip(pkg models.Package) (string, bool) {
+	if op.skipCondition(pkg) {
+		return op.skipReason, true
+	}
+	if op.skipOther != nil {
+		if reason := op.skipOther(pkg); reason != "" {
+			return reason, true
+		}
+	}
+	return "", false
 }
 
 // handleBatchPackageOperation processes multiple packages with progress notifications.
@@ -598,6 +1808,9 @@ func (s *InputService) handleBatchPackageOperation(op batchOperation) {
 	if !s.appService.IsBrewfileMode() {
 		return
 	}
+	if op.confirmed {
+		s.closeModal()
+	}
 
 	packages := *s.appService.GetBrewfilePackages()
 	if len(packages) == 0 {
@@ -608,7 +1821,7 @@ func (s *InputService) handleBatchPackageOperation(op batchOperation) {
 	// Count relevant packages
 	actionable := 0
 	for _, pkg := range packages {
-		if !op.skipCondition(pkg) {
+		if _, skip := op.skip(pkg); !skip {
 			actionable++
 		}
 	}
@@ -621,7 +1834,7 @@ func (s *InputService) handleBatchPackageOperation(op batchOperation) {
 	message := fmt.Sprintf("%s all packages from Brewfile?\n\nTotal: %d packages\nTo process: %d",
 		op.actionVerb, len(packages), actionable)
 
-	s.showModal(message, func() {
+	run := func() {
 		s.closeModal()
 		s.layout.GetOutput().Clear()
 		go func() {
@@ -632,10 +1845,10 @@ func (s *InputService) handleBatchPackageOperation(op batchOperation) {
 				current++
 				pkgName := pkg.Name // Capture for closures
 
-				if op.skipCondition(pkg) {
+				if reason, skip := op.skip(pkg); skip {
 					s.appService.app.QueueUpdateDraw(func() {
-						s.layout.GetNotifier().ShowWarning(fmt.Sprintf("[%d/%d] Skipping %s (%s)", current, total, pkgName, op.skipReason))
-						fmt.Fprintf(s.layout.GetOutput().View(), "[SKIP] %s (%s)\n", pkgName, op.skipReason)
+						s.layout.GetNotifier().ShowWarning(fmt.Sprintf("[%d/%d] Skipping %s (%s)", current, total, pkgName, reason))
+						fmt.Fprintf(s.layout.GetOutput().View(), "[SKIP] %s (%s)\n", pkgName, reason)
 					})
 					continue
 				}
@@ -663,31 +1876,98 @@ func (s *InputService) handleBatchPackageOperation(op batchOperation) {
 			})
 			s.appService.forceRefreshResults()
 		}()
-	}, s.closeModal)
+	}
+	if op.confirmed {
+		run()
+		return
+	}
+	s.showModal(message, run, s.closeModal)
 }
 
 // handleInstallAllPackagesEvent is called when the user presses the install all key (Ctrl+A).
+// Packages conflicting with installed ones are listed first: the conflicting packages
+// can be unlinked or removed before each install, or the Brewfile packages skipped.
 func (s *InputService) handleInstallAllPackagesEvent() {
-	s.handleBatchPackageOperation(batchOperation{
-		actionVerb:    "Installing",
-		actionTag:     "INSTALL",
-		skipCondition: func(pkg models.Package) bool { return pkg.LocallyInstalled },
-		skipReason:    "already installed",
-		execute: func(pkg models.Package) error {
-			switch pkg.Type {
-			case models.PackageTypeFlatpak:
-				return s.flatpakService.InstallPackage(pkg, s.outputWriter())
-			case models.PackageTypeMas:
-				return s.appService.masService.InstallApp(pkg, s.outputWriter())
-			default:
-				return s.brewService.InstallPackage(pkg, s.outputWriter())
+	if s.jobsRunning() {
+		return
+	}
+	conflicts := make(map[string][]packageConflict)
+	var lines []string
+	if s.appService.IsBrewfileMode() {
+		for _, pkg := range *s.appService.GetBrewfilePackages() {
+			if pkg.LocallyInstalled {
+				continue
 			}
-		},
-	})
+			if found := s.appService.installedConflicts(pkg); len(found) > 0 {
+				conflicts[packageKey(pkg.Type, pkg.Name)] = found
+				lines = append(lines, fmt.Sprintf("%s conflicts with:\n%s", pkg.Label(), describeConflicts(found)))
+			}
+		}
+	}
+
+	install := func(resolution conflictResolution, confirmed bool) {
+		resolver := newConflictResolver(s.brewService, resolution == conflictsRemove)
+		s.handleBatchPackageOperation(batchOperation{
+			actionVerb:    "Installing",
+			actionTag:     "INSTALL",
+			skipCondition: func(pkg models.Package) bool { return pkg.LocallyInstalled },
+			skipReason:    "already installed",
+			skipOther: func(pkg models.Package) string {
+				if found := conflicts[packageKey(pkg.Type, pkg.Name)]; resolution == conflictsSkip && len(found) > 0 {
+					return "conflicts with " + found[0].Package.Label()
+				}
+				return ""
+			},
+			execute: func(pkg models.Package) error {
+				switch pkg.Type {
+				case models.PackageTypeFlatpak:
+					return s.flatpakService.InstallPackage(pkg, s.outputWriter())
+				case models.PackageTypeMas:
+					return s.appService.masService.InstallApp(pkg, s.outputWriter())
+				default:
+					if err := resolver.resolve(conflicts[packageKey(pkg.Type, pkg.Name)], s.outputWriter()); err != nil {
+						return err
+					}
+					if err := s.brewService.InstallPackage(pkg, s.appService.InstallOptions(pkg), s.outputWriter()); err != nil {
+						return err
+					}
+					return s.restartBrewfileService(pkg, s.outputWriter())
+				}
+			},
+			confirmed: confirmed,
+		})
+	}
+	if len(conflicts) == 0 {
+		install(conflictsUnlink, false) // Nothing to resolve
+		return
+	}
+
+	var all []packageConflict
+	for _, found := range conflicts {
+		all = append(all, found...)
+	}
+	// The choice confirms the install: no second confirmation is asked
+	var labels []string
+	var handlers []func()
+	if canUnlinkConflicts(all) {
+		labels = append(labels, "Unlink")
+		handlers = append(handlers, func() { install(conflictsUnlink, true) })
+	}
+	labels = append(labels, "Remove", "Skip", "Cancel")
+	handlers = append(handlers, func() { install(conflictsRemove, true) }, func() { install(conflictsSkip, true) }, s.closeModal)
+
+	modal := s.layout.GetModal().BuildChoice(
+		fmt.Sprintf("Install all packages from Brewfile?\n\nInstalled packages conflict with %d of them:\n\n%s\n\nUnlink or remove the conflicting packages before installing, or skip these Brewfile packages?",
+			len(conflicts), strings.Join(lines, "\n\n")),
+		labels, handlers)
+	s.setRoot(modal)
 }
 
 // handleRemoveAllPackagesEvent is called when the user presses the remove all key (Ctrl+R).
 func (s *InputService) handleRemoveAllPackagesEvent() {
+	if s.jobsRunning() {
+		return
+	}
 	s.handleBatchPackageOperation(batchOperation{
 		actionVerb:    "Removing",
 		actionTag:     "REMOVE",
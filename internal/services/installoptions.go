@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/adrg/xdg"
+
+	"bbrew/internal/models"
+)
+
+// Install options chosen in the install dialog are remembered per package, reused by
+// later installs and written as args: into exported Brewfiles. They are user choices,
+// so they are kept in the XDG state directory rather than the cache, which may be
+// wiped at any time.
+const fileInstallOptions = "install-options.json"
+
+// installOptionsPath returns the file the remembered install options are saved to.
+func installOptionsPath() string {
+	return filepath.Join(xdg.StateHome, "bbrew", fileInstallOptions)
+}
+
+// installOptionsStore holds the remembered install options, keyed by packageKey.
+// It is read from the state directory on first use.
+type installOptionsStore struct {
+	once    sync.Once
+	mu      sync.RWMutex
+	options map[string]models.InstallOptions
+}
+
+// load reads the remembered options, unless they were already set. Options saved
+// in the cache directory by earlier versions are picked up when there are none yet.
+func (o *installOptionsStore) load() {
+	o.once.Do(func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if o.options != nil {
+			return
+		}
+		o.options = make(map[string]models.InstallOptions)
+		// #nosec G304 -- the path is built from the XDG state directory
+		data, err := os.ReadFile(installOptionsPath())
+		if err != nil {
+			data = readStaleCacheFile(fileInstallOptions, 2)
+		}
+		if data != nil {
+			_ = json.Unmarshal(data, &o.options)
+		}
+	})
+}
+
+// get returns the options remembered for a package, zero if there are none.
+func (o *installOptionsStore) get(pkgType models.PackageType, name string) models.InstallOptions {
+	o.load()
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.options[packageKey(pkgType, name)]
+}
+
+// set remembers the options of a package (forgetting them when zero) and saves them.
+func (o *installOptionsStore) set(pkgType models.PackageType, name string, opts models.InstallOptions) error {
+	o.load()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if opts.IsZero() {
+		delete(o.options, packageKey(pkgType, name))
+	} else {
+		o.options[packageKey(pkgType, name)] = opts
+	}
+
+	data, err := json.Marshal(o.options)
+	if err != nil {
+		return err
+	}
+	path := installOptionsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// versionChoices lists the formulae that can be installed in place of a formula:
+// itself first, then its other versions (e.g. node, node@22, node@20).
+func versionChoices(pkg models.Package) []string {
+	choices := []string{pkg.Name}
+	if pkg.Type != models.PackageTypeFormula || pkg.Formula == nil {
+		return choices
+	}
+	for _, name := range pkg.Formula.VersionedFormulae {
+		if !slices.Contains(choices, name) {
+			choices = append(choices, name)
+		}
+	}
+	return choices
+}
+
+// InstallOptions returns the install options remembered for a package.
+func (s *AppService) InstallOptions(pkg models.Package) models.InstallOptions {
+	return s.installOptions.get(pkg.Type, pkg.Name)
+}
+
+// SetInstallOptions remembers the install options of a package.
+func (s *AppService) SetInstallOptions(pkg models.Package, opts models.InstallOptions) error {
+	return s.installOptions.set(pkg.Type, pkg.Name, opts)
+}
+
+// headAvailable reports whether a formula of the catalogue can be installed with --HEAD.
+func (s *AppService) headAvailable(name string) bool {
+	pkg, ok := s.findPackage(models.PackageTypeFormula, name)
+	return ok && pkg.Formula != nil && pkg.Formula.Urls.Head.URL != ""
+}
+
+// findPackage returns the catalogue entry of a package.
+func (s *AppService) findPackage(pkgType models.PackageType, name string) (models.Package, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, pkg := range *s.packages {
+		if pkg.Type == pkgType && pkg.Name == name {
+			return pkg, true
+		}
+	}
+	return models.Package{}, false
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/adrg/xdg"
+
+	"bbrew/internal/models"
+)
+
+func TestVersionChoices(t *testing.T) {
+	node := models.Package{
+		Name:    "node",
+		Type:    models.PackageTypeFormula,
+		Formula: &models.Formula{Name: "node", VersionedFormulae: []string{"node@22", "node@20", "node"}},
+	}
+	if got, want := versionChoices(node), []string{"node", "node@22", "node@20"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("versionChoices(node) = %v, want %v", got, want)
+	}
+
+	firefox := models.Package{Name: "firefox", Type: models.PackageTypeCask, Cask: &models.Cask{Token: "firefox"}}
+	if got := versionChoices(firefox); !reflect.DeepEqual(got, []string{"firefox"}) {
+		t.Errorf("versionChoices(firefox) = %v, want only the cask itself", got)
+	}
+}
+
+func TestInstallOptionsStore_SavedInStateDir(t *testing.T) {
+	previousState, previousCache := xdg.StateHome, xdg.CacheHome
+	xdg.StateHome, xdg.CacheHome = t.TempDir(), t.TempDir()
+	t.Cleanup(func() { xdg.StateHome, xdg.CacheHome = previousState, previousCache })
+
+	var store installOptionsStore
+	if err := store.set(models.PackageTypeFormula, "neovim", models.InstallOptions{HEAD: true}); err != nil {
+		t.Fatalf("set() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(xdg.StateHome, "bbrew", fileInstallOptions)); err != nil {
+		t.Fatalf("options should be saved in the state directory: %v", err)
+	}
+
+	// Wiping the cache keeps them
+	_ = os.RemoveAll(xdg.CacheHome)
+	var reloaded installOptionsStore
+	if got := reloaded.get(models.PackageTypeFormula, "neovim"); !got.HEAD {
+		t.Errorf("get() = %+v, want the saved options", got)
+	}
+}
+
+func TestInstallOptionsStore_ReadsOptionsSavedInCache(t *testing.T) {
+	previousState, previousCache := xdg.StateHome, xdg.CacheHome
+	xdg.StateHome, xdg.CacheHome = t.TempDir(), t.TempDir()
+	t.Cleanup(func() { xdg.StateHome, xdg.CacheHome = previousState, previousCache })
+
+	if err := ensureCacheDir(); err != nil {
+		t.Fatal(err)
+	}
+	writeCacheFile(fileInstallOptions, []byte(`{"cask:firefox":{"no_quarantine":true}}`))
+
+	var store installOptionsStore
+	if got := store.get(models.PackageTypeCask, "firefox"); !got.NoQuarantine {
+		t.Errorf("get() = %+v, want the options saved by an earlier version", got)
+	}
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// jobsPanelRefresh is how often the open jobs panel redraws, so the output of the
+// running job scrolls as it is written.
+const jobsPanelRefresh = 500 * time.Millisecond
+
+// jobLog collects the output of a job while the jobs panel reads it.
+type jobLog struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.Write(p)
+}
+
+func (l *jobLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+// queuedJob is a job of the queue with its output.
+type queuedJob struct {
+	models.Job
+	log jobLog
+}
+
+// jobQueue runs package operations one at a time in the background, so the user
+// can keep browsing while they run. Finished jobs are kept with their output
+// until cleared, and failed ones can be retried.
+type jobQueue struct {
+	mu      sync.Mutex
+	jobs    []*queuedJob
+	nextID  int
+	running bool
+
+	succeeded, failed int // Jobs finished since the queue last started
+
+	run      func(job models.Job, output io.Writer) error
+	onChange func()                      // Called from the worker when a job starts or finishes
+	onIdle   func(succeeded, failed int) // Called from the worker once no job is left
+}
+
+// newJobQueue creates a queue running each job with run.
+func newJobQueue(run func(models.Job, io.Writer) error, onChange func(), onIdle func(succeeded, failed int)) *jobQueue {
+	return &jobQueue{run: run, onChange: onChange, onIdle: onIdle, nextID: 1}
+}
+
+// Enqueue adds a job per package and starts the worker if it is idle. Packages
+// that already have the same action queued or running are left out.
+func (q *jobQueue) Enqueue(action models.JobAction, packages []models.Package) []models.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var added []models.Job
+	for _, pkg := range packages {
+		if q.pendingLocked(action, pkg) {
+			continue
+		}
+		job := &queuedJob{Job: models.Job{ID: q.nextID, Action: action, Package: pkg, Status: models.JobQueued}}
+		q.nextID++
+		q.jobs = append(q.jobs, job)
+		added = append(added, job.Job)
+	}
+	q.startLocked()
+	return added
+}
+
+// pendingLocked reports whether a package has the action queued or running.
+func (q *jobQueue) pendingLocked(action models.JobAction, pkg models.Package) bool {
+	for _, job := range q.jobs {
+		if !job.Done() && job.Action == action && job.Package.Type == pkg.Type && job.Package.Name == pkg.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry queues a failed job again, keeping the output of the failed attempt.
+func (q *jobQueue) Retry(id int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job := q.findLocked(id)
+	if job == nil || job.Status != models.JobFailed {
+		return false
+	}
+	job.Status = models.JobQueued
+	job.Error = ""
+	job.Started, job.Finished = time.Time{}, time.Time{}
+	_, _ = fmt.Fprintf(&job.log, "\n==> Retrying\n")
+	q.startLocked()
+	return true
+}
+
+// ClearSucceeded drops the jobs that succeeded and returns how many were dropped.
+// Failed jobs stay listed so they can be retried.
+func (q *jobQueue) ClearSucceeded() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kept := q.jobs[:0]
+	for _, job := range q.jobs {
+		if job.Status != models.JobSucceeded {
+			kept = append(kept, job)
+		}
+	}
+	cleared := len(q.jobs) - len(kept)
+	q.jobs = kept
+	return cleared
+}
+
+// Jobs returns a copy of the jobs in the order they were queued.
+func (q *jobQueue) Jobs() []models.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]models.Job, len(q.jobs))
+	for i, job := range q.jobs {
+		jobs[i] = job.Job
+	}
+	return jobs
+}
+
+// Log returns the output of a job so far.
+func (q *jobQueue) Log(id int) string {
+	q.mu.Lock()
+	job := q.findLocked(id)
+	q.mu.Unlock()
+	if job == nil {
+		return ""
+	}
+	return job.log.String()
+}
+
+// Pending returns the number of jobs queued or running.
+func (q *jobQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := 0
+	for _, job := range q.jobs {
+		if !job.Done() {
+			pending++
+		}
+	}
+	return pending
+}
+
+func (q *jobQueue) findLocked(id int) *queuedJob {
+	for _, job := range q.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// startLocked starts the worker unless it is already running.
+func (q *jobQueue) startLocked() {
+	if q.running {
+		return
+	}
+	q.running = true
+	q.succeeded, q.failed = 0, 0
+	go q.work()
+}
+
+// work runs the queued jobs in order until none is left.
+func (q *jobQueue) work() {
+	for {
+		q.mu.Lock()
+		var job *queuedJob
+		for _, candidate := range q.jobs {
+			if candidate.Status == models.JobQueued {
+				job = candidate
+				break
+			}
+		}
+		if job == nil {
+			q.running = false
+			succeeded, failed := q.succeeded, q.failed
+			q.mu.Unlock()
+			if q.onIdle != nil {
+				q.onIdle(succeeded, failed)
+			}
+			return
+		}
+		job.Status = models.JobRunning
+		job.Started = time.Now()
+		snapshot := job.Job
+		q.mu.Unlock()
+		q.notify()
+
+		_, _ = fmt.Fprintf(&job.log, "==> %s %s\n", snapshot.Action.Verb(), snapshot.Package.Label())
+		err := q.run(snapshot, &job.log)
+
+		q.mu.Lock()
+		job.Finished = time.Now()
+		if err != nil {
+			job.Status = models.JobFailed
+			job.Error = err.Error()
+			q.failed++
+		} else {
+			job.Status = models.JobSucceeded
+			q.succeeded++
+		}
+		q.mu.Unlock()
+		if err != nil {
+			_, _ = fmt.Fprintf(&job.log, "==> Failed: %v\n", err)
+		}
+		q.notify()
+	}
+}
+
+func (q *jobQueue) notify() {
+	if q.onChange != nil {
+		q.onChange()
+	}
+}
+
+// jobSkipReason tells why an action does not apply to a package, e.g. installing
+// a package that is already installed.
+func jobSkipReason(action models.JobAction, pkg models.Package) (string, bool) {
+	switch {
+	case action == models.JobInstall && pkg.LocallyInstalled:
+		return "already installed", true
+	case action != models.JobInstall && !pkg.LocallyInstalled:
+		return "not installed", true
+	case action == models.JobUpgrade && pkg.Type == models.PackageTypeMas:
+		return "updated through the App Store", true
+	case action == models.JobUpgrade && pkg.Pinned:
+		return "pinned", true
+	case action == models.JobUpgrade && !pkg.Outdated:
+		return "up to date", true
+	}
+	return "", false
+}
+
+// runJob runs a queued install, removal or upgrade, writing the output to the job log.
+func (s *InputService) runJob(job models.Job, output io.Writer) error {
+	pkg := job.Package
+	switch job.Action {
+	case models.JobInstall:
+		switch pkg.Type {
+		case models.PackageTypeFlatpak:
+			return s.flatpakService.InstallPackage(pkg, output)
+		case models.PackageTypeMas:
+			return s.appService.masService.InstallApp(pkg, output)
+		}
+		if err := s.brewService.InstallPackage(pkg, s.appService.InstallOptions(pkg), output); err != nil {
+			return err
+		}
+		return s.restartBrewfileService(pkg, output)
+	case models.JobRemove:
+		switch pkg.Type {
+		case models.PackageTypeFlatpak:
+			return s.flatpakService.RemovePackage(pkg, output)
+		case models.PackageTypeMas:
+			return s.appService.masService.RemoveApp(pkg, output)
+		}
+		return s.brewService.RemovePackage(pkg, output)
+	case models.JobUpgrade:
+		if pkg.Type == models.PackageTypeFlatpak {
+			return s.flatpakService.UpdatePackage(pkg, output)
+		}
+		return s.brewService.UpdatePackage(pkg, output)
+	}
+	return fmt.Errorf("unknown job action %q", job.Action)
+}
+
+// watchJobsPanel redraws the jobs panel until done is closed.
+func (s *InputService) watchJobsPanel(done <-chan struct{}) {
+	ticker := time.NewTicker(jobsPanelRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.appService.app.QueueUpdateDraw(s.refreshJobsPanel)
+		}
+	}
+}
+
+// jobsRunning refuses to start a brew command next to queued jobs, as two brew
+// processes would contend for the Homebrew lock. It reports whether jobs are pending.
+func (s *InputService) jobsRunning() bool {
+	pending := s.jobs.Pending()
+	if pending == 0 {
+		return false
+	}
+	s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Wait for the %d queued job%s to finish (J to follow them)", pending, pluralS(pending)))
+	return true
+}
+
+// isMarked reports whether the row of a package is marked for a batch of jobs.
+func (s *AppService) isMarked(pkg models.Package) bool {
+	return s.marked[packageKey(pkg.Type, pkg.Name)]
+}
+
+// toggleMarkSelected marks the selected row, or unmarks it, then moves to the next row.
+func (s *AppService) toggleMarkSelected() bool {
+	table := s.layout.GetTable().View()
+	row, _ := table.GetSelection()
+	if row <= 0 || row-1 >= len(*s.filteredPackages) {
+		return false
+	}
+	pkg := (*s.filteredPackages)[row-1]
+	key := packageKey(pkg.Type, pkg.Name)
+	if s.marked == nil {
+		s.marked = make(map[string]bool)
+	}
+	if s.marked[key] {
+		delete(s.marked, key)
+	} else {
+		s.marked[key] = true
+	}
+	table.SetCell(row, 0, s.typeCell(&pkg))
+	if row < len(*s.filteredPackages) {
+		table.Select(row+1, 0)
+	}
+	return true
+}
+
+// markAllVisible marks every row of the table, or clears all marks when the rows
+// are all marked already.
+func (s *AppService) markAllVisible() {
+	allMarked := len(*s.filteredPackages) > 0
+	for _, pkg := range *s.filteredPackages {
+		if !s.isMarked(pkg) {
+			allMarked = false
+			break
+		}
+	}
+	if allMarked {
+		s.clearMarks()
+		return
+	}
+	if s.marked == nil {
+		s.marked = make(map[string]bool)
+	}
+	for _, pkg := range *s.filteredPackages {
+		s.marked[packageKey(pkg.Type, pkg.Name)] = true
+	}
+	s.redrawMarks()
+}
+
+// clearMarks unmarks all rows.
+func (s *AppService) clearMarks() {
+	s.marked = nil
+	s.redrawMarks()
+}
+
+// redrawMarks redraws the table after marks changed, keeping the selected row.
+func (s *AppService) redrawMarks() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	s.setResults(s.filteredPackages, false)
+	s.layout.GetTable().View().Select(row, 0)
+}
+
+// markedPackages returns the marked packages, including those hidden by the
+// current search or filter.
+func (s *AppService) markedPackages() []models.Package {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var marked []models.Package
+	seen := make(map[string]bool, len(s.marked))
+	collect := func(packages *[]models.Package) {
+		if packages == nil {
+			return
+		}
+		for _, pkg := range *packages {
+			key := packageKey(pkg.Type, pkg.Name)
+			if s.marked[key] && !seen[key] {
+				seen[key] = true
+				marked = append(marked, pkg)
+			}
+		}
+	}
+	collect(s.filteredPackages)
+	collect(s.brewfilePackages)
+	collect(s.packages)
+	return marked
+}
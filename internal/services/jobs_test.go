@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// idleWaiter returns an onIdle callback and a function waiting for it.
+func idleWaiter(t *testing.T) (func(int, int), func() [2]int) {
+	t.Helper()
+	idle := make(chan [2]int, 4)
+	return func(succeeded, failed int) { idle <- [2]int{succeeded, failed} },
+		func() [2]int {
+			select {
+			case counts := <-idle:
+				return counts
+			case <-time.After(5 * time.Second):
+				t.Fatal("job queue did not finish")
+				return [2]int{}
+			}
+		}
+}
+
+func formulae(names ...string) []models.Package {
+	packages := make([]models.Package, len(names))
+	for i, name := range names {
+		packages[i] = models.Package{Name: name, Type: models.PackageTypeFormula}
+	}
+	return packages
+}
+
+func TestJobQueue_RunsJobsInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var ran []string
+	running := 0
+	run := func(job models.Job, output io.Writer) error {
+		mu.Lock()
+		running++
+		if running > 1 {
+			t.Error("jobs ran concurrently")
+		}
+		ran = append(ran, string(job.Action)+" "+job.Package.Name)
+		mu.Unlock()
+		_, _ = fmt.Fprintln(output, "done")
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+	onIdle, wait := idleWaiter(t)
+	q := newJobQueue(run, nil, onIdle)
+
+	added := q.Enqueue(models.JobInstall, formulae("wget", "jq", "curl"))
+	if len(added) != 3 || added[0].ID != 1 || added[2].ID != 3 {
+		t.Fatalf("added = %+v, want jobs 1 to 3", added)
+	}
+	if counts := wait(); counts != [2]int{3, 0} {
+		t.Errorf("idle counts = %v, want 3 succeeded", counts)
+	}
+
+	want := []string{"install wget", "install jq", "install curl"}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	for _, job := range q.Jobs() {
+		if job.Status != models.JobSucceeded || job.Finished.IsZero() {
+			t.Errorf("job %d = %s, want succeeded with a finish time", job.ID, job.Status)
+		}
+	}
+	if log := q.Log(1); log != "==> Installing wget\ndone\n" {
+		t.Errorf("log = %q", log)
+	}
+}
+
+func TestJobQueue_SkipsPendingDuplicates(t *testing.T) {
+	release := make(chan struct{})
+	onIdle, wait := idleWaiter(t)
+	q := newJobQueue(func(models.Job, io.Writer) error { <-release; return nil }, nil, onIdle)
+
+	q.Enqueue(models.JobInstall, formulae("wget"))
+	if added := q.Enqueue(models.JobInstall, formulae("wget", "jq")); len(added) != 1 || added[0].Package.Name != "jq" {
+		t.Errorf("added = %+v, want only jq", added)
+	}
+	if added := q.Enqueue(models.JobRemove, formulae("wget")); len(added) != 1 {
+		t.Errorf("a removal of wget should queue next to its install, got %+v", added)
+	}
+	if pending := q.Pending(); pending != 3 {
+		t.Errorf("Pending() = %d, want 3", pending)
+	}
+	close(release)
+	wait()
+}
+
+func TestJobQueue_RetryFailedJob(t *testing.T) {
+	attempts := 0
+	run := func(job models.Job, output io.Writer) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+	onIdle, wait := idleWaiter(t)
+	q := newJobQueue(run, nil, onIdle)
+
+	q.Enqueue(models.JobUpgrade, formulae("node"))
+	if counts := wait(); counts != [2]int{0, 1} {
+		t.Fatalf("idle counts = %v, want 1 failed", counts)
+	}
+	job := q.Jobs()[0]
+	if job.Status != models.JobFailed || job.Error != "exit status 1" {
+		t.Fatalf("job = %s %q, want failed with the error", job.Status, job.Error)
+	}
+
+	if q.Retry(42) {
+		t.Error("Retry of an unknown job should fail")
+	}
+	if !q.Retry(job.ID) {
+		t.Fatal("Retry of a failed job should queue it")
+	}
+	if counts := wait(); counts != [2]int{1, 0} {
+		t.Errorf("idle counts after retry = %v, want 1 succeeded", counts)
+	}
+	if q.Retry(job.ID) {
+		t.Error("Retry of a succeeded job should fail")
+	}
+	log := q.Log(job.ID)
+	if !strings.Contains(log, "==> Failed: exit status 1") || !strings.Contains(log, "==> Retrying") {
+		t.Errorf("log should keep the failed attempt, got %q", log)
+	}
+}
+
+func TestJobQueue_ClearSucceeded(t *testing.T) {
+	run := func(job models.Job, output io.Writer) error {
+		if job.Package.Name == "broken" {
+			return errors.New("failed")
+		}
+		return nil
+	}
+	onIdle, wait := idleWaiter(t)
+	q := newJobQueue(run, nil, onIdle)
+
+	q.Enqueue(models.JobInstall, formulae("wget", "broken", "jq"))
+	wait()
+	if cleared := q.ClearSucceeded(); cleared != 2 {
+		t.Errorf("ClearSucceeded() = %d, want 2", cleared)
+	}
+	if jobs := q.Jobs(); len(jobs) != 1 || jobs[0].Package.Name != "broken" {
+		t.Errorf("jobs = %+v, want only the failed one", jobs)
+	}
+}
+
+func TestJobSkipReason(t *testing.T) {
+	installed := models.Package{Name: "wget", Type: models.PackageTypeFormula, LocallyInstalled: true}
+	outdated := installed
+	outdated.Outdated = true
+	pinned := outdated
+	pinned.Pinned = true
+	app := models.Package{Name: "Xcode", Type: models.PackageTypeMas, LocallyInstalled: true, Outdated: true}
+
+	tests := []struct {
+		action models.JobAction
+		pkg    models.Package
+		want   string
+	}{
+		{models.JobInstall, installed, "already installed"},
+		{models.JobInstall, formulae("jq")[0], ""},
+		{models.JobRemove, formulae("jq")[0], "not installed"},
+		{models.JobRemove, installed, ""},
+		{models.JobUpgrade, installed, "up to date"},
+		{models.JobUpgrade, pinned, "pinned"},
+		{models.JobUpgrade, app, "updated through the App Store"},
+		{models.JobUpgrade, outdated, ""},
+	}
+	for _, tt := range tests {
+		reason, skip := jobSkipReason(tt.action, tt.pkg)
+		if reason != tt.want || skip != (tt.want != "") {
+			t.Errorf("jobSkipReason(%s, %s) = %q, %v, want %q", tt.action, tt.pkg.Name, reason, skip, tt.want)
+		}
+	}
+}
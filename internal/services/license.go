@@ -0,0 +1,309 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"bbrew/internal/models"
+)
+
+// License flags of the compliance report.
+const (
+	licenseMissing = "missing" // The formula declares no license
+	licenseUnknown = "unknown" // No license data (casks, Flatpak, App Store), or not an SPDX expression
+)
+
+// homebrewLicenses are license values Homebrew writes that are not SPDX identifiers
+// but still name a known license.
+var homebrewLicenses = map[string]bool{"Public Domain": true}
+
+// licenseExpr is a parsed SPDX license expression: a license identifier, optionally
+// WITH an exception, or the AND/OR of two expressions.
+type licenseExpr struct {
+	op          string // "AND" or "OR", empty for an identifier
+	left, right *licenseExpr
+	id          string
+	exception   string
+}
+
+// parseLicenseExpression parses an SPDX expression such as
+// "(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0".
+// Operators are case-insensitive; AND binds tighter than OR.
+func parseLicenseExpression(expr string) (*licenseExpr, error) {
+	p := &licenseParser{tokens: tokenizeLicense(expr)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+// tokenizeLicense splits an expression into identifiers, operators and parentheses.
+func tokenizeLicense(expr string) []string {
+	var tokens []string
+	start := -1
+	for i, r := range expr {
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			if start >= 0 {
+				tokens = append(tokens, expr[start:i])
+				start = -1
+			}
+			if r == '(' || r == ')' {
+				tokens = append(tokens, string(r))
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, expr[start:])
+	}
+	return tokens
+}
+
+// licenseParser is a recursive descent parser over the tokens of an expression.
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *licenseParser) peekOperator(op string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], op)
+}
+
+func (p *licenseParser) parseOr() (*licenseExpr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peekOperator("OR") {
+		p.pos++
+		var right *licenseExpr
+		if right, err = p.parseAnd(); err == nil {
+			left = &licenseExpr{op: "OR", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *licenseParser) parseAnd() (*licenseExpr, error) {
+	left, err := p.parseWith()
+	for err == nil && p.peekOperator("AND") {
+		p.pos++
+		var right *licenseExpr
+		if right, err = p.parseWith(); err == nil {
+			left = &licenseExpr{op: "AND", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *licenseParser) parseWith() (*licenseExpr, error) {
+	node, err := p.parsePrimary()
+	if err != nil || !p.peekOperator("WITH") {
+		return node, err
+	}
+	p.pos++
+	if node.op != "" || p.pos >= len(p.tokens) || isLicenseKeyword(p.tokens[p.pos]) {
+		return nil, fmt.Errorf("WITH must join a license and an exception")
+	}
+	node.exception = p.tokens[p.pos]
+	p.pos++
+	return node, nil
+}
+
+func (p *licenseParser) parsePrimary() (*licenseExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("expression ends early")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch {
+	case tok == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return node, nil
+	case tok == ")" || isLicenseKeyword(tok):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		return &licenseExpr{id: tok}, nil
+	}
+}
+
+func isLicenseKeyword(tok string) bool {
+	return strings.EqualFold(tok, "AND") || strings.EqualFold(tok, "OR") || strings.EqualFold(tok, "WITH") || tok == "(" || tok == ")"
+}
+
+// identifiers lists the licenses and exceptions the expression mentions.
+func (e *licenseExpr) identifiers() []string {
+	if e.op != "" {
+		return append(e.left.identifiers(), e.right.identifiers()...)
+	}
+	if e.exception != "" {
+		return []string{e.id, e.exception}
+	}
+	return []string{e.id}
+}
+
+// avoids reports whether the expression can be satisfied without any license
+// matching the pattern, e.g. "MIT OR AGPL-3.0-only" avoids AGPL* but
+// "MIT AND AGPL-3.0-only" does not.
+func (e *licenseExpr) avoids(pattern string) bool {
+	switch e.op {
+	case "AND":
+		return e.left.avoids(pattern) && e.right.avoids(pattern)
+	case "OR":
+		return e.left.avoids(pattern) || e.right.avoids(pattern)
+	default:
+		return !matchLicenseID(pattern, e.id)
+	}
+}
+
+// matchesAny evaluates a query expression against a set of identifiers: each
+// identifier of the query is true when one of ids matches it.
+func (e *licenseExpr) matchesAny(ids []string) bool {
+	switch e.op {
+	case "AND":
+		return e.left.matchesAny(ids) && e.right.matchesAny(ids)
+	case "OR":
+		return e.left.matchesAny(ids) || e.right.matchesAny(ids)
+	}
+	for _, id := range ids {
+		if matchLicenseID(e.id, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLicenseID matches a license identifier against a case-insensitive pattern,
+// where * stands for any text (GPL-* matches GPL-3.0-or-later).
+func matchLicenseID(pattern, id string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(id))
+	return matched && err == nil
+}
+
+// packageLicense returns the license a package declares: only formulae carry one.
+func packageLicense(p *models.Package) string {
+	if p.Formula == nil {
+		return ""
+	}
+	return p.Formula.License
+}
+
+// packageLicenseIDs lists the identifiers of a package license. Values that are not
+// SPDX expressions are split on spaces and parentheses.
+func packageLicenseIDs(p *models.Package) []string {
+	license := packageLicense(p)
+	if expr, err := parseLicenseExpression(license); err == nil {
+		return expr.identifiers()
+	}
+	return strings.FieldsFunc(license, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')'
+	})
+}
+
+// licenseFlag tells why a package license needs a review: licenseMissing,
+// licenseUnknown, or an empty string when it is a known license.
+func licenseFlag(p *models.Package) string {
+	license := packageLicense(p)
+	switch {
+	case p.Formula == nil:
+		return licenseUnknown
+	case license == "":
+		return licenseMissing
+	case homebrewLicenses[license]:
+		return ""
+	}
+	if _, err := parseLicenseExpression(license); err != nil {
+		return licenseUnknown
+	}
+	return ""
+}
+
+// licenseReportEntry is a line of the license compliance report.
+type licenseReportEntry struct {
+	Type    models.PackageType `json:"type"`
+	Name    string             `json:"name"`
+	Version string             `json:"version"`
+	License string             `json:"license"`
+	Flag    string             `json:"flag,omitempty"` // licenseMissing or licenseUnknown
+}
+
+// licenseReport lists the installed packages with their license, formulae first.
+func licenseReport(packages []models.Package) []licenseReportEntry {
+	var entries []licenseReportEntry
+	for i := range packages {
+		p := &packages[i]
+		if !p.LocallyInstalled {
+			continue
+		}
+		entries = append(entries, licenseReportEntry{
+			Type:    p.Type,
+			Name:    p.Name,
+			Version: p.Version,
+			License: packageLicense(p),
+			Flag:    licenseFlag(p),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type == models.PackageTypeFormula
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// writeLicenseReport writes the report as CSV (with a header row) or JSON.
+func writeLicenseReport(w io.Writer, entries []licenseReportEntry, format string) error {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"type", "name", "version", "license", "flag"})
+		for _, e := range entries {
+			_ = cw.Write([]string{string(e.Type), e.Name, e.Version, e.License, e.Flag})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		if entries == nil {
+			entries = []licenseReportEntry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	default:
+		return fmt.Errorf("unknown format %q (use csv or json)", format)
+	}
+}
+
+// PrintLicenses writes the licenses of the installed packages to w, in csv or json,
+// flagging missing and unknown ones (bbrew licenses).
+func PrintLicenses(w io.Writer, format string) error {
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unknown format %q (use csv or json)", format)
+	}
+	d := NewDataProvider()
+	if err := d.SetupData(false); err != nil {
+		return err
+	}
+	return writeLicenseReport(w, licenseReport(*d.GetPackages()), format)
+}
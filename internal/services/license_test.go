@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestParseLicenseExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantIDs []string
+		wantErr bool
+	}{
+		{"MIT", []string{"MIT"}, false},
+		{"Apache-2.0 OR MIT", []string{"Apache-2.0", "MIT"}, false},
+		{"(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0", []string{"MIT", "Apache-2.0", "GPL-2.0-only", "Classpath-exception-2.0"}, false},
+		{"mit or bsd-3-clause", []string{"mit", "bsd-3-clause"}, false},
+		{"", nil, true},
+		{"MIT OR", nil, true},
+		{"(MIT", nil, true},
+		{"MIT Apache-2.0", nil, true},
+		{"Public Domain", nil, true},
+		{"(MIT OR Apache-2.0) WITH LLVM-exception", nil, true},
+	}
+
+	for _, tt := range tests {
+		expr, err := parseLicenseExpression(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLicenseExpression(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(expr.identifiers(), tt.wantIDs) {
+			t.Errorf("parseLicenseExpression(%q) identifiers = %v, want %v", tt.expr, expr.identifiers(), tt.wantIDs)
+		}
+	}
+}
+
+func TestLicenseExpr_Avoids(t *testing.T) {
+	tests := []struct {
+		license string
+		pattern string
+		want    bool
+	}{
+		{"MIT OR AGPL-3.0-only", "AGPL*", true},
+		{"MIT AND AGPL-3.0-only", "AGPL*", false},
+		{"AGPL-3.0-or-later", "agpl-*", false},
+		{"(GPL-2.0-only OR AGPL-3.0-only) AND MIT", "*GPL*", false},
+		{"(GPL-2.0-only OR MIT) AND BSD-2-Clause", "GPL-*", true},
+		{"Apache-2.0", "AGPL*", true},
+	}
+
+	for _, tt := range tests {
+		expr, err := parseLicenseExpression(tt.license)
+		if err != nil {
+			t.Fatalf("parseLicenseExpression(%q) error: %v", tt.license, err)
+		}
+		if got := expr.avoids(tt.pattern); got != tt.want {
+			t.Errorf("%q avoids %q = %v, want %v", tt.license, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestLicenseFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  models.Package
+		want string
+	}{
+		{"spdx", models.Package{Formula: &models.Formula{License: "BSD-2-Clause"}}, ""},
+		{"public domain", models.Package{Formula: &models.Formula{License: "Public Domain"}}, ""},
+		{"missing", models.Package{Formula: &models.Formula{}}, licenseMissing},
+		{"not spdx", models.Package{Formula: &models.Formula{License: "Cannot Represent"}}, licenseUnknown},
+		{"cask", models.Package{Cask: &models.Cask{Token: "firefox"}}, licenseUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := licenseFlag(&tt.pkg); got != tt.want {
+			t.Errorf("%s: licenseFlag() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLicenseReport(t *testing.T) {
+	entries := licenseReport(queryTestPackages())
+	want := []licenseReportEntry{
+		{Type: models.PackageTypeFormula, Name: "curl", License: "curl"},
+		{Type: models.PackageTypeFormula, Name: "openssl@3", License: "Apache-2.0"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("licenseReport() = %+v, want %+v", entries, want)
+	}
+
+	entries = append(entries, licenseReportEntry{Type: models.PackageTypeCask, Name: "firefox", Version: "131.0", Flag: licenseUnknown})
+	var buf bytes.Buffer
+	if err := writeLicenseReport(&buf, entries, "csv"); err != nil {
+		t.Fatalf("writeLicenseReport(csv) error: %v", err)
+	}
+	wantCSV := "type,name,version,license,flag\nformula,curl,,curl,\nformula,openssl@3,,Apache-2.0,\ncask,firefox,131.0,,unknown\n"
+	if buf.String() != wantCSV {
+		t.Errorf("csv report = %q, want %q", buf.String(), wantCSV)
+	}
+
+	buf.Reset()
+	if err := writeLicenseReport(&buf, entries, "json"); err != nil {
+		t.Fatalf("writeLicenseReport(json) error: %v", err)
+	}
+	var decoded []licenseReportEntry
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, entries) {
+		t.Errorf("json report = %s (error %v), want %+v", buf.String(), err, entries)
+	}
+
+	if err := writeLicenseReport(&buf, entries, "xml"); err == nil {
+		t.Error("writeLicenseReport(xml) should fail")
+	}
+}
@@ -18,6 +18,7 @@ type MasAppInfo struct {
 type MasServiceInterface interface {
 	IsMasInstalled() bool
 	GetInstalledApps() (map[string]bool, error)
+	ListInstalledApps() []models.Package
 	GetAppInfo(appID string) (*MasAppInfo, error)
 	InstallApp(info models.Package, output io.Writer) error
 	RemoveApp(info models.Package, output io.Writer) error
@@ -58,6 +59,39 @@ func (s *MasService) GetInstalledApps() (map[string]bool, error) {
 	return installed, nil
 }
 
+// ListInstalledApps returns the installed Mac App Store apps with their name and version,
+// or nothing when mas is not available.
+func (s *MasService) ListInstalledApps() []models.Package {
+	output, err := exec.Command("mas", "list").Output()
+	if err != nil {
+		return nil
+	}
+	return parseMasList(string(output))
+}
+
+// parseMasList parses `mas list` lines such as "497799835  Xcode  (15.4)".
+func parseMasList(output string) []models.Package {
+	var apps []models.Package
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		id, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		name, version := strings.TrimSpace(rest), ""
+		if open := strings.LastIndex(name, "("); open > 0 && strings.HasSuffix(name, ")") {
+			name, version = strings.TrimSpace(name[:open]), name[open+1:len(name)-1]
+		}
+		apps = append(apps, models.Package{
+			Name:             id,
+			DisplayName:      name,
+			Version:          version,
+			Type:             models.PackageTypeMas,
+			LocallyInstalled: true,
+		})
+	}
+	return apps
+}
+
 // GetAppInfo retrieves metadata for a Mac App Store app via `mas info`.
 // Output is a table with "▁" separators, e.g.:
 //
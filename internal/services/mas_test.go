@@ -128,3 +128,22 @@ func TestParseMasInfoOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMasList(t *testing.T) {
+	output := "497799835  Xcode          (15.4)\n1168254295 AmorphousDiskMark (4.0.1)\n904280696  Things 3 (3.20.1)\n"
+	apps := parseMasList(output)
+	want := []struct{ id, name, version string }{
+		{"497799835", "Xcode", "15.4"},
+		{"1168254295", "AmorphousDiskMark", "4.0.1"},
+		{"904280696", "Things 3", "3.20.1"},
+	}
+	if len(apps) != len(want) {
+		t.Fatalf("parseMasList() = %+v, want %d apps", apps, len(want))
+	}
+	for i, w := range want {
+		app := apps[i]
+		if app.Name != w.id || app.DisplayName != w.name || app.Version != w.version || app.Type != models.PackageTypeMas || !app.LocallyInstalled {
+			t.Errorf("app %d = %+v, want %s %q %s", i, app, w.id, w.name, w.version)
+		}
+	}
+}
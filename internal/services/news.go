@@ -0,0 +1,231 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// News: each time the formula and cask catalogues are downloaded again, they are
+// compared with a name index of the previous ones and the changes are logged.
+const (
+	cacheFileCatalogIndex = "catalog-index.json"
+	cacheFileNews         = "news.json"
+	newsRetention         = 90 * 24 * time.Hour
+	newsMaxItems          = 2000
+)
+
+// catalogIndexEntry is what is remembered about a package between catalogue refreshes.
+type catalogIndexEntry struct {
+	Description string `json:"desc,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// catalogIndex lists the formulae and casks of a catalogue by name.
+type catalogIndex struct {
+	Formulae map[string]catalogIndexEntry `json:"formulae"`
+	Casks    map[string]catalogIndexEntry `json:"casks"`
+}
+
+// newCatalogIndex builds the name index of a catalogue.
+func newCatalogIndex(formulae []models.Formula, casks []models.Cask) *catalogIndex {
+	index := &catalogIndex{
+		Formulae: make(map[string]catalogIndexEntry, len(formulae)),
+		Casks:    make(map[string]catalogIndexEntry, len(casks)),
+	}
+	for _, f := range formulae {
+		index.Formulae[f.Name] = catalogIndexEntry{f.Description, f.Deprecated, f.Disabled}
+	}
+	for _, c := range casks {
+		index.Casks[c.Token] = catalogIndexEntry{c.Description, c.Deprecated, c.Disabled}
+	}
+	return index
+}
+
+// diffCatalogIndex lists the packages added, removed, newly deprecated or newly
+// disabled between two catalogues, ordered by kind, type and name.
+func diffCatalogIndex(prev, next *catalogIndex, now time.Time) []models.NewsItem {
+	var items []models.NewsItem
+	diff := func(pkgType models.PackageType, before, after map[string]catalogIndexEntry) {
+		for name, entry := range after {
+			old, existed := before[name]
+			item := models.NewsItem{Type: pkgType, Name: name, Description: entry.Description, Time: now}
+			switch {
+			case !existed:
+				item.Kind = models.NewsAdded
+			case entry.Disabled && !old.Disabled:
+				item.Kind = models.NewsDisabled
+			case entry.Deprecated && !old.Deprecated:
+				item.Kind = models.NewsDeprecated
+			default:
+				continue
+			}
+			items = append(items, item)
+		}
+		for name, entry := range before {
+			if _, exists := after[name]; !exists {
+				items = append(items, models.NewsItem{Kind: models.NewsRemoved, Type: pkgType, Name: name, Description: entry.Description, Time: now})
+			}
+		}
+	}
+	diff(models.PackageTypeFormula, prev.Formulae, next.Formulae)
+	diff(models.PackageTypeCask, prev.Casks, next.Casks)
+
+	kindOrder := map[models.NewsKind]int{models.NewsAdded: 0, models.NewsRemoved: 1, models.NewsDeprecated: 2, models.NewsDisabled: 3}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Type != b.Type {
+			return a.Type == models.PackageTypeFormula
+		}
+		return a.Name < b.Name
+	})
+	return items
+}
+
+// catalogModTime returns when the formula or cask catalogue cache was last written.
+func catalogModTime() time.Time {
+	var latest time.Time
+	for _, name := range []string{cacheFileFormulae, cacheFileCasks} {
+		if info, err := os.Stat(filepath.Join(getCacheDir(), name)); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// updateNews logs what changed since the previous catalogue when the catalogue cache
+// is newer than the index, then indexes the current catalogue. The first run only
+// writes the index, so the whole catalogue is not reported as new.
+func updateNews(formulae []models.Formula, casks []models.Cask, now time.Time) error {
+	if len(formulae) == 0 || len(casks) == 0 {
+		return nil // A partial catalogue would report everything as removed
+	}
+
+	indexPath := filepath.Join(getCacheDir(), cacheFileCatalogIndex)
+	info, err := os.Stat(indexPath)
+	if err == nil && !catalogModTime().After(info.ModTime()) {
+		return nil // Catalogue unchanged since it was indexed
+	}
+
+	next := newCatalogIndex(formulae, casks)
+	if err == nil {
+		if data := readStaleCacheFile(cacheFileCatalogIndex, 2); data != nil {
+			var prev catalogIndex
+			if json.Unmarshal(data, &prev) == nil {
+				if err := appendNews(diffCatalogIndex(&prev, next, now), now); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	data, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+	writeCacheFile(cacheFileCatalogIndex, data)
+	return nil
+}
+
+// loadNews reads the news log, newest first.
+func loadNews() ([]models.NewsItem, error) {
+	data := readStaleCacheFile(cacheFileNews, 2)
+	if data == nil {
+		return nil, nil
+	}
+	var items []models.NewsItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse news: %w", err)
+	}
+	return items, nil
+}
+
+// appendNews adds items to the front of the news log, dropping entries past the retention period.
+func appendNews(items []models.NewsItem, now time.Time) error {
+	if len(items) == 0 {
+		return nil
+	}
+	existing, _ := loadNews()
+
+	log := append([]models.NewsItem{}, items...)
+	for _, item := range existing {
+		if now.Sub(item.Time) <= newsRetention {
+			log = append(log, item)
+		}
+	}
+	if len(log) > newsMaxItems {
+		log = log[:newsMaxItems]
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		return err
+	}
+	writeCacheFile(cacheFileNews, data)
+	return nil
+}
+
+// GetNews returns the packages added, removed, deprecated or disabled in the Homebrew
+// catalogue over the last refreshes, newest first.
+func (d *DataProvider) GetNews() ([]models.NewsItem, error) {
+	return loadNews()
+}
+
+// newsSymbols marks each kind of change in the news output.
+var newsSymbols = map[models.NewsKind]string{
+	models.NewsAdded:      "+",
+	models.NewsRemoved:    "-",
+	models.NewsDeprecated: "!",
+	models.NewsDisabled:   "x",
+}
+
+// PrintNews refreshes the catalogue when its cache has expired and writes the news
+// log to w, grouped by refresh. It backs the `bbrew news` command.
+func PrintNews(w io.Writer) error {
+	d := NewDataProvider()
+	formulae, err := d.GetRemoteFormulae(false)
+	if err != nil {
+		return fmt.Errorf("failed to get formulae: %w", err)
+	}
+	casks, err := d.GetRemoteCasks(false)
+	if err != nil {
+		return fmt.Errorf("failed to get casks: %w", err)
+	}
+	if err := updateNews(formulae, casks, time.Now()); err != nil {
+		return err
+	}
+
+	items, err := d.GetNews()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		_, err := fmt.Fprintln(w, "No changes recorded yet: news appear after the Homebrew catalogue is refreshed.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var current time.Time
+	for _, item := range items {
+		if !item.Time.Equal(current) {
+			if !current.IsZero() {
+				fmt.Fprintln(tw)
+			}
+			current = item.Time
+			fmt.Fprintf(tw, "%s\n", item.Time.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Fprintf(tw, "  %s %s\t%s\t%s\t%s\n", newsSymbols[item.Kind], item.Kind, item.Type, item.Name, item.Description)
+	}
+	return tw.Flush()
+}
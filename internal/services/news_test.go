@@ -0,0 +1,118 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+func TestDiffCatalogIndex(t *testing.T) {
+	prev := newCatalogIndex(
+		[]models.Formula{{Name: "wget"}, {Name: "youtube-dl"}, {Name: "python@3.9"}, {Name: "oldssl", Deprecated: true}},
+		[]models.Cask{{Token: "firefox"}},
+	)
+	next := newCatalogIndex(
+		[]models.Formula{{Name: "wget"}, {Name: "uv", Description: "Python package installer"}, {Name: "python@3.9", Deprecated: true}, {Name: "oldssl", Deprecated: true, Disabled: true}},
+		[]models.Cask{{Token: "firefox"}, {Token: "zed"}},
+	)
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	got := diffCatalogIndex(prev, next, now)
+	want := []struct {
+		kind    models.NewsKind
+		pkgType models.PackageType
+		name    string
+	}{
+		{models.NewsAdded, models.PackageTypeFormula, "uv"},
+		{models.NewsAdded, models.PackageTypeCask, "zed"},
+		{models.NewsRemoved, models.PackageTypeFormula, "youtube-dl"},
+		{models.NewsDeprecated, models.PackageTypeFormula, "python@3.9"},
+		{models.NewsDisabled, models.PackageTypeFormula, "oldssl"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("diffCatalogIndex() = %+v, want %d items", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Kind != w.kind || got[i].Type != w.pkgType || got[i].Name != w.name || !got[i].Time.Equal(now) {
+			t.Errorf("item %d = %+v, want %s %s %s", i, got[i], w.kind, w.pkgType, w.name)
+		}
+	}
+	if got[0].Description != "Python package installer" {
+		t.Errorf("added item description = %q", got[0].Description)
+	}
+}
+
+func TestUpdateNews(t *testing.T) {
+	useTempCacheDir(t)
+	if err := ensureCacheDir(); err != nil {
+		t.Fatal(err)
+	}
+	touchCatalogue := func(at time.Time) {
+		for _, name := range []string{cacheFileFormulae, cacheFileCasks} {
+			path := filepath.Join(getCacheDir(), name)
+			if err := os.WriteFile(path, []byte("[]"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, at, at); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	casks := []models.Cask{{Token: "firefox"}}
+	start := time.Now().Add(-time.Hour)
+
+	// First run only indexes the catalogue
+	touchCatalogue(start)
+	if err := updateNews([]models.Formula{{Name: "wget"}}, casks, start); err != nil {
+		t.Fatalf("updateNews() error: %v", err)
+	}
+	if items, _ := loadNews(); len(items) != 0 {
+		t.Fatalf("first run news = %+v, want none", items)
+	}
+
+	// An unchanged catalogue cache is not compared again
+	if err := updateNews([]models.Formula{{Name: "wget"}, {Name: "uv"}}, casks, start); err != nil {
+		t.Fatalf("updateNews() error: %v", err)
+	}
+	if items, _ := loadNews(); len(items) != 0 {
+		t.Fatalf("unchanged catalogue news = %+v, want none", items)
+	}
+
+	// A refreshed catalogue logs the differences
+	touchCatalogue(time.Now().Add(time.Minute))
+	if err := updateNews([]models.Formula{{Name: "wget"}, {Name: "uv"}}, casks, time.Now()); err != nil {
+		t.Fatalf("updateNews() error: %v", err)
+	}
+	items, err := (&DataProvider{}).GetNews()
+	if err != nil {
+		t.Fatalf("GetNews() error: %v", err)
+	}
+	if len(items) != 1 || items[0].Name != "uv" || items[0].Kind != models.NewsAdded {
+		t.Errorf("news = %+v, want uv added", items)
+	}
+}
+
+func TestAppendNews_Retention(t *testing.T) {
+	useTempCacheDir(t)
+	if err := ensureCacheDir(); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	old := models.NewsItem{Kind: models.NewsAdded, Type: models.PackageTypeFormula, Name: "old", Time: now.Add(-newsRetention - time.Hour)}
+	if err := appendNews([]models.NewsItem{old}, old.Time); err != nil {
+		t.Fatal(err)
+	}
+	recent := models.NewsItem{Kind: models.NewsRemoved, Type: models.PackageTypeCask, Name: "recent", Time: now}
+	if err := appendNews([]models.NewsItem{recent}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	items, _ := loadNews()
+	if len(items) != 1 || items[0].Name != "recent" {
+		t.Errorf("news = %+v, want only the recent item", items)
+	}
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bbrew/internal/models"
+)
+
+// searchQuery is a parsed search field: free-text terms ranked by fuzzy matching,
+// plus qualifier filters that every result must satisfy.
+//
+// Syntax (terms are combined with AND):
+//
+//	ripgrep                free text, matched fuzzily
+//	"json parser"          quoted phrase, matched as a substring
+//	type:cask              qualifier, see queryQualifiers
+//	outdated               boolean qualifier used on its own (same as outdated:yes)
+//	-tap:homebrew/core     negation, also written !tap:homebrew/core
+//	downloads:>10000       numeric comparison (>, >=, <, <=, =, or a range 100..5k)
+type searchQuery struct {
+	terms   []string
+	filters []queryFilter
+}
+
+// queryFilter is a single predicate of a query.
+type queryFilter struct {
+	negate bool
+	match  func(pkg *models.Package) bool
+}
+
+// queryError reports an invalid query and the column (1-based) where the problem starts.
+type queryError struct {
+	pos int
+	msg string
+}
+
+func (e *queryError) Error() string {
+	return fmt.Sprintf("col %d: %s", e.pos+1, e.msg)
+}
+
+// queryToken is one whitespace-separated element of the query.
+type queryToken struct {
+	pos    int    // Byte offset of the token in the query
+	negate bool   // Prefixed by - or !
+	key    string // Qualifier name, empty for free text
+	value  string
+	quoted bool // The value was a quoted phrase
+}
+
+// queryQualifier builds the predicate for a qualifier value.
+type queryQualifier func(value string) (func(pkg *models.Package) bool, error)
+
+// queryQualifiers lists the supported qualifiers.
+var queryQualifiers = map[string]queryQualifier{
+	"type":       parseTypeQualifier,
+	"installed":  boolQualifier(func(p *models.Package) bool { return p.LocallyInstalled }),
+	"outdated":   boolQualifier(func(p *models.Package) bool { return p.LocallyInstalled && p.Outdated }),
+	"deprecated": boolQualifier(func(p *models.Package) bool { return p.Deprecated }),
+	"disabled":   boolQualifier(func(p *models.Package) bool { return p.Disabled }),
+	"leaf":       boolQualifier(func(p *models.Package) bool { return p.LocallyInstalled && p.InstalledOnRequest }),
+	"pinned":     boolQualifier(func(p *models.Package) bool { return p.LocallyInstalled && p.Pinned }),
+	"linked":     boolQualifier(func(p *models.Package) bool { return p.Formula != nil && p.Formula.Linked() }),
+	"kegonly":    boolQualifier(func(p *models.Package) bool { return p.Formula != nil && p.Formula.KegOnly }),
+	"bottle":     boolQualifier(func(p *models.Package) bool { return p.Bottle.Available() }),
+	"service":    boolQualifier(func(p *models.Package) bool { return p.Formula != nil && p.Formula.Service != nil }),
+	"tap":        textQualifier(func(p *models.Package, v string) bool { return strings.EqualFold(packageTap(p), v) }),
+	"license":    parseLicenseQualifier,
+	"requires":   textQualifier(matchRequiresQualifier),
+	"licensed":   boolQualifier(func(p *models.Package) bool { return licenseFlag(p) == "" }),
+	"dep":        textQualifier(matchDependencyQualifier),
+	"name": textQualifier(func(p *models.Package, v string) bool {
+		return containsFold(p.Name, v) || containsFold(p.DisplayName, v)
+	}),
+	"desc":      textQualifier(func(p *models.Package, v string) bool { return containsFold(p.Description, v) }),
+	"downloads": numberQualifier(func(p *models.Package) int { return p.Analytics90dDownloads }),
+	"rank":      numberQualifier(func(p *models.Package) int { return p.Analytics90dRank }),
+}
+
+// booleanQualifiers may be written without a value, e.g. "outdated". The other
+// state qualifiers are plain words ("service", "bottle") that need the "key:" form,
+// so searching for them still matches text.
+var booleanQualifiers = map[string]bool{
+	"outdated": true, "deprecated": true,
+}
+
+// parseQuery parses the search field text into a searchQuery.
+func parseQuery(input string) (*searchQuery, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &searchQuery{}
+	for _, tok := range tokens {
+		switch {
+		case tok.key != "":
+			build, ok := queryQualifiers[tok.key]
+			if !ok {
+				// Only a near miss of a qualifier is an error: other "key:value"
+				// text such as a URL or "foo:bar" is searched as is
+				if name, typo := nearestQualifier(tok.key); typo {
+					return nil, &queryError{tok.pos, fmt.Sprintf("unknown qualifier %q (did you mean %q?)", tok.key+":", name+":")}
+				}
+				query.addText(tok, tok.key+":"+tok.value)
+				continue
+			}
+			if tok.value == "" {
+				return nil, &queryError{tok.pos, fmt.Sprintf("missing value after %q", tok.key+":")}
+			}
+			match, err := build(tok.value)
+			if err != nil {
+				return nil, &queryError{tok.pos, tok.key + ": " + err.Error()}
+			}
+			query.filters = append(query.filters, queryFilter{negate: tok.negate, match: match})
+
+		case !tok.quoted && booleanQualifiers[strings.ToLower(tok.value)]:
+			match, _ := queryQualifiers[strings.ToLower(tok.value)]("yes")
+			query.filters = append(query.filters, queryFilter{negate: tok.negate, match: match})
+
+		default:
+			query.addText(tok, tok.value)
+		}
+	}
+	return query, nil
+}
+
+// addText adds free text to the query: a fuzzy term, or a literal filter for phrases
+// and exclusions, as a fuzzy exclusion would hide too much.
+func (q *searchQuery) addText(tok queryToken, text string) {
+	if !tok.quoted && !tok.negate {
+		q.terms = append(q.terms, strings.ToLower(text))
+		return
+	}
+	q.filters = append(q.filters, queryFilter{negate: tok.negate, match: func(p *models.Package) bool {
+		return containsFold(p.Name, text) || containsFold(p.DisplayName, text) || containsFold(p.Description, text)
+	}})
+}
+
+// nearestQualifier returns the qualifier a key is probably a typo of: one edit or
+// swap of two adjacent letters away, for keys of three letters or more.
+func nearestQualifier(key string) (string, bool) {
+	if len(key) < 3 {
+		return "", false
+	}
+	for _, name := range slices.Sorted(maps.Keys(queryQualifiers)) {
+		if editDistance(key, name) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of adjacent
+// bytes turning a into b (optimal string alignment distance).
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// tokenizeQuery splits the query on whitespace, honouring double-quoted values.
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(input) {
+		if input[i] == ' ' || input[i] == '\t' {
+			i++
+			continue
+		}
+
+		tok := queryToken{pos: i}
+		if (input[i] == '-' || input[i] == '!') && i+1 < len(input) && input[i+1] != ' ' {
+			tok.negate = true
+			i++
+		}
+
+		// Optional "key:" prefix; a quote ends the key so "a:b" phrases stay phrases
+		start := i
+		for i < len(input) && input[i] != ' ' && input[i] != '"' && input[i] != ':' {
+			i++
+		}
+		if i < len(input) && input[i] == ':' {
+			tok.key = strings.ToLower(input[start:i])
+			if tok.key == "" {
+				return nil, &queryError{start, "missing qualifier name before ':'"}
+			}
+			i++
+			start = i
+		} else {
+			i = start
+		}
+
+		// Value: quoted phrase or bare word
+		if i < len(input) && input[i] == '"' {
+			end := strings.IndexByte(input[i+1:], '"')
+			if end < 0 {
+				return nil, &queryError{i, "unterminated quote"}
+			}
+			tok.value, tok.quoted = input[i+1:i+1+end], true
+			i += end + 2
+		} else {
+			for i < len(input) && input[i] != ' ' && input[i] != '\t' {
+				i++
+			}
+			tok.value = input[start:i]
+		}
+
+		if tok.key == "" && tok.value == "" {
+			if tok.quoted {
+				continue // Empty phrase
+			}
+			return nil, &queryError{tok.pos, "nothing to negate"}
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// match reports whether pkg satisfies every filter of the query.
+func (q *searchQuery) match(pkg *models.Package) bool {
+	for _, f := range q.filters {
+		if f.match(pkg) == f.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns the packages satisfying the query filters (free-text terms are not applied).
+func (q *searchQuery) filter(list []models.Package) []models.Package {
+	if len(q.filters) == 0 {
+		return list
+	}
+	result := make([]models.Package, 0, len(list)/4)
+	for i := range list {
+		if q.match(&list[i]) {
+			result = append(result, list[i])
+		}
+	}
+	return result
+}
+
+// boolQualifier builds a yes/no qualifier from a package predicate.
+func boolQualifier(predicate func(p *models.Package) bool) queryQualifier {
+	return func(value string) (func(*models.Package) bool, error) {
+		var want bool
+		switch strings.ToLower(value) {
+		case "yes", "y", "true", "1":
+			want = true
+		case "no", "n", "false", "0":
+			want = false
+		default:
+			return nil, fmt.Errorf("expected yes or no, got %q", value)
+		}
+		return func(p *models.Package) bool { return predicate(p) == want }, nil
+	}
+}
+
+// textQualifier builds a qualifier comparing a package with the (raw) value.
+func textQualifier(predicate func(p *models.Package, value string) bool) queryQualifier {
+	return func(value string) (func(*models.Package) bool, error) {
+		return func(p *models.Package) bool { return predicate(p, value) }, nil
+	}
+}
+
+// numberQualifier builds a numeric comparison qualifier such as downloads:>10k.
+func numberQualifier(field func(p *models.Package) int) queryQualifier {
+	return func(value string) (func(*models.Package) bool, error) {
+		if lo, hi, ok := strings.Cut(value, ".."); ok {
+			from, errLo := parseQueryNumber(lo)
+			to, errHi := parseQueryNumber(hi)
+			if errLo != nil || errHi != nil {
+				return nil, fmt.Errorf("expected a range like 100..5k, got %q", value)
+			}
+			return func(p *models.Package) bool { v := field(p); return v >= from && v <= to }, nil
+		}
+
+		number := strings.TrimLeft(value, "<>=")
+		op := value[:len(value)-len(number)]
+		n, err := parseQueryNumber(number)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number like >10000, got %q", value)
+		}
+		switch op {
+		case ">":
+			return func(p *models.Package) bool { return field(p) > n }, nil
+		case ">=":
+			return func(p *models.Package) bool { return field(p) >= n }, nil
+		case "<":
+			return func(p *models.Package) bool { return field(p) < n }, nil
+		case "<=":
+			return func(p *models.Package) bool { return field(p) <= n }, nil
+		case "", "=":
+			return func(p *models.Package) bool { return field(p) == n }, nil
+		}
+		return nil, fmt.Errorf("unknown comparison %q", op)
+	}
+}
+
+// parseQueryNumber parses a non-negative integer with an optional k or m suffix.
+func parseQueryNumber(s string) (int, error) {
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(strings.ToLower(s), "k"):
+		multiplier, s = 1_000, s[:len(s)-1]
+	case strings.HasSuffix(strings.ToLower(s), "m"):
+		multiplier, s = 1_000_000, s[:len(s)-1]
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(s, "_", ""))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return n * multiplier, nil
+}
+
+// parseTypeQualifier matches the package type, accepting the [F]/[C]/[P]/[M] letters too.
+func parseTypeQualifier(value string) (func(*models.Package) bool, error) {
+	var want models.PackageType
+	switch strings.ToLower(value) {
+	case "formula", "formulae", "f":
+		want = models.PackageTypeFormula
+	case "cask", "casks", "c":
+		want = models.PackageTypeCask
+	case "flatpak", "p":
+		want = models.PackageTypeFlatpak
+	case "mas", "m":
+		want = models.PackageTypeMas
+	default:
+		return nil, fmt.Errorf("expected formula, cask, flatpak or mas, got %q", value)
+	}
+	return func(p *models.Package) bool { return p.Type == want }, nil
+}
+
+// packageTap returns the tap a formula or cask comes from.
+func packageTap(p *models.Package) string {
+	switch {
+	case p.Formula != nil:
+		return p.Formula.Tap
+	case p.Cask != nil:
+		return p.Cask.Tap
+	}
+	return ""
+}
+
+// parseLicenseQualifier matches packages whose license mentions the licenses of an
+// SPDX-style expression, with * wildcards: license:MIT matches "Apache-2.0 OR MIT",
+// license:"GPL-* OR LGPL-*" any GNU license.
+func parseLicenseQualifier(value string) (func(*models.Package) bool, error) {
+	query, err := parseLicenseExpression(value)
+	if err != nil {
+		return nil, err
+	}
+	return func(p *models.Package) bool {
+		ids := packageLicenseIDs(p)
+		return len(ids) > 0 && query.matchesAny(ids)
+	}, nil
+}
+
+// matchRequiresQualifier reports whether a formula cannot be used without a license
+// matching the pattern: -requires:AGPL* hides "AGPL-3.0-only" but keeps "MIT OR AGPL-3.0-only".
+func matchRequiresQualifier(p *models.Package, value string) bool {
+	license := packageLicense(p)
+	if license == "" {
+		return false
+	}
+	if expr, err := parseLicenseExpression(license); err == nil {
+		return !expr.avoids(value)
+	}
+	return slices.ContainsFunc(packageLicenseIDs(p), func(id string) bool { return matchLicenseID(value, id) })
+}
+
+// matchDependencyQualifier reports whether the package depends on the named formula or cask.
+func matchDependencyQualifier(p *models.Package, value string) bool {
+	var deps []string
+	switch {
+	case p.Formula != nil:
+		deps = p.Formula.Dependencies
+	case p.Cask != nil:
+		deps = slices.Concat(p.Cask.DependsOn.Formula, p.Cask.DependsOn.Cask)
+	}
+	for _, dep := range deps {
+		if strings.EqualFold(dep, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether substr is within s, ignoring case.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
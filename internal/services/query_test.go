@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+// queryTestPackages returns a small catalogue covering every qualifier.
+func queryTestPackages() []models.Package {
+	openssl := &models.Formula{Name: "openssl@3", Tap: "homebrew/core", License: "Apache-2.0", KegOnly: true}
+	curl := &models.Formula{Name: "curl", Tap: "homebrew/core", License: "curl", Dependencies: []string{"openssl@3", "zstd"}, LinkedKeg: "8.5.0"}
+	rg := &models.Formula{Name: "ripgrep", Tap: "homebrew/core", License: "Unlicense OR MIT"}
+	old := &models.Formula{Name: "oldtool", Tap: "someone/tap", License: "GPL-2.0-only"}
+	firefox := &models.Cask{Token: "firefox", Tap: "homebrew/cask"}
+	wireshark := &models.Cask{Token: "wireshark-app", Tap: "homebrew/cask", DependsOn: models.CaskDependsOn{Formula: []string{"wireshark"}}}
+
+	return []models.Package{
+		{Name: "openssl@3", Description: "Cryptography and SSL/TLS Toolkit", Type: models.PackageTypeFormula, Formula: openssl, LocallyInstalled: true, Analytics90dDownloads: 900_000},
+		{Name: "curl", Description: "Get a file from an HTTP, HTTPS or FTP server", Type: models.PackageTypeFormula, Formula: curl, LocallyInstalled: true, Outdated: true, Pinned: true, InstalledOnRequest: true, Analytics90dDownloads: 500_000},
+		{Name: "ripgrep", Description: "Search tool like grep and The Silver Searcher", Type: models.PackageTypeFormula, Formula: rg, Bottle: models.BottleStatus{Platform: "arm64_sonoma", Tag: "arm64_sonoma"}, Analytics90dDownloads: 120_000},
+		{Name: "oldtool", Description: "Legacy tool", Type: models.PackageTypeFormula, Formula: old, Deprecated: true, Analytics90dDownloads: 50},
+		{Name: "firefox", DisplayName: "Mozilla Firefox", Description: "Web browser", Type: models.PackageTypeCask, Cask: firefox, Analytics90dDownloads: 300_000},
+		{Name: "wireshark-app", DisplayName: "Wireshark", Description: "Network protocol analyzer", Type: models.PackageTypeCask, Cask: wireshark, Analytics90dDownloads: 40_000},
+	}
+}
+
+func filteredNames(t *testing.T, input string) []string {
+	t.Helper()
+	query, err := parseQuery(input)
+	if err != nil {
+		t.Fatalf("parseQuery(%q) error: %v", input, err)
+	}
+	var names []string
+	for _, pkg := range query.filter(queryTestPackages()) {
+		names = append(names, pkg.Name)
+	}
+	return names
+}
+
+func TestParseQuery_Qualifiers(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"type:cask", []string{"firefox", "wireshark-app"}},
+		{"type:f installed:yes", []string{"openssl@3", "curl"}},
+		{"installed:no type:formula", []string{"ripgrep", "oldtool"}},
+		{"outdated", []string{"curl"}},
+		{"leaf:yes", []string{"curl"}},
+		{"pinned:yes", []string{"curl"}},
+		{"linked:yes", []string{"curl"}},
+		{"kegonly:yes", []string{"openssl@3"}},
+		{"bottle:yes", []string{"ripgrep"}},
+		{"deprecated", []string{"oldtool"}},
+		{"-deprecated type:formula", []string{"openssl@3", "curl", "ripgrep"}},
+		{"tap:homebrew/core", []string{"openssl@3", "curl", "ripgrep"}},
+		{"!tap:homebrew/core type:formula", []string{"oldtool"}},
+		{"license:mit", []string{"ripgrep"}},
+		{"license:Apache-2.0", []string{"openssl@3"}},
+		{`license:"GPL-* OR curl"`, []string{"curl", "oldtool"}},
+		{`license:"unlicense and mit"`, []string{"ripgrep"}},
+		{"requires:GPL*", []string{"oldtool"}},
+		{"-requires:Unlicense type:formula", []string{"openssl@3", "curl", "ripgrep", "oldtool"}},
+		{"-licensed:yes", []string{"firefox", "wireshark-app"}},
+		{"dep:openssl@3", []string{"curl"}},
+		{"dep:wireshark", []string{"wireshark-app"}},
+		{"downloads:>100000", []string{"openssl@3", "curl", "ripgrep", "firefox"}},
+		{"downloads:<=50", []string{"oldtool"}},
+		{"downloads:40k..200k", []string{"ripgrep", "wireshark-app"}},
+		{"name:fire", []string{"firefox"}},
+		{"desc:browser", []string{"firefox"}},
+		{`"silver searcher"`, []string{"ripgrep"}},
+		{`-"tool"`, []string{"curl", "firefox", "wireshark-app"}}, // "Toolkit" matches too
+		{`desc:"protocol analyzer"`, []string{"wireshark-app"}},
+	}
+
+	for _, tt := range tests {
+		if got := filteredNames(t, tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("query %q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuery_FreeTextTerms(t *testing.T) {
+	query, err := parseQuery(`Rip type:formula grep "exact phrase"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(query.terms, []string{"rip", "grep"}) {
+		t.Errorf("terms = %v, want [rip grep]", query.terms)
+	}
+	if len(query.filters) != 2 {
+		t.Errorf("expected 2 filters (type and phrase), got %d", len(query.filters))
+	}
+
+	// Only outdated and deprecated work as bare words: other state qualifiers are
+	// ordinary words and stay searchable
+	query, _ = parseQuery("web service")
+	if len(query.filters) != 0 || !reflect.DeepEqual(query.terms, []string{"web", "service"}) {
+		t.Errorf("service should be a search term, got %d filters and terms %v", len(query.filters), query.terms)
+	}
+
+	query, _ = parseQuery(`"outdated"`)
+	if len(query.filters) != 1 || len(query.terms) != 0 {
+		t.Errorf("quoted keyword should be a phrase filter, got %d filters and terms %v", len(query.filters), query.terms)
+	}
+}
+
+func TestParseQuery_UnknownKeysAreText(t *testing.T) {
+	query, err := parseQuery("foo:bar https://brew.sh")
+	if err != nil {
+		t.Fatalf("parseQuery() error = %v", err)
+	}
+	if want := []string{"foo:bar", "https://brew.sh"}; !reflect.DeepEqual(query.terms, want) {
+		t.Errorf("terms = %v, want %v", query.terms, want)
+	}
+
+	query, err = parseQuery(`-foo:bar x:"a b"`)
+	if err != nil {
+		t.Fatalf("parseQuery() error = %v", err)
+	}
+	if len(query.filters) != 2 || len(query.terms) != 0 {
+		t.Errorf("exclusions and phrases should stay literal filters, got %d filters and terms %v", len(query.filters), query.terms)
+	}
+	pkg := models.Package{Name: "tool", Description: "Talks to foo:bar servers"}
+	if query.match(&pkg) {
+		t.Error("-foo:bar should exclude a package mentioning foo:bar")
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantPos int
+		wantMsg string
+	}{
+		{"tpye:cask", 0, `unknown qualifier "tpye:" (did you mean "type:"?)`},
+		{"jq -licence:MIT", 3, `unknown qualifier "licence:" (did you mean "license:"?)`},
+		{"git type:", 4, `missing value after "type:"`},
+		{"type:bottle", 0, "type: expected formula, cask, flatpak or mas"},
+		{"installed:maybe", 0, "installed: expected yes or no"},
+		{"downloads:>lots", 0, "downloads: expected a number"},
+		{"downloads:=>5", 0, `downloads: unknown comparison "=>"`},
+		{"rank:1..x", 0, "rank: expected a range"},
+		{`desc:"unterminated`, 5, "unterminated quote"},
+		{"jq :x", 3, "missing qualifier name"},
+		{`license:"MIT OR"`, 0, "license: expression ends early"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseQuery(tt.query)
+		var qerr *queryError
+		if !errors.As(err, &qerr) {
+			t.Errorf("parseQuery(%q) error = %v, want a queryError", tt.query, err)
+			continue
+		}
+		if qerr.pos != tt.wantPos || !strings.Contains(qerr.msg, tt.wantMsg) {
+			t.Errorf("parseQuery(%q) = col %d %q, want col %d containing %q", tt.query, qerr.pos, qerr.msg, tt.wantPos, tt.wantMsg)
+		}
+	}
+}
+
+func TestParseQuery_Empty(t *testing.T) {
+	query, err := parseQuery("   ")
+	if err != nil || len(query.terms) != 0 || len(query.filters) != 0 {
+		t.Errorf("blank query = %+v, %v; want empty query", query, err)
+	}
+}
@@ -0,0 +1,457 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// SBOMFormat is a software bill of materials format.
+type SBOMFormat string
+
+const (
+	SBOMCycloneDX SBOMFormat = "cyclonedx" // CycloneDX 1.5 JSON
+	SBOMSPDX      SBOMFormat = "spdx"      // SPDX 2.3 JSON
+)
+
+// FileName returns the file an SBOM of this format is exported to in the home directory.
+func (f SBOMFormat) FileName() string {
+	if f == SBOMSPDX {
+		return "bbrew-sbom.spdx.json"
+	}
+	return "bbrew-sbom.cdx.json"
+}
+
+// ParseSBOMFormat parses a format name given on the command line.
+func ParseSBOMFormat(name string) (SBOMFormat, error) {
+	switch strings.ToLower(name) {
+	case "cyclonedx", "cdx":
+		return SBOMCycloneDX, nil
+	case "spdx":
+		return SBOMSPDX, nil
+	}
+	return "", fmt.Errorf("unknown SBOM format %q (use cyclonedx or spdx)", name)
+}
+
+// sbomComponent is an installed package, as described in an SBOM.
+type sbomComponent struct {
+	ref         string // packageKey, unique within the SBOM
+	pkgType     models.PackageType
+	id          string // Formula or cask name, Flatpak ID or App Store ID
+	name        string
+	version     string
+	description string
+	license     string
+	homepage    string
+	sourceURL   string
+	sha256      string
+	dependsOn   []string // refs of installed dependencies
+}
+
+// purl returns a package URL for the component. There is no purl type for Homebrew,
+// Flatpak or the App Store, so generic ones are namespaced by package manager.
+func (c sbomComponent) purl() string {
+	namespace := map[models.PackageType]string{
+		models.PackageTypeFormula: "homebrew",
+		models.PackageTypeCask:    "homebrew-cask",
+		models.PackageTypeFlatpak: "flatpak",
+		models.PackageTypeMas:     "mas",
+	}[c.pkgType]
+	purl := "pkg:generic/" + namespace + "/" + strings.ReplaceAll(c.id, "@", "%40")
+	if c.version != "" {
+		purl += "@" + c.version
+	}
+	return purl
+}
+
+// sbomComponents describes the installed packages, ordered by type and name.
+// Checksums and source URLs are those of the catalogue version, so they are left out
+// for outdated packages, whose installed version is older.
+func sbomComponents(packages []models.Package) []sbomComponent {
+	var components []sbomComponent
+	installed := make(map[string]bool)
+	for i := range packages {
+		if packages[i].LocallyInstalled {
+			installed[packageKey(packages[i].Type, packages[i].Name)] = true
+		}
+	}
+
+	for i := range packages {
+		pkg := &packages[i]
+		if !pkg.LocallyInstalled {
+			continue
+		}
+		c := sbomComponent{
+			ref:         packageKey(pkg.Type, pkg.Name),
+			pkgType:     pkg.Type,
+			id:          pkg.Name,
+			name:        pkg.Label(),
+			version:     pkg.Version,
+			description: pkg.Description,
+			license:     packageLicense(pkg),
+			homepage:    pkg.Homepage,
+		}
+		switch {
+		case pkg.Formula != nil:
+			f := pkg.Formula
+			if len(f.Installed) > 0 && f.Installed[0].Version != "" {
+				c.version = f.Installed[0].Version
+			}
+			if !pkg.Outdated {
+				c.sourceURL, c.sha256 = f.Urls.Stable.URL, f.Urls.Stable.Checksum
+			}
+			c.dependsOn = runtimeDependencyRefs(f, installed)
+		case pkg.Cask != nil:
+			if pkg.Cask.Installed != nil && *pkg.Cask.Installed != "" {
+				c.version = *pkg.Cask.Installed
+			}
+			if !pkg.Outdated {
+				c.sourceURL = pkg.Cask.URL
+				if pkg.Cask.SHA256 != "no_check" {
+					c.sha256 = pkg.Cask.SHA256
+				}
+			}
+			var refs []string
+			for _, dep := range pkg.Cask.DependsOn.Formula {
+				refs = append(refs, formulaKey(dep))
+			}
+			for _, dep := range pkg.Cask.DependsOn.Cask {
+				refs = append(refs, packageKey(models.PackageTypeCask, dep[strings.LastIndex(dep, "/")+1:]))
+			}
+			for _, ref := range refs {
+				if installed[ref] {
+					c.dependsOn = append(c.dependsOn, ref)
+				}
+			}
+		}
+		components = append(components, c)
+	}
+
+	order := map[models.PackageType]int{models.PackageTypeFormula: 0, models.PackageTypeCask: 1, models.PackageTypeFlatpak: 2, models.PackageTypeMas: 3}
+	sort.SliceStable(components, func(i, j int) bool {
+		if components[i].pkgType != components[j].pkgType {
+			return order[components[i].pkgType] < order[components[j].pkgType]
+		}
+		return components[i].name < components[j].name
+	})
+	return components
+}
+
+// runtimeDependencyRefs lists the installed formulae an installed formula was built
+// against, from its install receipt.
+func runtimeDependencyRefs(f *models.Formula, installed map[string]bool) []string {
+	var refs []string
+	for _, dep := range receiptDependencies(f) {
+		if ref := formulaKey(dep.FullName); installed[ref] {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// buildSBOM encodes the installed packages as an SBOM document.
+func buildSBOM(packages []models.Package, format SBOMFormat, now time.Time) ([]byte, error) {
+	components := sbomComponents(packages)
+	var doc any
+	switch format {
+	case SBOMCycloneDX:
+		doc = cycloneDXDocument(components, now)
+	case SBOMSPDX:
+		doc = spdxDocument(components, now)
+	default:
+		return nil, fmt.Errorf("unknown SBOM format %q", format)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// newUUID returns a random (version 4) UUID, used to identify each SBOM.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// CycloneDX 1.5 JSON, limited to the fields bbrew fills in.
+type cdxDocument struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cdxComponent `json:"components"`
+	} `json:"tools"`
+}
+
+type cdxComponent struct {
+	Type               string           `json:"type"`
+	BOMRef             string           `json:"bom-ref,omitempty"`
+	Name               string           `json:"name"`
+	Version            string           `json:"version,omitempty"`
+	Description        string           `json:"description,omitempty"`
+	Licenses           []cdxLicense     `json:"licenses,omitempty"`
+	Hashes             []cdxHash        `json:"hashes,omitempty"`
+	PURL               string           `json:"purl,omitempty"`
+	ExternalReferences []cdxExternalRef `json:"externalReferences,omitempty"`
+}
+
+type cdxLicense struct {
+	License    *cdxLicenseID `json:"license,omitempty"`
+	Expression string        `json:"expression,omitempty"`
+}
+
+type cdxLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxExternalRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func cycloneDXDocument(components []sbomComponent, now time.Time) cdxDocument {
+	doc := cdxDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+	doc.Metadata.Timestamp = now.UTC().Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []cdxComponent{{Type: "application", Name: "bbrew", Version: AppVersion}}
+
+	for _, c := range components {
+		component := cdxComponent{
+			Type:        "application",
+			BOMRef:      c.ref,
+			Name:        c.name,
+			Version:     c.version,
+			Description: c.description,
+			PURL:        c.purl(),
+		}
+		if c.pkgType == models.PackageTypeFormula {
+			component.Type = "library" // Formulae include libraries and command-line tools alike
+		}
+		if license := cdxLicenseOf(c.license); license != nil {
+			component.Licenses = []cdxLicense{*license}
+		}
+		if c.sha256 != "" {
+			component.Hashes = []cdxHash{{Alg: "SHA-256", Content: c.sha256}}
+		}
+		if c.homepage != "" {
+			component.ExternalReferences = append(component.ExternalReferences, cdxExternalRef{"website", c.homepage})
+		}
+		if c.sourceURL != "" {
+			component.ExternalReferences = append(component.ExternalReferences, cdxExternalRef{"distribution", c.sourceURL})
+		}
+		doc.Components = append(doc.Components, component)
+		doc.Dependencies = append(doc.Dependencies, cdxDependency{Ref: c.ref, DependsOn: append([]string{}, c.dependsOn...)})
+	}
+	return doc
+}
+
+// cdxLicenseOf describes a license as a single SPDX identifier, an SPDX expression,
+// or by name when it is not SPDX.
+func cdxLicenseOf(license string) *cdxLicense {
+	if license == "" {
+		return nil
+	}
+	expr, err := parseLicenseExpression(license)
+	switch {
+	case err != nil:
+		return &cdxLicense{License: &cdxLicenseID{Name: license}}
+	case expr.op == "" && expr.exception == "":
+		return &cdxLicense{License: &cdxLicenseID{ID: license}}
+	default:
+		return &cdxLicense{Expression: license}
+	}
+}
+
+// SPDX 2.3 JSON, limited to the fields bbrew fills in.
+type spdxDoc struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	Homepage         string            `json:"homepage,omitempty"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxNoAssertion marks a field bbrew has no information for.
+const spdxNoAssertion = "NOASSERTION"
+
+// spdxIDChars matches the characters not allowed in an SPDX identifier.
+var spdxIDChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// spdxID returns the SPDX identifier of a component, e.g. SPDXRef-formula-openssl-3.
+func spdxID(ref string) string {
+	return "SPDXRef-" + spdxIDChars.ReplaceAllString(ref, "-")
+}
+
+func spdxDocument(components []sbomComponent, now time.Time) spdxDoc {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "localhost"
+	}
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "bbrew-" + host,
+		DocumentNamespace: "https://github.com/Valkyrie00/bold-brew/spdx/" + host + "-" + newUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  now.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: bbrew-" + AppVersion},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for _, c := range components {
+		pkg := spdxPackage{
+			SPDXID:           spdxID(c.ref),
+			Name:             c.name,
+			VersionInfo:      c.version,
+			Description:      c.description,
+			DownloadLocation: spdxNoAssertion,
+			Homepage:         c.homepage,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			ExternalRefs:     []spdxExternalRef{{"PACKAGE-MANAGER", "purl", c.purl()}},
+		}
+		if c.sourceURL != "" {
+			pkg.DownloadLocation = c.sourceURL
+		}
+		if _, err := parseLicenseExpression(c.license); err == nil {
+			pkg.LicenseDeclared = c.license
+		}
+		if c.sha256 != "" {
+			pkg.Checksums = []spdxChecksum{{"SHA256", c.sha256}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", pkg.SPDXID})
+		for _, dep := range c.dependsOn {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{pkg.SPDXID, "DEPENDS_ON", spdxID(dep)})
+		}
+	}
+	return doc
+}
+
+// ExportSBOM writes an SBOM of the installed packages, including Flatpak and
+// App Store apps, to the home directory and returns its path.
+func (s *AppService) ExportSBOM(format SBOMFormat) (string, error) {
+	s.mu.RLock()
+	var packages []models.Package
+	if s.packages != nil {
+		packages = slices.Clone(*s.packages)
+	}
+	s.mu.RUnlock()
+	if len(packages) == 0 {
+		return "", fmt.Errorf("no packages loaded")
+	}
+	packages = append(packages, s.flatpakService.ListInstalledApps()...)
+	packages = append(packages, s.masService.ListInstalledApps()...)
+
+	data, err := buildSBOM(packages, format, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	outputPath := filepath.Join(home, format.FileName())
+	if err := os.WriteFile(outputPath, append(data, '\n'), 0600); err != nil {
+		return "", fmt.Errorf("failed to write SBOM: %w", err)
+	}
+	return outputPath, nil
+}
+
+// PrintSBOM writes an SBOM of the installed packages to w (bbrew sbom).
+func PrintSBOM(w io.Writer, format SBOMFormat) error {
+	d := NewDataProvider()
+	if err := d.SetupData(false); err != nil {
+		return err
+	}
+	packages := slices.Clone(*d.GetPackages())
+	packages = append(packages, NewFlatpakService().ListInstalledApps()...)
+	packages = append(packages, NewMasService().ListInstalledApps()...)
+
+	data, err := buildSBOM(packages, format, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
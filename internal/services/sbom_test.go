@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// sbomTestPackages returns installed formulae with a dependency, a cask and an App Store app.
+func sbomTestPackages() []models.Package {
+	openssl := &models.Formula{Name: "openssl@3", License: "Apache-2.0",
+		Urls:      models.Urls{Stable: models.URL{URL: "https://openssl.org/openssl-3.3.1.tar.gz", Checksum: "abc123"}},
+		Installed: []models.Installed{{Version: "3.3.1"}}}
+	curl := &models.Formula{Name: "curl", License: "curl",
+		Urls: models.Urls{Stable: models.URL{URL: "https://curl.se/curl-8.9.0.tar.bz2", Checksum: "def456"}},
+		Installed: []models.Installed{{Version: "8.8.0", RuntimeDependencies: []models.RuntimeDependency{
+			{FullName: "openssl@3", DeclaredDirectly: true},
+			{FullName: "ca-certificates"},
+			{FullName: "zstd", DeclaredDirectly: true}, // Not installed
+		}}}}
+	installed := "131.0"
+	firefox := &models.Cask{Token: "firefox", URL: "https://download.mozilla.org/firefox-131.0.dmg", SHA256: "fff000", Installed: &installed}
+
+	return []models.Package{
+		{Name: "ca-certificates", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "ca-certificates", License: "MPL-2.0"}, LocallyInstalled: true},
+		{Name: "curl", Type: models.PackageTypeFormula, Version: "8.9.0", Homepage: "https://curl.se", Formula: curl, LocallyInstalled: true, Outdated: true},
+		{Name: "firefox", Type: models.PackageTypeCask, Version: "131.0", Cask: firefox, LocallyInstalled: true},
+		{Name: "openssl@3", Type: models.PackageTypeFormula, Version: "3.3.1", Formula: openssl, LocallyInstalled: true},
+		{Name: "wget", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "wget"}},
+		{Name: "497799835", DisplayName: "Xcode", Type: models.PackageTypeMas, Version: "15.4", LocallyInstalled: true},
+	}
+}
+
+func TestSBOMComponents(t *testing.T) {
+	components := sbomComponents(sbomTestPackages())
+
+	var names []string
+	for _, c := range components {
+		names = append(names, c.name)
+	}
+	if want := []string{"ca-certificates", "curl", "openssl@3", "firefox", "Xcode"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("components = %v, want %v", names, want)
+	}
+
+	curl := components[1]
+	if curl.version != "8.8.0" || curl.sourceURL != "" || curl.sha256 != "" {
+		t.Errorf("outdated curl = %+v, want the installed version without the newer checksum", curl)
+	}
+	if want := []string{"formula:openssl@3"}; !reflect.DeepEqual(curl.dependsOn, want) {
+		t.Errorf("curl dependsOn = %v, want %v (direct, installed dependencies)", curl.dependsOn, want)
+	}
+	if openssl := components[2]; openssl.sha256 != "abc123" || openssl.purl() != "pkg:generic/homebrew/openssl%403@3.3.1" {
+		t.Errorf("openssl = %+v, purl %s", openssl, openssl.purl())
+	}
+	if firefox := components[3]; firefox.sha256 != "fff000" || firefox.sourceURL == "" {
+		t.Errorf("firefox = %+v, want its checksum and URL", firefox)
+	}
+	if xcode := components[4]; xcode.purl() != "pkg:generic/mas/497799835@15.4" {
+		t.Errorf("xcode purl = %s", xcode.purl())
+	}
+}
+
+func TestBuildSBOM_CycloneDX(t *testing.T) {
+	data, err := buildSBOM(sbomTestPackages(), SBOMCycloneDX, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("buildSBOM() error: %v", err)
+	}
+	var doc cdxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.BOMFormat != "CycloneDX" || doc.SpecVersion != "1.5" || doc.Metadata.Timestamp != "2026-10-19T09:00:00Z" {
+		t.Errorf("header = %s %s %s", doc.BOMFormat, doc.SpecVersion, doc.Metadata.Timestamp)
+	}
+	if len(doc.Components) != 5 || len(doc.Dependencies) != 5 {
+		t.Fatalf("got %d components and %d dependencies, want 5 each", len(doc.Components), len(doc.Dependencies))
+	}
+	openssl := doc.Components[2]
+	if openssl.Licenses[0].License.ID != "Apache-2.0" || openssl.Hashes[0].Content != "abc123" || openssl.ExternalReferences[0].Type != "distribution" {
+		t.Errorf("openssl component = %+v", openssl)
+	}
+	if dep := doc.Dependencies[1]; dep.Ref != "formula:curl" || !reflect.DeepEqual(dep.DependsOn, []string{"formula:openssl@3"}) {
+		t.Errorf("curl dependency = %+v", dep)
+	}
+}
+
+func TestBuildSBOM_SPDX(t *testing.T) {
+	data, err := buildSBOM(sbomTestPackages(), SBOMSPDX, time.Now())
+	if err != nil {
+		t.Fatalf("buildSBOM() error: %v", err)
+	}
+	var doc spdxDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.SPDXVersion != "SPDX-2.3" || len(doc.Packages) != 5 {
+		t.Fatalf("got %s with %d packages", doc.SPDXVersion, len(doc.Packages))
+	}
+	openssl := doc.Packages[2]
+	if openssl.SPDXID != "SPDXRef-formula-openssl-3" || openssl.LicenseDeclared != "Apache-2.0" || openssl.Checksums[0].ChecksumValue != "abc123" {
+		t.Errorf("openssl package = %+v", openssl)
+	}
+	if xcode := doc.Packages[4]; xcode.LicenseDeclared != spdxNoAssertion || xcode.DownloadLocation != spdxNoAssertion {
+		t.Errorf("xcode package = %+v, want NOASSERTION license and download location", xcode)
+	}
+	want := spdxRelationship{"SPDXRef-formula-curl", "DEPENDS_ON", "SPDXRef-formula-openssl-3"}
+	found := false
+	for _, r := range doc.Relationships {
+		found = found || r == want
+	}
+	if !found {
+		t.Errorf("relationships = %+v, want %+v", doc.Relationships, want)
+	}
+}
+
+func TestParseSBOMFormat(t *testing.T) {
+	if f, err := ParseSBOMFormat("CDX"); err != nil || f != SBOMCycloneDX {
+		t.Errorf("ParseSBOMFormat(CDX) = %q, %v", f, err)
+	}
+	if f, err := ParseSBOMFormat("spdx"); err != nil || f != SBOMSPDX {
+		t.Errorf("ParseSBOMFormat(spdx) = %q, %v", f, err)
+	}
+	if _, err := ParseSBOMFormat("swid"); err == nil {
+		t.Error("ParseSBOMFormat(swid) should fail")
+	}
+}
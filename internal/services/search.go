@@ -2,7 +2,9 @@ package services
 
 import (
 	"bbrew/internal/models"
+	"cmp"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -10,10 +12,17 @@ import (
 	"github.com/rivo/tview"
 )
 
-// search filters the packages based on the search text and the current filter state.
+// search filters the packages based on the search query and the current filter state.
+// Results are ranked by relevance unless a sort mode is active. An invalid query
+// leaves the results untouched and shows the error next to the search field.
 func (s *AppService) search(searchText string, scrollToTop bool) {
 	var filteredList []models.Package
-	uniquePackages := make(map[string]bool)
+
+	query, err := parseQuery(searchText)
+	if err != nil {
+		s.layout.GetSearch().SetError(err.Error())
+		return
+	}
 
 	// Determine the source list based on the current filter state
 	// If Brewfile mode is active, use brewfilePackages as the base source
@@ -24,49 +33,256 @@ func (s *AppService) search(searchText string, scrollToTop bool) {
 
 	// Apply active filter on the source list
 	sourceList = s.applyFilter(sourceList)
+	candidates := query.filter(*sourceList)
 
-	if searchText == "" {
-		filteredList = *sourceList
+	if len(query.terms) == 0 {
+		filteredList = candidates
+		s.searchMatches = nil
 	} else {
-		searchTextLower := strings.ToLower(searchText)
-		for _, info := range *sourceList {
-			if strings.Contains(strings.ToLower(info.Name), searchTextLower) ||
-				strings.Contains(strings.ToLower(info.DisplayName), searchTextLower) ||
-				strings.Contains(strings.ToLower(info.Description), searchTextLower) {
-				if !uniquePackages[info.Name] {
-					filteredList = append(filteredList, info)
-					uniquePackages[info.Name] = true
-				}
-			}
-		}
+		filteredList, s.searchMatches = rankPackages(candidates, query.terms)
 	}
 
-	s.applySortOrder(filteredList)
+	switch {
+	case len(query.terms) > 0 && s.primarySort() == models.SortNone:
+		// Keep the relevance ranking
+	case s.activeFilter == FilterTrending && s.primarySort() == models.SortNone:
+		s.sortByTrend(filteredList)
+	case s.activeFilter == FilterDeprecated && s.primarySort() == models.SortNone:
+		sortByDisableDate(filteredList)
+	default:
+		s.applySortOrder(filteredList)
+	}
 	*s.filteredPackages = filteredList
 	s.setResults(s.filteredPackages, scrollToTop)
 }
 
-// applySortOrder sorts the list in place based on the active sort mode.
-// When SortNone, the list preserves its natural order (API/cache order).
+// applySortOrder sorts the list in place by the active sort keys, the first key
+// deciding and the next ones breaking ties. The sort is stable, so packages equal
+// on every key keep their natural order (API/cache order), which is also kept
+// when no sort is active.
 func (s *AppService) applySortOrder(list []models.Package) {
-	switch s.activeSort {
+	if len(s.sortKeys) == 0 {
+		return
+	}
+	slices.SortStableFunc(list, func(a, b models.Package) int {
+		for _, key := range s.sortKeys {
+			c := s.comparePackages(&a, &b, key.Mode)
+			if key.Reverse {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// typeSortOrder is the order of package types for SortByType.
+var typeSortOrder = map[models.PackageType]int{
+	models.PackageTypeFormula: 0,
+	models.PackageTypeCask:    1,
+	models.PackageTypeFlatpak: 2,
+	models.PackageTypeMas:     3,
+}
+
+// comparePackages compares two packages in the natural direction of a sort mode.
+func (s *AppService) comparePackages(a, b *models.Package, mode models.SortMode) int {
+	switch mode {
 	case models.SortByDownloads:
-		sort.Slice(list, func(i, j int) bool {
-			return list[i].Analytics90dDownloads > list[j].Analytics90dDownloads
-		})
+		return cmp.Compare(b.Analytics[s.activePeriod].Count, a.Analytics[s.activePeriod].Count)
 	case models.SortByName:
-		sort.Slice(list, func(i, j int) bool {
-			return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
-		})
-	default: // SortNone - no sorting
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case models.SortBySize:
+		return cmp.Compare(b.DiskUsage.Bytes, a.DiskUsage.Bytes)
+	case models.SortByInstalledDate:
+		return cmp.Compare(b.InstalledTime(), a.InstalledTime())
+	case models.SortByOutdated:
+		return cmp.Compare(installStateOrder(a), installStateOrder(b))
+	case models.SortByType:
+		return cmp.Compare(typeSortOrder[a.Type], typeSortOrder[b.Type])
+	case models.SortByDescription:
+		return strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
+	default:
+		return 0
+	}
+}
+
+// installStateOrder ranks outdated packages first, then other installed ones.
+func installStateOrder(pkg *models.Package) int {
+	switch {
+	case pkg.LocallyInstalled && pkg.Outdated:
+		return 0
+	case pkg.LocallyInstalled:
+		return 1
+	default:
+		return 2
 	}
 }
 
-// CycleSortMode advances to the next sort mode and re-applies the search.
+// primarySort returns the mode of the first sort key, or SortNone.
+func (s *AppService) primarySort() models.SortMode {
+	if len(s.sortKeys) == 0 {
+		return models.SortNone
+	}
+	return s.sortKeys[0].Mode
+}
+
+// sortByTrend orders trending packages by how much they climbed, relative to their rank.
+func (s *AppService) sortByTrend(list []models.Package) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return s.packageTrend(list[i]).Score() > s.packageTrend(list[j]).Score()
+	})
+}
+
+// packageTrend returns the popularity trend of a package, zero when unknown.
+func (s *AppService) packageTrend(pkg models.Package) models.Trend {
+	return s.trends[packageKey(pkg.Type, pkg.Name)]
+}
+
+// LoadTrends reads the trends for the selected period from the analytics history.
+func (s *AppService) LoadTrends() error {
+	trends, since, err := s.dataProvider.GetTrends(s.trendPeriod)
+	if err != nil {
+		return err
+	}
+	s.trends, s.trendSince = trends, since
+	return nil
+}
+
+// CycleTrendPeriod switches the trending comparison between a week and a month ago,
+// re-applying the search when the Trending filter is active.
+func (s *AppService) CycleTrendPeriod() (models.TrendPeriod, error) {
+	s.trendPeriod = s.trendPeriod.Next()
+	if err := s.LoadTrends(); err != nil {
+		return s.trendPeriod, err
+	}
+	if s.activeFilter == FilterTrending {
+		s.search(s.layout.GetSearch().Field().GetText(), true)
+	}
+	return s.trendPeriod, nil
+}
+
+// maxSortKeys is how many columns a multi-key sort remembers.
+const maxSortKeys = 3
+
+// CycleSortMode advances to the next sort mode, in its natural direction, and
+// re-applies the search. It replaces any multi-key sort built by SortByColumn.
 func (s *AppService) CycleSortMode() models.SortMode {
-	s.activeSort = s.activeSort.Next()
+	next := s.primarySort().Next()
+	s.sortKeys = nil
+	if next != models.SortNone {
+		s.sortKeys = []models.SortKey{{Mode: next}}
+	}
+	s.resort()
+	return next
+}
+
+// ReverseSort flips the direction of the primary sort key.
+// It returns false when no sort is active.
+func (s *AppService) ReverseSort() (models.SortKey, bool) {
+	if len(s.sortKeys) == 0 {
+		return models.SortKey{}, false
+	}
+	s.sortKeys[0].Reverse = !s.sortKeys[0].Reverse
+	s.resort()
+	return s.sortKeys[0], true
+}
+
+// SortByColumn sorts by a table column. Sorting again by the primary column flips its
+// direction. Another column becomes the primary key and the previous keys break its
+// ties: clicking Name then Type groups packages by type, sorted by name within each.
+func (s *AppService) SortByColumn(mode models.SortMode) models.SortKey {
+	if s.primarySort() == mode {
+		s.sortKeys[0].Reverse = !s.sortKeys[0].Reverse
+	} else {
+		keys := []models.SortKey{{Mode: mode}}
+		for _, key := range s.sortKeys {
+			if key.Mode != mode && len(keys) < maxSortKeys {
+				keys = append(keys, key)
+			}
+		}
+		s.sortKeys = keys
+	}
+	s.resort()
+	return s.sortKeys[0]
+}
+
+// describeSortKey returns a label for a sort key, e.g. "Size ▲".
+func describeSortKey(key models.SortKey) string {
+	if key.Mode == models.SortNone {
+		return key.Mode.String()
+	}
+	if key.Descending() {
+		return key.Mode.String() + " ▼"
+	}
+	return key.Mode.String() + " ▲"
+}
+
+// resort re-applies the search after a sort change, keeping the selected package.
+func (s *AppService) resort() {
+	selected := s.selectedPackageName()
 	s.search(s.layout.GetSearch().Field().GetText(), false)
-	return s.activeSort
+	s.selectPackage(selected)
+}
+
+// CycleAnalyticsPeriod switches the analytics window shown in the Downloads column
+// and used by the downloads sort.
+func (s *AppService) CycleAnalyticsPeriod() models.AnalyticsPeriod {
+	s.activePeriod = s.activePeriod.Next()
+	s.layout.GetDetails().SetAnalyticsView(s.activeMetric, s.activePeriod)
+	s.search(s.layout.GetSearch().Field().GetText(), false)
+	s.showSelectedDetails()
+	return s.activePeriod
+}
+
+// CycleAnalyticsMetric switches between install, install-on-request and build-error
+// counts. The lists of the new metric are loaded in the background.
+func (s *AppService) CycleAnalyticsMetric() {
+	metric := s.activeMetric.Next()
+	s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Loading %s analytics...", metric))
+
+	go func() {
+		if err := s.dataProvider.SetAnalyticsMetric(metric); err != nil {
+			s.app.QueueUpdateDraw(func() {
+				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to load analytics: %v", err))
+			})
+			return
+		}
+
+		// The table reads the packages on the UI goroutine without the lock, so
+		// the new counts are applied there
+		s.app.QueueUpdateDraw(func() {
+			s.mu.Lock()
+			s.dataProvider.ApplyAnalytics(*s.packages)
+			s.dataProvider.ApplyAnalytics(*s.brewfilePackages)
+			s.mu.Unlock()
+
+			s.activeMetric = metric
+			s.layout.GetDetails().SetAnalyticsView(s.activeMetric, s.activePeriod)
+			selected := s.selectedPackageName()
+			s.search(s.layout.GetSearch().Field().GetText(), false)
+			s.selectPackage(selected)
+			s.showSelectedDetails()
+			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Analytics: %s", metric))
+		})
+	}()
+}
+
+// analyticsColumnTitle returns the header of the Downloads column for the selected metric and window.
+// With the Trending filter active, the column shows the rank change instead.
+func (s *AppService) analyticsColumnTitle() string {
+	if s.activeFilter == FilterTrending {
+		return fmt.Sprintf("Trend (%s)", s.trendPeriod)
+	}
+	title := "Downloads"
+	switch s.activeMetric {
+	case models.MetricInstall:
+		title = "Installs"
+	case models.MetricBuildError:
+		title = "Build Errors"
+	}
+	return fmt.Sprintf("%s %s", title, s.activePeriod)
 }
 
 // applyFilter filters packages based on the active filter type.
@@ -89,6 +305,20 @@ func (s *AppService) applyFilter(sourceList *[]models.Package) *[]models.Package
 			include = info.Type == models.PackageTypeCask
 		case FilterFormulae:
 			include = info.Type == models.PackageTypeFormula
+		case FilterTrending:
+			include = s.packageTrend(info).RankChange() > 0
+		case FilterOrphans:
+			include = s.orphans[packageKey(info.Type, info.Name)]
+		case FilterPinned:
+			include = info.LocallyInstalled && info.Pinned
+		case FilterUnlinked:
+			include = info.LocallyInstalled && info.Formula != nil && !info.Formula.Linked()
+		case FilterNoBottle:
+			include = info.Formula != nil && info.Bottle.Platform != "" && !info.Bottle.Available()
+		case FilterServices:
+			include = info.Formula != nil && info.Formula.Service != nil
+		case FilterDeprecated:
+			include = info.LocallyInstalled && (info.Deprecated || info.Disabled)
 		}
 		if include {
 			*filteredSource = append(*filteredSource, info)
@@ -97,10 +327,69 @@ func (s *AppService) applyFilter(sourceList *[]models.Package) *[]models.Package
 	return filteredSource
 }
 
+// ToggleSizeColumn shows or hides the installed Size column.
+func (s *AppService) ToggleSizeColumn() bool {
+	s.showSize = !s.showSize
+	selected := s.selectedPackageName()
+	s.search(s.layout.GetSearch().Field().GetText(), false)
+	s.selectPackage(selected)
+	return s.showSize
+}
+
+// sizeColumnVisible reports whether the Size column is shown: when toggled on,
+// or while sorting by size.
+func (s *AppService) sizeColumnVisible() bool {
+	return s.showSize || slices.ContainsFunc(s.sortKeys, func(k models.SortKey) bool { return k.Mode == models.SortBySize })
+}
+
+// ToggleLicenseColumn shows or hides the License column.
+func (s *AppService) ToggleLicenseColumn() bool {
+	s.showLicense = !s.showLicense
+	selected := s.selectedPackageName()
+	s.search(s.layout.GetSearch().Field().GetText(), false)
+	s.selectPackage(selected)
+	return s.showLicense
+}
+
+// refreshDiskUsage measures installed sizes in the Cellar and Caskroom and redraws
+// the table and header with them. A scan already in progress is not repeated.
+func (s *AppService) refreshDiskUsage() {
+	if !s.diskScanMu.TryLock() {
+		return
+	}
+	defer s.diskScanMu.Unlock()
+
+	if err := s.dataProvider.ScanDiskUsage(); err != nil {
+		return // Sizes are informational: keep showing the cached ones
+	}
+
+	// Like the analytics counts, sizes are applied on the UI goroutine, which reads
+	// the packages without the lock
+	s.app.QueueUpdateDraw(func() {
+		s.mu.Lock()
+		s.dataProvider.ApplyDiskUsage(*s.packages)
+		s.dataProvider.ApplyDiskUsage(*s.brewfilePackages)
+		s.mu.Unlock()
+
+		s.updateHeader()
+		selected := s.selectedPackageName()
+		s.search(s.layout.GetSearch().Field().GetText(), false)
+		s.selectPackage(selected)
+		s.showSelectedDetails()
+	})
+}
+
 // forceRefreshResults forces a refresh of the Homebrew formulae and cask data and updates the results in the UI.
 func (s *AppService) forceRefreshResults() {
 	// Force refresh all data to get up-to-date versions and installed status
 	_ = s.dataProvider.SetupData(true)
+	s.refreshResults()
+	s.refreshDiskUsage()
+}
+
+// refreshResults rebuilds the package lists from the DataProvider and redraws the
+// table in place, keeping the current search, filter and selected package.
+func (s *AppService) refreshResults() {
 	newPackages := s.dataProvider.GetPackages()
 
 	// If in Brewfile mode, load tap packages and verify installed status
@@ -127,37 +416,97 @@ func (s *AppService) forceRefreshResults() {
 	s.mu.Unlock()
 
 	s.app.QueueUpdateDraw(func() {
-		s.search(s.layout.GetSearch().Field().GetText(), false)
+		if s.activeFilter == FilterOrphans {
+			s.LoadOrphans() // Removals and installs change what is orphaned
+		}
+		// Scroll to top only when nothing was selected yet (e.g. first run with an empty cache)
+		selected := s.selectedPackageName()
+		s.search(s.layout.GetSearch().Field().GetText(), selected == "")
+		s.selectPackage(selected)
 	})
 }
 
+// selectedPackageName returns the name of the package on the selected table row, if any.
+func (s *AppService) selectedPackageName() string {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row > 0 && row-1 < len(*s.filteredPackages) {
+		return (*s.filteredPackages)[row-1].Name
+	}
+	return ""
+}
+
+// showSelectedDetails re-renders the details panel for the selected package.
+func (s *AppService) showSelectedDetails() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row > 0 && row-1 < len(*s.filteredPackages) {
+		pkg := &(*s.filteredPackages)[row-1]
+		if size, ok := cachedBottleSize(pkg.Name); ok && pkg.Bottle.Available() {
+			pkg.Bottle.Size = size
+		}
+		vulns, _ := s.vulnsService.GetCachedVulns(pkg.Name)
+		s.layout.GetDetails().SetContent(pkg, vulns)
+	}
+}
+
+// selectPackage moves the table selection to the named package when it is visible.
+func (s *AppService) selectPackage(name string) {
+	if name == "" {
+		return
+	}
+	for i, pkg := range *s.filteredPackages {
+		if pkg.Name == name {
+			s.layout.GetTable().View().Select(i+1, 0)
+			return
+		}
+	}
+}
+
+// sortColumns maps each table column to the sort mode a click on its header selects.
+// The Version column sorts outdated packages first, since it is where they are highlighted.
+var sortColumns = []models.SortMode{
+	models.SortByType, models.SortByName, models.SortByOutdated,
+	models.SortByDescription, models.SortByDownloads, models.SortBySize,
+}
+
+// columnSortMode returns the sort mode of a table column, if it is sortable.
+// The Size column is optional, so it is only sortable while shown.
+func (s *AppService) columnSortMode(column int) (models.SortMode, bool) {
+	if column >= len(sortColumns) || (sortColumns[column] == models.SortBySize && !s.sizeColumnVisible()) {
+		return models.SortNone, false
+	}
+	return sortColumns[column], true
+}
+
 // setResults updates the results table with the provided data and optionally scrolls to the top.
 func (s *AppService) setResults(data *[]models.Package, scrollToTop bool) {
 	s.layout.GetTable().Clear()
 
-	headers := [5]string{"Type", "Name", "Version", "Description", "Downloads"}
-	switch s.activeSort {
-	case models.SortByName:
-		headers[1] += " ▼"
-	case models.SortByDownloads:
-		headers[4] += " ▼"
+	headers := []string{"Type", "Name", "Version", "Description", s.analyticsColumnTitle()}
+	if s.sizeColumnVisible() {
+		headers = append(headers, "Size")
 	}
-	s.layout.GetTable().SetTableHeaders(headers[0], headers[1], headers[2], headers[3], headers[4])
-
-	for i, info := range *data {
-		// Type cell with escaped brackets
-		var typeTag string
-		switch info.Type {
-		case models.PackageTypeCask:
-			typeTag = tview.Escape("[C]")
-		case models.PackageTypeFlatpak:
-			typeTag = tview.Escape("[P]")
-		case models.PackageTypeMas:
-			typeTag = tview.Escape("[M]")
+	if s.showLicense {
+		headers = append(headers, "License")
+	}
+	// Mark the sorted columns: an arrow for the primary key, a dot for tie-breakers
+	for i, key := range s.sortKeys {
+		column := slices.Index(sortColumns, key.Mode)
+		if column < 0 || column >= len(headers) {
+			continue
+		}
+		switch {
+		case i > 0:
+			headers[column] += " ·"
+		case key.Descending():
+			headers[column] += " ▼"
 		default:
-			typeTag = tview.Escape("[F]")
+			headers[column] += " ▲"
 		}
-		typeCell := tview.NewTableCell(typeTag).SetSelectable(true).SetAlign(tview.AlignLeft)
+	}
+	s.layout.GetTable().SetTableHeaders(headers...)
+
+	for i, info := range *data {
+		typeCell := s.typeCell(&info)
 
 		// Version handling - truncate if too long
 		version := info.Version
@@ -167,7 +516,8 @@ func (s *AppService) setResults(data *[]models.Package, scrollToTop bool) {
 		}
 
 		// Name cell with color based on status
-		nameCell := tview.NewTableCell(info.Label()).SetSelectable(true)
+		match := s.searchMatches[info.Name]
+		nameCell := tview.NewTableCell(highlightMatches(info.Label(), match.label)).SetSelectable(true)
 		switch {
 		case info.Disabled:
 			nameCell.SetTextColor(tcell.ColorRed)
@@ -178,21 +528,34 @@ func (s *AppService) setResults(data *[]models.Package, scrollToTop bool) {
 		}
 
 		// Version cell
+		if info.LocallyInstalled && info.Pinned {
+			version = "📌 " + version
+		}
 		versionCell := tview.NewTableCell(version).SetSelectable(true)
 		if info.LocallyInstalled && info.Outdated {
 			versionCell.SetTextColor(tcell.ColorOrange)
 		}
 
 		// Description with deprecation/disabled warning prefix
-		desc := info.Description
-		if info.Disabled {
+		desc := highlightMatches(info.Description, match.description)
+		if match.via != "" {
+			desc = "[::d]matched via " + tview.Escape(match.via) + "[::-] · " + desc
+		}
+		switch {
+		case s.activeFilter == FilterDeprecated:
+			desc = tview.Escape(deprecationTag(&info)) + " " + desc
+		case info.Disabled:
 			desc = "[DISABLED] " + desc
-		} else if info.Deprecated {
+		case info.Deprecated:
 			desc = "[DEPRECATED] " + desc
 		}
 
-		// Downloads cell
-		downloadsCell := tview.NewTableCell(fmt.Sprintf("%d", info.Analytics90dDownloads)).SetSelectable(true).SetAlign(tview.AlignRight)
+		// Downloads cell, or the rank change when showing trending packages
+		downloads := fmt.Sprintf("%d", info.Analytics[s.activePeriod].Count)
+		if s.activeFilter == FilterTrending {
+			downloads = formatTrend(s.packageTrend(info))
+		}
+		downloadsCell := tview.NewTableCell(downloads).SetSelectable(true).SetAlign(tview.AlignRight)
 
 		// Set cells with new column order: Type, Name, Version, Description, Downloads
 		s.layout.GetTable().View().SetCell(i+1, 0, typeCell.SetExpansion(0))
@@ -200,6 +563,29 @@ func (s *AppService) setResults(data *[]models.Package, scrollToTop bool) {
 		s.layout.GetTable().View().SetCell(i+1, 2, versionCell.SetExpansion(0))
 		s.layout.GetTable().View().SetCell(i+1, 3, tview.NewTableCell(desc).SetSelectable(true).SetExpansion(1))
 		s.layout.GetTable().View().SetCell(i+1, 4, downloadsCell.SetExpansion(0))
+		if s.sizeColumnVisible() {
+			size := ""
+			if info.DiskUsage.Bytes > 0 {
+				size = models.FormatSize(info.DiskUsage.Bytes)
+			}
+			s.layout.GetTable().View().SetCell(i+1, 5, tview.NewTableCell(size).SetSelectable(true).SetAlign(tview.AlignRight).SetExpansion(0))
+		}
+		if s.showLicense {
+			license := packageLicense(&info)
+			const maxLicenseLen = 24
+			if len(license) > maxLicenseLen {
+				license = license[:maxLicenseLen-1] + "…"
+			}
+			if license == "" && info.Formula != nil {
+				license = licenseMissing
+			}
+			licenseCell := tview.NewTableCell(tview.Escape(license)).SetSelectable(true).SetExpansion(0)
+			// Casks carry no license data at all: only flag formulae that need a review
+			if info.Formula != nil && licenseFlag(&info) != "" {
+				licenseCell.SetTextColor(tcell.ColorOrange)
+			}
+			s.layout.GetTable().View().SetCell(i+1, len(headers)-1, licenseCell)
+		}
 	}
 
 	// Update the details view with the first item in the list
@@ -221,3 +607,71 @@ func (s *AppService) setResults(data *[]models.Package, scrollToTop bool) {
 	}
 	s.layout.GetSearch().UpdateCounter(totalCount, len(*s.filteredPackages))
 }
+
+// typeCell returns the Type cell of a package, with escaped brackets and a dot
+// when the row is marked for a batch of jobs.
+func (s *AppService) typeCell(info *models.Package) *tview.TableCell {
+	var typeTag string
+	switch info.Type {
+	case models.PackageTypeCask:
+		typeTag = tview.Escape("[C]")
+	case models.PackageTypeFlatpak:
+		typeTag = tview.Escape("[P]")
+	case models.PackageTypeMas:
+		typeTag = tview.Escape("[M]")
+	default:
+		typeTag = tview.Escape("[F]")
+	}
+	cell := tview.NewTableCell(typeTag).SetSelectable(true).SetAlign(tview.AlignLeft)
+	if s.isMarked(*info) {
+		cell.SetText("● " + typeTag).SetTextColor(tcell.ColorYellow)
+	}
+	return cell
+}
+
+// formatTrend describes a rank change for the table, e.g. "▲ 120" or "new".
+func formatTrend(trend models.Trend) string {
+	switch change := trend.RankChange(); {
+	case trend.NewRank > 0 && trend.OldRank == 0:
+		return "new"
+	case change > 0:
+		return fmt.Sprintf("▲ %d", change)
+	case change < 0:
+		return fmt.Sprintf("▼ %d", -change)
+	default:
+		return "-"
+	}
+}
+
+// highlightMatches escapes text for a table cell and underlines the characters at the
+// given byte positions (as reported by fuzzyMatch).
+func highlightMatches(text string, positions []int) string {
+	if len(positions) == 0 {
+		return tview.Escape(text)
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	start, inMatch := 0, false
+	for i := range text { // Rune boundaries, so multi-byte characters are never split
+		if matched[i] == inMatch {
+			continue
+		}
+		b.WriteString(tview.Escape(text[start:i]))
+		if inMatch {
+			b.WriteString("[::-]")
+		} else {
+			b.WriteString("[::bu]")
+		}
+		start, inMatch = i, !inMatch
+	}
+	b.WriteString(tview.Escape(text[start:]))
+	if inMatch {
+		b.WriteString("[::-]")
+	}
+	return b.String()
+}
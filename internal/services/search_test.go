@@ -0,0 +1,56 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func sortTestPackages() []models.Package {
+	at := func(t int64) []models.Installed { return []models.Installed{{Time: t}} }
+	return []models.Package{
+		{Name: "wget", Description: "Internet file retriever", Type: models.PackageTypeFormula, LocallyInstalled: true,
+			Formula: &models.Formula{Name: "wget", Installed: at(300)}, DiskUsage: models.DiskUsage{Bytes: 4_000}},
+		{Name: "firefox", Description: "Web browser", Type: models.PackageTypeCask, Cask: &models.Cask{Token: "firefox"}},
+		{Name: "curl", Description: "Get a file from an HTTP, HTTPS or FTP server", Type: models.PackageTypeFormula, LocallyInstalled: true, Outdated: true,
+			Formula: &models.Formula{Name: "curl", Installed: at(100)}, DiskUsage: models.DiskUsage{Bytes: 9_000}},
+		{Name: "Zed", Description: "Code editor", Type: models.PackageTypeCask, Cask: &models.Cask{Token: "zed"}},
+		{Name: "jq", Description: "JSON processor", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "jq"}},
+	}
+}
+
+func sortedNames(keys ...models.SortKey) []string {
+	s := &AppService{sortKeys: keys}
+	list := sortTestPackages()
+	s.applySortOrder(list)
+	names := make([]string, len(list))
+	for i, pkg := range list {
+		names[i] = pkg.Name
+	}
+	return names
+}
+
+func TestApplySortOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []models.SortKey
+		want []string
+	}{
+		{"none keeps natural order", nil, []string{"wget", "firefox", "curl", "Zed", "jq"}},
+		{"name", []models.SortKey{{Mode: models.SortByName}}, []string{"curl", "firefox", "jq", "wget", "Zed"}},
+		{"name reversed", []models.SortKey{{Mode: models.SortByName, Reverse: true}}, []string{"Zed", "wget", "jq", "firefox", "curl"}},
+		{"size", []models.SortKey{{Mode: models.SortBySize}}, []string{"curl", "wget", "firefox", "Zed", "jq"}},
+		{"installed date", []models.SortKey{{Mode: models.SortByInstalledDate}}, []string{"wget", "curl", "firefox", "Zed", "jq"}},
+		{"outdated", []models.SortKey{{Mode: models.SortByOutdated}}, []string{"curl", "wget", "firefox", "Zed", "jq"}},
+		{"description", []models.SortKey{{Mode: models.SortByDescription}}, []string{"Zed", "curl", "wget", "jq", "firefox"}},
+		{"type then name", []models.SortKey{{Mode: models.SortByType}, {Mode: models.SortByName}}, []string{"curl", "jq", "wget", "firefox", "Zed"}},
+		{"type reversed then name", []models.SortKey{{Mode: models.SortByType, Reverse: true}, {Mode: models.SortByName}}, []string{"firefox", "Zed", "curl", "jq", "wget"}},
+	}
+
+	for _, tt := range tests {
+		if got := sortedNames(tt.keys...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"bbrew/internal/models"
+)
+
+// pluralY returns the ending of a word like "dependency" for a count: "y" or "ies".
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// pluralS returns the plural "s" of a count, empty for one.
+func pluralS(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// pluralAE returns the ending of "formula" for a count: "a" or "ae".
+func pluralAE(n int) string {
+	if n == 1 {
+		return "a"
+	}
+	return "ae"
+}
+
+// packageNames lists package names for a message, up to limit of them.
+func packageNames(packages []models.Package, limit int) string {
+	names := make([]string, 0, limit)
+	for i, pkg := range packages {
+		if i == limit {
+			return fmt.Sprintf("%s and %d more", strings.Join(names, ", "), len(packages)-limit)
+		}
+		names = append(names, pkg.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// commandLine formats a brew command with its flags for display.
+func commandLine(command string, flags []string, name string) string {
+	line := "brew " + command
+	for _, flag := range flags {
+		line += " " + flag
+	}
+	return line + " " + name
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bbrew/internal/models"
+)
+
+// Analytics history: one compact, gzipped snapshot of each popularity list per day,
+// named <category>-<end date>.json.gz, so trends can be computed between any two days.
+const (
+	analyticsHistoryDir       = "analytics-history"
+	analyticsHistoryRetention = 45 * 24 * time.Hour // Longer than the longest trend period
+	analyticsDateLayout       = "2006-01-02"
+)
+
+// errNoTrendHistory is returned until at least two daily snapshots have been kept.
+var errNoTrendHistory = errors.New("not enough analytics history yet: trends need snapshots from two different days")
+
+// analyticsSnapshot maps each package to its [rank, count] on the snapshot date.
+type analyticsSnapshot map[string][2]int
+
+// historyFile returns the path of a snapshot of a list category on a date.
+func historyFile(category, date string) string {
+	return filepath.Join(getCacheDir(), analyticsHistoryDir, fmt.Sprintf("%s-%s.json.gz", category, date))
+}
+
+// recordAnalyticsSnapshot keeps a compact copy of an analytics list, once per data date,
+// and removes snapshots older than the retention period.
+func recordAnalyticsSnapshot(category, date string, items map[string]models.AnalyticsItem) error {
+	if _, err := time.Parse(analyticsDateLayout, date); err != nil {
+		return fmt.Errorf("invalid analytics end date %q", date)
+	}
+	path := historyFile(category, date)
+	if _, err := os.Stat(path); err == nil {
+		return nil // Already recorded
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	snapshot := make(analyticsSnapshot, len(items))
+	for name := range items {
+		c := analyticsCount(items, name)
+		snapshot[name] = [2]int{c.Rank, c.Count}
+	}
+
+	tmp := path + ".tmp"
+	// #nosec G304 -- path is safely constructed from getCacheDir
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(file)
+	err = json.NewEncoder(zw).Encode(snapshot)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	pruneAnalyticsHistory(category, date)
+	return nil
+}
+
+// pruneAnalyticsHistory removes snapshots of a category older than the retention period.
+func pruneAnalyticsHistory(category, latest string) {
+	latestDate, _ := time.Parse(analyticsDateLayout, latest)
+	for _, date := range snapshotDates(category) {
+		if d, _ := time.Parse(analyticsDateLayout, date); latestDate.Sub(d) > analyticsHistoryRetention {
+			_ = os.Remove(historyFile(category, date))
+		}
+	}
+}
+
+// snapshotDates returns the dates of the kept snapshots of a category, oldest first.
+func snapshotDates(category string) []string {
+	entries, err := os.ReadDir(filepath.Join(getCacheDir(), analyticsHistoryDir))
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	prefix := category + "-"
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".json.gz") {
+			continue
+		}
+		date := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json.gz")
+		if _, err := time.Parse(analyticsDateLayout, date); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+// loadAnalyticsSnapshot reads a kept snapshot.
+func loadAnalyticsSnapshot(category, date string) (analyticsSnapshot, error) {
+	// #nosec G304 -- path is safely constructed from getCacheDir
+	file, err := os.Open(historyFile(category, date))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var snapshot analyticsSnapshot
+	if err := json.NewDecoder(zr).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// baselineDate picks the snapshot to compare the latest one with: the newest snapshot
+// at least days older than the latest, or the oldest one while history is shorter.
+func baselineDate(dates []string, days int) (baseline, latest string, ok bool) {
+	if len(dates) < 2 {
+		return "", "", false
+	}
+	latest = dates[len(dates)-1]
+	latestDate, _ := time.Parse(analyticsDateLayout, latest)
+	target := latestDate.AddDate(0, 0, -days).Format(analyticsDateLayout)
+
+	baseline = dates[0]
+	for _, date := range dates[:len(dates)-1] {
+		if date <= target {
+			baseline = date
+		}
+	}
+	return baseline, latest, true
+}
+
+// GetTrends compares the latest formula and cask popularity snapshots with those from
+// about a period earlier. Trends are keyed by packageKey. It also returns the date of the
+// earlier snapshot, which is more recent than a full period while history is building up.
+func (d *DataProvider) GetTrends(period models.TrendPeriod) (map[string]models.Trend, string, error) {
+	trends := make(map[string]models.Trend)
+	since := ""
+
+	lists := []struct {
+		category string
+		pkgType  models.PackageType
+	}{
+		{popularityFormulaList.category, models.PackageTypeFormula},
+		{popularityCaskList.category, models.PackageTypeCask},
+	}
+	for _, list := range lists {
+		baseline, latest, ok := baselineDate(snapshotDates(list.category), period.Days())
+		if !ok {
+			continue
+		}
+		before, err := loadAnalyticsSnapshot(list.category, baseline)
+		if err != nil {
+			return nil, "", err
+		}
+		after, err := loadAnalyticsSnapshot(list.category, latest)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for name, now := range after {
+			then := before[name]
+			trends[packageKey(list.pkgType, name)] = models.Trend{
+				OldRank: then[0], NewRank: now[0],
+				OldCount: then[1], NewCount: now[1],
+				OldListSize: len(before),
+			}
+		}
+		if since == "" || baseline < since {
+			since = baseline
+		}
+	}
+
+	if len(trends) == 0 {
+		return nil, "", errNoTrendHistory
+	}
+	return trends, since, nil
+}
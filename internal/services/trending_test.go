@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/adrg/xdg"
+
+	"bbrew/internal/models"
+)
+
+// useTempCacheDir points the cache directory at a temporary directory for one test.
+func useTempCacheDir(t *testing.T) {
+	t.Helper()
+	previous := xdg.CacheHome
+	xdg.CacheHome = t.TempDir()
+	t.Cleanup(func() { xdg.CacheHome = previous })
+}
+
+func analyticsItems(ranks map[string]int) map[string]models.AnalyticsItem {
+	items := make(map[string]models.AnalyticsItem, len(ranks))
+	for name, rank := range ranks {
+		items[name] = models.AnalyticsItem{Number: rank, Formula: name, Count: "1,000"}
+	}
+	return items
+}
+
+func TestBaselineDate(t *testing.T) {
+	dates := []string{"2026-09-01", "2026-10-01", "2026-10-10", "2026-10-12", "2026-10-18"}
+	tests := []struct {
+		days int
+		want string
+	}{
+		{7, "2026-10-10"},
+		{30, "2026-09-01"},
+		{1, "2026-10-12"},
+	}
+	for _, tt := range tests {
+		baseline, latest, ok := baselineDate(dates, tt.days)
+		if !ok || baseline != tt.want || latest != "2026-10-18" {
+			t.Errorf("baselineDate(%d days) = %q, %q, %v; want %q", tt.days, baseline, latest, ok, tt.want)
+		}
+	}
+
+	// Shorter history than the period falls back to the oldest snapshot
+	if baseline, _, ok := baselineDate([]string{"2026-10-16", "2026-10-18"}, 30); !ok || baseline != "2026-10-16" {
+		t.Errorf("short history baseline = %q, %v; want 2026-10-16", baseline, ok)
+	}
+	if _, _, ok := baselineDate([]string{"2026-10-18"}, 7); ok {
+		t.Error("a single snapshot should not give a baseline")
+	}
+}
+
+func TestGetTrends(t *testing.T) {
+	useTempCacheDir(t)
+	category := popularityFormulaList.category
+	d := &DataProvider{}
+
+	if err := recordAnalyticsSnapshot(category, "2026-10-10", analyticsItems(map[string]int{"wget": 1, "curl": 2, "jq": 3})); err != nil {
+		t.Fatalf("recordAnalyticsSnapshot() error: %v", err)
+	}
+	if _, _, err := d.GetTrends(models.TrendWeek); !errors.Is(err, errNoTrendHistory) {
+		t.Fatalf("GetTrends() with one snapshot error = %v, want errNoTrendHistory", err)
+	}
+
+	if err := recordAnalyticsSnapshot(category, "2026-10-18", analyticsItems(map[string]int{"jq": 1, "wget": 2, "curl": 3, "uv": 4})); err != nil {
+		t.Fatalf("recordAnalyticsSnapshot() error: %v", err)
+	}
+	trends, since, err := d.GetTrends(models.TrendWeek)
+	if err != nil {
+		t.Fatalf("GetTrends() error: %v", err)
+	}
+	if since != "2026-10-10" {
+		t.Errorf("since = %q, want 2026-10-10", since)
+	}
+
+	want := map[string]int{"jq": 2, "wget": -1, "curl": -1, "uv": 0}
+	for name, change := range want {
+		trend, ok := trends[packageKey(models.PackageTypeFormula, name)]
+		if !ok {
+			t.Errorf("missing trend for %s", name)
+			continue
+		}
+		if got := trend.RankChange(); got != change {
+			t.Errorf("%s rank change = %d, want %d", name, got, change)
+		}
+	}
+}
+
+func TestRecordAnalyticsSnapshot_Prunes(t *testing.T) {
+	useTempCacheDir(t)
+	category := popularityCaskList.category
+	items := analyticsItems(map[string]int{"firefox": 1})
+
+	for _, date := range []string{"2026-08-01", "2026-10-01", "2026-10-18"} {
+		if err := recordAnalyticsSnapshot(category, date, items); err != nil {
+			t.Fatalf("recordAnalyticsSnapshot(%s) error: %v", date, err)
+		}
+	}
+	if got := snapshotDates(category); len(got) != 2 || got[0] != "2026-10-01" {
+		t.Errorf("snapshot dates = %v, want [2026-10-01 2026-10-18]", got)
+	}
+	if _, err := os.Stat(historyFile(category, "2026-08-01")); !os.IsNotExist(err) {
+		t.Error("expected the old snapshot to be removed")
+	}
+
+	if err := recordAnalyticsSnapshot(category, "not-a-date", items); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
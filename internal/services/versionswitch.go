@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"io"
+
+	"bbrew/internal/models"
+)
+
+// planVersionSwitch lists the brew commands of a version switch: unlink the old version,
+// install the target if needed, link it (forced when keg-only), then optionally move
+// the service over and uninstall the old version. The old version is unlinked before
+// the install, whose own link step would otherwise clash with it, and relinked when
+// the install fails.
+func planVersionSwitch(brew BrewServiceInterface, sw models.VersionSwitch, opts models.InstallOptions) []brewStep {
+	from, to := sw.From, sw.To
+	var steps []brewStep
+
+	unlinked := from.Formula != nil && from.Formula.Linked()
+	if unlinked {
+		steps = append(steps, brewStep{
+			description: "brew unlink " + from.Name,
+			run:         func(output io.Writer) error { return brew.UnlinkPackage(from, output) },
+		})
+	}
+	if !to.LocallyInstalled {
+		steps = append(steps, brewStep{
+			description: commandLine("install", opts.Flags(to.Type), to.Name),
+			run: func(output io.Writer) error {
+				err := brew.InstallPackage(to, opts, output)
+				if err != nil && unlinked {
+					_, _ = fmt.Fprintf(output, "==> brew link %s\n", from.Name)
+					_ = brew.LinkPackage(from, from.Formula.KegOnly, output)
+				}
+				return err
+			},
+		})
+	}
+	if to.Formula == nil || !to.Formula.Linked() {
+		force := to.Formula != nil && to.Formula.KegOnly
+		var flags []string
+		if force {
+			flags = []string{"--force"}
+		}
+		steps = append(steps, brewStep{
+			description: commandLine("link", flags, to.Name),
+			run:         func(output io.Writer) error { return brew.LinkPackage(to, force, output) },
+		})
+	}
+	if sw.MigrateService {
+		steps = append(steps,
+			brewStep{
+				description: "brew services stop " + from.Name,
+				run:         func(output io.Writer) error { return brew.ServiceAction("stop", from.Name, output) },
+			},
+			brewStep{
+				description: "brew services start " + to.Name,
+				run:         func(output io.Writer) error { return brew.ServiceAction("start", to.Name, output) },
+			})
+	}
+	if sw.RemoveOld {
+		steps = append(steps, brewStep{
+			description: "brew uninstall " + from.Name,
+			run:         func(output io.Writer) error { return brew.RemovePackage(from, output) },
+		})
+	}
+	return steps
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func stepDescriptions(steps []brewStep) []string {
+	descriptions := make([]string, len(steps))
+	for i, step := range steps {
+		descriptions[i] = step.description
+	}
+	return descriptions
+}
+
+func TestPlanVersionSwitch(t *testing.T) {
+	pg15 := models.Package{
+		Name: "postgresql@15", Type: models.PackageTypeFormula, LocallyInstalled: true,
+		Formula: &models.Formula{Name: "postgresql@15", KegOnly: true, LinkedKeg: "15.8"},
+	}
+	pg16 := models.Package{
+		Name: "postgresql@16", Type: models.PackageTypeFormula,
+		Formula: &models.Formula{Name: "postgresql@16", KegOnly: true},
+	}
+
+	sw := models.VersionSwitch{From: pg15, To: pg16, MigrateService: true, RemoveOld: true}
+	got := stepDescriptions(planVersionSwitch(nil, sw, models.InstallOptions{BuildFromSource: true}))
+	want := []string{
+		"brew unlink postgresql@15",
+		"brew install --build-from-source postgresql@16",
+		"brew link --force postgresql@16",
+		"brew services stop postgresql@15",
+		"brew services start postgresql@16",
+		"brew uninstall postgresql@15",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planVersionSwitch() = %v, want %v", got, want)
+	}
+
+	// An installed, unlinked target is only relinked
+	py311 := models.Package{Name: "python@3.11", Type: models.PackageTypeFormula, LocallyInstalled: true, Formula: &models.Formula{Name: "python@3.11"}}
+	py312 := models.Package{Name: "python@3.12", Type: models.PackageTypeFormula, LocallyInstalled: true, Formula: &models.Formula{Name: "python@3.12"}}
+	got = stepDescriptions(planVersionSwitch(nil, models.VersionSwitch{From: py311, To: py312}, models.InstallOptions{}))
+	if want := []string{"brew link python@3.12"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("planVersionSwitch() = %v, want %v", got, want)
+	}
+}
+
+// recordingBrew records the brew commands it is asked to run; the install fails when failInstall is set.
+type recordingBrew struct {
+	BrewServiceInterface
+	calls       []string
+	failInstall bool
+}
+
+func (b *recordingBrew) InstallPackage(info models.Package, _ models.InstallOptions, _ io.Writer) error {
+	b.calls = append(b.calls, "install "+info.Name)
+	if b.failInstall {
+		return errors.New("exit status 1")
+	}
+	return nil
+}
+
+func (b *recordingBrew) LinkPackage(info models.Package, _ bool, _ io.Writer) error {
+	b.calls = append(b.calls, "link "+info.Name)
+	return nil
+}
+
+func (b *recordingBrew) RemovePackages(packages []models.Package, _ bool, _ io.Writer) error {
+	for _, pkg := range packages {
+		b.calls = append(b.calls, "uninstall "+pkg.Name)
+	}
+	return nil
+}
+
+func (b *recordingBrew) UnlinkPackage(info models.Package, _ io.Writer) error {
+	b.calls = append(b.calls, "unlink "+info.Name)
+	return nil
+}
+
+func TestPlanVersionSwitch_UnlinksBeforeInstallingLinkedTarget(t *testing.T) {
+	// node@20 is not keg-only: its install links it, which clashes with a linked node@18
+	node18 := models.Package{
+		Name: "node@18", Type: models.PackageTypeFormula, LocallyInstalled: true,
+		Formula: &models.Formula{Name: "node@18", LinkedKeg: "18.20.4"},
+	}
+	node20 := models.Package{Name: "node@20", Type: models.PackageTypeFormula, Formula: &models.Formula{Name: "node@20"}}
+	sw := models.VersionSwitch{From: node18, To: node20}
+
+	got := stepDescriptions(planVersionSwitch(nil, sw, models.InstallOptions{}))
+	want := []string{"brew unlink node@18", "brew install node@20", "brew link node@20"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planVersionSwitch() = %v, want %v", got, want)
+	}
+
+	brew := &recordingBrew{}
+	if err := runBrewSteps(planVersionSwitch(brew, sw, models.InstallOptions{}), io.Discard); err != nil {
+		t.Fatalf("runBrewSteps() error = %v", err)
+	}
+	if want := []string{"unlink node@18", "install node@20", "link node@20"}; !reflect.DeepEqual(brew.calls, want) {
+		t.Errorf("calls = %v, want %v", brew.calls, want)
+	}
+
+	// A failed install relinks the old version and stops the switch
+	brew = &recordingBrew{failInstall: true}
+	if err := runBrewSteps(planVersionSwitch(brew, sw, models.InstallOptions{}), io.Discard); err == nil {
+		t.Fatal("runBrewSteps() should fail when the install fails")
+	}
+	if want := []string{"unlink node@18", "install node@20", "link node@18"}; !reflect.DeepEqual(brew.calls, want) {
+		t.Errorf("calls = %v, want %v", brew.calls, want)
+	}
+}
+
+func TestRunBrewSteps_StopsAtFirstFailure(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) brewStep {
+		return brewStep{description: name, run: func(io.Writer) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+	steps := []brewStep{step("brew unlink a", nil), step("brew link b", errors.New("exit status 1")), step("brew uninstall a", nil)}
+
+	var output strings.Builder
+	err := runBrewSteps(steps, &output)
+	if err == nil || !strings.Contains(err.Error(), "brew link b") {
+		t.Fatalf("runBrewSteps() error = %v, want the failed step named", err)
+	}
+	if want := []string{"brew unlink a", "brew link b"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if !strings.Contains(output.String(), "==> brew unlink a\n") {
+		t.Errorf("output should echo each command, got %q", output.String())
+	}
+}
@@ -115,10 +115,3 @@ func (s *VulnsService) GetCachedVulns(name string) ([]models.Vulnerability, bool
 	vulns, ok := s.cache[name]
 	return vulns, ok
 }
-
-func pluralY(n int) string {
-	if n == 1 {
-		return "y"
-	}
-	return "ies"
-}
@@ -0,0 +1,79 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// CaveatsScreen displays an overlay with the caveats of one or more packages,
+// which stays open until dismissed
+type CaveatsScreen struct {
+	pages    *tview.Pages
+	textView *tview.TextView
+	theme    *theme.Theme
+}
+
+// NewCaveatsScreen creates a new caveats screen component
+func NewCaveatsScreen(theme *theme.Theme) *CaveatsScreen {
+	return &CaveatsScreen{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the text view holding the caveats, which handles scrolling
+func (c *CaveatsScreen) View() *tview.TextView {
+	return c.textView
+}
+
+// Build creates the caveats screen as an overlay on top of the main content.
+// The title says what just happened, e.g. "Installed wget".
+func (c *CaveatsScreen) Build(mainContent tview.Primitive, title string, packages []models.Package) *tview.Pages {
+	c.textView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWordWrap(true).
+		SetText(c.buildCaveatsContent(packages))
+
+	c.textView.SetBackgroundColor(c.theme.ModalBgColor)
+	c.textView.SetTextColor(c.theme.DefaultTextColor)
+
+	frame := tview.NewFrame(c.textView).
+		SetBorders(1, 1, 1, 1, 2, 2).
+		AddText("↑/↓ scroll · Esc close", false, tview.AlignCenter, c.theme.LegendColor)
+	frame.SetBackgroundColor(c.theme.ModalBgColor)
+	frame.SetBorderColor(c.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s: Caveats ", tview.Escape(title))).
+		SetTitleAlign(tview.AlignCenter)
+
+	// Leave a margin around the box so the main view stays visible behind it
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 6, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	c.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("caveats", centered, true, true)
+
+	return c.pages
+}
+
+// buildCaveatsContent lists the caveats of each package under its name
+func (c *CaveatsScreen) buildCaveatsContent(packages []models.Package) string {
+	sections := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		sections = append(sections, fmt.Sprintf("[#%06x::b]%s %s[-::-]\n%s",
+			c.theme.WarningColor.Hex(), typeTag(pkg.Type), tview.Escape(pkg.Label()), tview.Escape(pkg.Caveats)))
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,135 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// DependencyTree displays an overlay with the dependency tree of a package and
+// the installed packages that depend on it
+type DependencyTree struct {
+	pages    *tview.Pages
+	treeView *tview.TreeView
+	theme    *theme.Theme
+}
+
+// NewDependencyTree creates a new dependency tree component
+func NewDependencyTree(theme *theme.Theme) *DependencyTree {
+	return &DependencyTree{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the tree view, which handles navigation
+func (d *DependencyTree) View() *tview.TreeView {
+	return d.treeView
+}
+
+// Build creates the dependency tree as an overlay on top of the main content.
+// onSelect is called with the package chosen with Enter.
+func (d *DependencyTree) Build(mainContent tview.Primitive, pkg *models.Package, deps, usedBy *models.DependencyNode, onSelect func(pkgType models.PackageType, name string)) *tview.Pages {
+	root := tview.NewTreeNode(fmt.Sprintf("%s %s", typeTag(pkg.Type), tview.Escape(pkg.Name))).
+		SetColor(d.theme.TitleColor).
+		SetSelectable(false)
+	root.AddChild(d.branch(fmt.Sprintf("Dependencies (%d)", deps.Count()), deps, "No runtime dependencies"))
+	root.AddChild(d.branch(fmt.Sprintf("Used by (%d installed)", usedBy.Count()), usedBy, "Not required by any installed package"))
+
+	d.treeView = tview.NewTreeView().
+		SetRoot(root).
+		SetCurrentNode(root.GetChildren()[0]).
+		SetGraphicsColor(d.theme.BorderColor)
+	d.treeView.SetBackgroundColor(d.theme.ModalBgColor)
+	d.treeView.SetSelectedFunc(func(node *tview.TreeNode) {
+		if dep, ok := node.GetReference().(*models.DependencyNode); ok {
+			onSelect(dep.Type, dep.Name)
+			return
+		}
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	frame := tview.NewFrame(d.treeView).
+		SetBorders(1, 1, 1, 1, 2, 2).
+		AddText("↑/↓ move · ←/→ collapse/expand · Enter go to package · Esc close", false, tview.AlignCenter, d.theme.LegendColor)
+	frame.SetBackgroundColor(d.theme.ModalBgColor)
+	frame.SetBorderColor(d.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" Dependency Tree ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Leave a margin around the box so the main view stays visible behind it
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 8, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	d.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("deptree", centered, true, true)
+
+	return d.pages
+}
+
+// ExpandCurrent expands or collapses the selected node
+func (d *DependencyTree) ExpandCurrent(expanded bool) {
+	if node := d.treeView.GetCurrentNode(); node != nil {
+		node.SetExpanded(expanded)
+	}
+}
+
+// branch creates a top-level section of the tree. Only its first level is
+// expanded, so large trees open compactly.
+func (d *DependencyTree) branch(title string, tree *models.DependencyNode, empty string) *tview.TreeNode {
+	node := tview.NewTreeNode(title).SetColor(d.theme.WarningColor)
+	if len(tree.Children) == 0 {
+		return node.AddChild(tview.NewTreeNode(empty).SetColor(d.theme.LegendColor).SetSelectable(false))
+	}
+	for _, child := range tree.Children {
+		node.AddChild(d.packageNode(child))
+	}
+	return node
+}
+
+// packageNode creates the tree node of a package and, collapsed, its subtree
+func (d *DependencyTree) packageNode(dep *models.DependencyNode) *tview.TreeNode {
+	text := fmt.Sprintf("%s %s", typeTag(dep.Type), tview.Escape(dep.Name))
+	color := d.theme.DefaultTextColor
+	switch {
+	case dep.Missing:
+		text += " (not in catalogue)"
+		color = d.theme.LegendColor
+	case dep.Installed:
+		text += " ✓"
+		color = d.theme.SuccessColor
+	}
+	if dep.Repeated {
+		text += " …" // Expanded where it first appears
+	}
+	if len(dep.Children) > 0 {
+		text += fmt.Sprintf(" (%d)", len(dep.Children))
+	}
+
+	node := tview.NewTreeNode(text).
+		SetReference(dep).
+		SetColor(color).
+		SetExpanded(false)
+	for _, child := range dep.Children {
+		node.AddChild(d.packageNode(child))
+	}
+	return node
+}
+
+// typeTag returns the short label of a package type used in overlays
+func typeTag(pkgType models.PackageType) string {
+	if pkgType == models.PackageTypeCask {
+		return tview.Escape("[C]")
+	}
+	return tview.Escape("[F]")
+}
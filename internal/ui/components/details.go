@@ -15,12 +15,17 @@ import (
 type Details struct {
 	view  *tview.TextView
 	theme *theme.Theme
+
+	// Analytics metric and window selected in the table
+	metric models.AnalyticsMetric
+	period models.AnalyticsPeriod
 }
 
 func NewDetails(theme *theme.Theme) *Details {
 	details := &Details{
-		view:  tview.NewTextView(),
-		theme: theme,
+		view:   tview.NewTextView(),
+		theme:  theme,
+		period: models.Analytics90d,
 	}
 
 	details.view.SetDynamicColors(true)
@@ -42,6 +47,12 @@ func NewDetails(theme *theme.Theme) *Details {
 	return details
 }
 
+// SetAnalyticsView sets the analytics metric shown and the window to highlight.
+func (d *Details) SetAnalyticsView(metric models.AnalyticsMetric, period models.AnalyticsPeriod) {
+	d.metric = metric
+	d.period = period
+}
+
 func (d *Details) SetContent(pkg *models.Package, vulns []models.Vulnerability) {
 	if pkg == nil {
 		d.view.SetText("")
@@ -55,6 +66,9 @@ func (d *Details) SetContent(pkg *models.Package, vulns []models.Vulnerability)
 		if pkg.Outdated {
 			installedStatus = "[orange]Update available[-]"
 		}
+		if pkg.Pinned {
+			installedStatus += " [blue]📌 Pinned[-]"
+		}
 	}
 
 	// Health warning inline (shown next to status)
@@ -96,6 +110,8 @@ func (d *Details) SetContent(pkg *models.Package, vulns []models.Vulnerability)
 			"[blue]• %s:[-] %s\n"+
 			"[blue]• Display Name:[-] %s\n"+
 			"[blue]• Version:[-] %s\n"+
+			"[blue]• License:[-] %s\n"+
+			"%s"+
 			"[blue]• Status:[-] %s%s\n"+
 			"[blue]• Homepage:[-] %s\n\n"+
 			"[yellow::b]Description[-]\n%s\n%s",
@@ -104,6 +120,8 @@ func (d *Details) SetContent(pkg *models.Package, vulns []models.Vulnerability)
 		nameLabel, pkg.Name,
 		pkg.DisplayName,
 		pkg.Version,
+		d.getLicense(pkg),
+		d.getBottleLine(pkg),
 		installedStatus, healthInline,
 		pkg.Homepage,
 		separator,
@@ -133,6 +151,12 @@ func (d *Details) SetContent(pkg *models.Package, vulns []models.Vulnerability)
 		parts = append(parts, vulnInfo)
 	}
 	parts = append(parts, installDetails)
+	if pkg.Caveats != "" {
+		parts = append(parts, d.getCaveatsInfo(pkg))
+	}
+	if pkg.DiskUsage.Bytes > 0 {
+		parts = append(parts, d.getDiskUsageInfo(pkg.DiskUsage))
+	}
 	if dependenciesInfo != "" {
 		parts = append(parts, dependenciesInfo)
 	}
@@ -141,71 +165,90 @@ func (d *Details) SetContent(pkg *models.Package, vulns []models.Vulnerability)
 	d.view.SetText(strings.Join(parts, "\n\n"))
 }
 
+// getLicense returns the license a formula declares. Only formulae carry one in the
+// Homebrew API, so it is unknown for other packages.
+func (d *Details) getLicense(pkg *models.Package) string {
+	switch {
+	case pkg.Formula == nil:
+		return "[dim]Unknown[-]"
+	case pkg.Formula.License == "":
+		return "[orange]Not declared[-]"
+	default:
+		return tview.Escape(pkg.Formula.License)
+	}
+}
+
+// getBottleLine tells whether a formula has a bottle for the running platform, or
+// will be built from source, as a line of the basic information (empty otherwise).
+func (d *Details) getBottleLine(pkg *models.Package) string {
+	bottle := pkg.Bottle
+	if pkg.Type != models.PackageTypeFormula || bottle.Platform == "" {
+		return ""
+	}
+	if !bottle.Available() {
+		return fmt.Sprintf("[blue]• Bottle:[-] [orange]None for %s, builds from source[-]\n", bottle.Platform)
+	}
+	line := "[blue]• Bottle:[-] [green]✓[-] " + bottle.Tag
+	if bottle.Size > 0 {
+		line += fmt.Sprintf(" (%s download)", models.FormatSize(bottle.Size))
+	}
+	return line + "\n"
+}
+
 func (d *Details) getHealthInfo(pkg *models.Package) string {
-	if !pkg.Deprecated && !pkg.Disabled {
+	deprecation, ok := pkg.Deprecation()
+	if !ok {
 		return ""
 	}
 
 	separator := "[dim]────────────────────────[-]"
 
-	var title, reason, date, replacement string
-
-	if pkg.Disabled {
+	title := "[yellow::b]⚠ Package Deprecated[-]"
+	if deprecation.Disabled {
 		title = "[red::b]⚠ Package Disabled[-]"
-		if pkg.Formula != nil {
-			reason = interfaceToString(pkg.Formula.DisableReason)
-			date = interfaceToString(pkg.Formula.DisableDate)
-			replacement = interfaceToString(pkg.Formula.DisableReplacement)
-		} else if pkg.Cask != nil {
-			reason = interfaceToString(pkg.Cask.DisableReason)
-			date = interfaceToString(pkg.Cask.DisableDate)
-		}
-	} else {
-		title = "[yellow::b]⚠ Package Deprecated[-]"
-		if pkg.Formula != nil {
-			reason = interfaceToString(pkg.Formula.DeprecationReason)
-			date = interfaceToString(pkg.Formula.DeprecationDate)
-			replacement = interfaceToString(pkg.Formula.DeprecationReplacement)
-		} else if pkg.Cask != nil {
-			reason = interfaceToString(pkg.Cask.DeprecationReason)
-			date = interfaceToString(pkg.Cask.DeprecationDate)
-		}
 	}
 
 	var sb strings.Builder
 	fmt.Fprintf(&sb, "%s\n%s\n", title, separator)
 
-	if reason != "" {
-		fmt.Fprintf(&sb, "[blue]• Reason:[-] %s\n", reason)
+	if deprecation.Reason != "" {
+		fmt.Fprintf(&sb, "[blue]• Reason:[-] %s\n", deprecation.Reason)
+	}
+	if deprecation.Date != "" {
+		fmt.Fprintf(&sb, "[blue]• Since:[-] %s\n", deprecation.Date)
 	}
-	if date != "" {
-		fmt.Fprintf(&sb, "[blue]• Since:[-] %s\n", date)
+	if !deprecation.Disabled && deprecation.DisableDate != "" {
+		fmt.Fprintf(&sb, "[blue]• Disabled from:[-] %s\n", deprecation.DisableDate)
 	}
-	if replacement != "" {
-		fmt.Fprintf(&sb, "[blue]• Replacement:[-] [green]%s[-]\n", replacement)
+	if deprecation.Replacement != "" {
+		fmt.Fprintf(&sb, "[blue]• Replacement:[-] [green]%s[-] (%s)\n", deprecation.Replacement, deprecation.ReplacementType)
+	} else {
+		sb.WriteString("[blue]• Replacement:[-] [orange]None named by Homebrew[-]\n")
 	}
 
-	if pkg.Disabled {
+	switch {
+	case deprecation.Replacement != "" && pkg.LocallyInstalled:
+		sb.WriteString("\n[dim]Press m to migrate to the replacement.[-]")
+	case deprecation.Disabled:
 		sb.WriteString("\n[dim]This package can no longer be installed.[-]")
-	} else {
+	case deprecation.Replacement != "":
 		sb.WriteString("\n[dim]Consider migrating to the replacement before this package is removed.[-]")
+	default:
+		sb.WriteString("\n[dim]Look for an alternative before this package is removed.[-]")
 	}
 
 	return sb.String()
 }
 
-func interfaceToString(v interface{}) string {
-	if v == nil {
-		return ""
-	}
-	return fmt.Sprintf("%v", v)
-}
-
 func (d *Details) getPackageInstallationDetails(pkg *models.Package) string {
 	separator := "[dim]────────────────────────[-]"
 
 	if !pkg.LocallyInstalled {
-		return fmt.Sprintf("[yellow::b]Installation[-]\n%s\nNot installed", separator)
+		notInstalled := fmt.Sprintf("[yellow::b]Installation[-]\n%s\nNot installed", separator)
+		if pkg.Formula != nil && pkg.Formula.KegOnly {
+			notInstalled += fmt.Sprintf("\n[blue]• Keg-only:[-] %s", tview.Escape(pkg.Formula.KegOnlyReason.String()))
+		}
+		return notInstalled
 	}
 
 	// For formulae, show detailed installation info
@@ -227,12 +270,14 @@ func (d *Details) getPackageInstallationDetails(pkg *models.Package) string {
 				"[blue]• Path:[-] %s\n"+
 				"[blue]• Installed on request:[-] %s\n"+
 				"[blue]• Installed as dependency:[-] %s\n"+
-				"[blue]• Installed version:[-] %s",
+				"[blue]• Installed version:[-] %s\n"+
+				"%s",
 			separator,
 			packagePrefix,
 			installedOnRequest,
 			installedAsDependency,
 			pkg.Formula.Installed[0].Version,
+			d.getLinkInfo(pkg.Formula),
 		)
 	}
 
@@ -255,12 +300,58 @@ func (d *Details) getPackageInstallationDetails(pkg *models.Package) string {
 	return fmt.Sprintf("[yellow::b]Installation[-]\n%s\nInstalled", separator)
 }
 
+// getLinkInfo describes whether the formula is linked into the prefix, and why not.
+func (d *Details) getLinkInfo(f *models.Formula) string {
+	var sb strings.Builder
+	switch {
+	case f.Linked():
+		fmt.Fprintf(&sb, "[blue]• Linked:[-] [green]Yes[-] (%s)", f.LinkedKeg)
+	case f.KegOnly:
+		sb.WriteString("[blue]• Linked:[-] No (keg-only, not on PATH)")
+	default:
+		sb.WriteString("[blue]• Linked:[-] [orange]No[-], commands are not on PATH")
+	}
+	if f.KegOnly {
+		fmt.Fprintf(&sb, "\n[blue]• Keg-only:[-] %s", tview.Escape(f.KegOnlyReason.String()))
+	}
+	if f.Linked() {
+		sb.WriteString("\n[dim]Press U to unlink[-]")
+	} else {
+		sb.WriteString("\n[dim]Press K to link[-]")
+	}
+	return sb.String()
+}
+
+func (d *Details) getCaveatsInfo(pkg *models.Package) string {
+	separator := "[dim]────────────────────────[-]"
+	hint := ""
+	if pkg.LocallyInstalled {
+		hint = "\n[dim]Press C to show them in a window[-]"
+	}
+	return fmt.Sprintf("[yellow::b]Caveats[-]\n%s\n%s%s", separator, tview.Escape(pkg.Caveats), hint)
+}
+
+func (d *Details) getDiskUsageInfo(usage models.DiskUsage) string {
+	separator := "[dim]────────────────────────[-]"
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[yellow::b]Disk Usage[-]\n%s\n[blue]• Total:[-] %s", separator, models.FormatSize(usage.Bytes))
+	if len(usage.Versions) > 1 {
+		for _, keg := range usage.Versions {
+			fmt.Fprintf(&sb, "\n  %-16s %10s", tview.Escape(keg.Version), models.FormatSize(keg.Bytes))
+		}
+	}
+	return sb.String()
+}
+
 func (d *Details) getDependenciesInfo(info *models.Formula) string {
 	separator := "[dim]────────────────────────[-]"
 	title := fmt.Sprintf("[yellow::b]Dependencies[-]\n%s\n", separator)
 
+	hint := "\n[dim]Press d for the full tree and dependents[-]"
+
 	if len(info.Dependencies) == 0 {
-		return title + "No dependencies"
+		return title + "No dependencies" + hint
 	}
 
 	// Format dependencies in multiple columns or with separators
@@ -276,20 +367,40 @@ func (d *Details) getDependenciesInfo(info *models.Formula) string {
 		}
 	}
 
-	return title + deps
+	return title + deps + hint
 }
 
 func (d *Details) getAnalyticsInfo(pkg *models.Package) string {
 	separator := "[dim]────────────────────────[-]"
 	p := message.NewPrinter(language.English)
 
+	if pkg.Type == models.PackageTypeCask && d.metric.CaskCategory() == "" {
+		return fmt.Sprintf("[yellow::b]Analytics[-] · %s\n%s\n[dim]Not tracked for casks[-]", d.metric, separator)
+	}
+
+	// One column per window, the selected one highlighted
+	header, ranks, counts := fmt.Sprintf("%-7s", ""), fmt.Sprintf("%-7s", "Rank"), fmt.Sprintf("%-7s", "Count")
+	for _, period := range models.AnalyticsPeriods {
+		stats := pkg.Analytics[period]
+		rank, count := "-", "-"
+		if stats.Rank > 0 {
+			rank, count = p.Sprintf("#%d", stats.Rank), p.Sprintf("%d", stats.Count)
+		}
+
+		style, reset := "", ""
+		if period == d.period {
+			style, reset = "[::b]", "[::-]"
+		}
+		header += fmt.Sprintf("%s%11s%s", style, period, reset)
+		ranks += fmt.Sprintf("%s%11s%s", style, rank, reset)
+		counts += fmt.Sprintf("%s%11s%s", style, count, reset)
+	}
+
 	return fmt.Sprintf(
-		"[yellow::b]Analytics[-]\n%s\n"+
-			"[blue]• 90d Global Rank:[-] %s\n"+
-			"[blue]• 90d Downloads:[-] %s",
-		separator,
-		p.Sprintf("%d", pkg.Analytics90dRank),
-		p.Sprintf("%d", pkg.Analytics90dDownloads),
+		"[yellow::b]Analytics[-] · %s\n%s\n"+
+			"[blue]%s[-]\n%s\n%s",
+		d.metric, separator,
+		header, ranks, counts,
 	)
 }
 
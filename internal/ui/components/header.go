@@ -11,6 +11,10 @@ import (
 type Header struct {
 	view  *tview.TextView
 	theme *theme.Theme
+
+	name, version, brewVersion string
+	diskUsage                  string
+	refreshing                 bool
 }
 
 func NewHeader(theme *theme.Theme) *Header {
@@ -25,7 +29,31 @@ func NewHeader(theme *theme.Theme) *Header {
 }
 
 func (h *Header) Update(name, version, brewVersion string) {
-	h.view.SetText(fmt.Sprintf(" %s %s - %s", name, version, brewVersion))
+	h.name, h.version, h.brewVersion = name, version, brewVersion
+	h.render()
+}
+
+// SetRefreshing toggles the background refresh indicator next to the version info.
+func (h *Header) SetRefreshing(refreshing bool) {
+	h.refreshing = refreshing
+	h.render()
+}
+
+// SetDiskUsage sets the total installed size shown after the version info.
+func (h *Header) SetDiskUsage(total string) {
+	h.diskUsage = total
+	h.render()
+}
+
+func (h *Header) render() {
+	text := fmt.Sprintf(" %s %s - %s", h.name, h.version, h.brewVersion)
+	if h.diskUsage != "" {
+		text += fmt.Sprintf(" · %s installed", h.diskUsage)
+	}
+	if h.refreshing {
+		text += " [yellow]⟳ refreshing…[-]"
+	}
+	h.view.SetText(text)
 }
 
 func (h *Header) View() *tview.TextView {
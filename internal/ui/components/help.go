@@ -55,12 +55,9 @@ func (h *HelpScreen) Build(mainContent tview.Primitive) *tview.Pages {
 		SetTitle(" Help ").
 		SetTitleAlign(tview.AlignCenter)
 
-	// Calculate box dimensions
-	boxHeight := 23
-	boxWidth := 55
-	if h.isBrewfile {
-		boxHeight = 27 // Extra space for Brewfile section
-	}
+	// Size the box to its content: text lines plus frame padding and border
+	boxHeight := strings.Count(content, "\n") + 1 + 4
+	boxWidth := 60
 
 	// Center the frame in a flex layout
 	centered := tview.NewFlex().
@@ -89,6 +86,10 @@ func (h *HelpScreen) buildHelpContent() string {
 	sb.WriteString(h.formatKey("↑/↓, j/k", "Navigate list"))
 	sb.WriteString(h.formatKey("/", "Focus search"))
 	sb.WriteString(h.formatKey("Esc", "Back to table"))
+	sb.WriteString(h.formatKey("n", "What's new in Homebrew"))
+	sb.WriteString(h.formatKey("d", "Dependency tree and dependents"))
+	sb.WriteString(h.formatKey("C", "Show caveats again"))
+	sb.WriteString(h.formatKey("b", "Background services (brew services)"))
 	sb.WriteString(h.formatKey("q", "Quit"))
 	sb.WriteString("\n")
 
@@ -99,17 +100,49 @@ func (h *HelpScreen) buildHelpContent() string {
 	sb.WriteString(h.formatKey("l", "Toggle leaves"))
 	sb.WriteString(h.formatKey("c", "Toggle casks"))
 	sb.WriteString(h.formatKey("F", "Toggle formulae"))
+	sb.WriteString(h.formatKey("t", "Toggle trending"))
+	sb.WriteString(h.formatKey("T", "Trend period (week/month)"))
+	sb.WriteString(h.formatKey("O", "Toggle orphaned dependencies"))
+	sb.WriteString(h.formatKey("P", "Toggle pinned"))
+	sb.WriteString(h.formatKey("B", "Toggle formulae with a service"))
+	sb.WriteString(h.formatKey("w", "Toggle installed but unlinked"))
+	sb.WriteString(h.formatKey("N", "Toggle no bottle on this platform"))
+	sb.WriteString(h.formatKey("D", "Toggle deprecated and disabled"))
 	sb.WriteString(h.formatKey("s", "Cycle sort mode"))
+	sb.WriteString(h.formatKey("S", "Reverse sort"))
+	sb.WriteString(h.formatKey("a", "Analytics window (30d/90d/365d)"))
+	sb.WriteString(h.formatKey("A", "Analytics metric"))
+	sb.WriteString(h.formatKey("Z", "Toggle size column"))
+	sb.WriteString(h.formatKey("L", "Toggle license column"))
+	sb.WriteString("\n")
+
+	// Search query section
+	sb.WriteString(h.formatSection("SEARCH QUERIES"))
+	sb.WriteString(h.formatKey("type:cask", "formula, cask, flatpak, mas"))
+	sb.WriteString(h.formatKey("outdated", "Also installed, leaf, pinned, linked, ..."))
+	sb.WriteString(h.formatKey("tap:, dep:", "Also license:, name:, desc:"))
+	sb.WriteString(h.formatKey("requires:GPL*", "License that can't be avoided"))
+	sb.WriteString(h.formatKey("downloads:>1k", "Also rank:<100, 100..5k"))
+	sb.WriteString(h.formatKey("-x, \"a b\"", "Exclude, exact phrase"))
 	sb.WriteString("\n")
 
 	// Actions section
 	sb.WriteString(h.formatSection("ACTIONS"))
 	sb.WriteString(h.formatKey("i", "Install selected"))
+	sb.WriteString(h.formatKey("I", "Install with options (version, HEAD...)"))
 	sb.WriteString(h.formatKey("u", "Update selected"))
 	sb.WriteString(h.formatKey("r", "Remove selected"))
+	sb.WriteString(h.formatKey("p", "Pin/unpin selected formula"))
+	sb.WriteString(h.formatKey("K / U", "Link/unlink selected formula"))
+	sb.WriteString(h.formatKey("V", "Switch to another formula version"))
+	sb.WriteString(h.formatKey("m", "Migrate to the replacement"))
+	sb.WriteString(h.formatKey("X", "Autoremove unneeded dependencies"))
 	sb.WriteString(h.formatKey("v", "Vulnerability scan"))
-	sb.WriteString(h.formatKey("e", "Export Brewfile"))
+	sb.WriteString(h.formatKey("e", "Export Brewfile or SBOM"))
 	sb.WriteString(h.formatKey("Ctrl+U", "Update all"))
+	sb.WriteString(h.formatKey("Space", "Mark row; i/r/u then queue marked rows"))
+	sb.WriteString(h.formatKey("*", "Mark all shown rows, again to clear"))
+	sb.WriteString(h.formatKey("J", "Jobs: logs, retry failed (r)"))
 
 	// Brewfile section (only if in Brewfile mode)
 	if h.isBrewfile {
@@ -132,7 +165,7 @@ func (h *HelpScreen) formatSection(title string) string {
 
 // formatKey formats a key-description pair
 func (h *HelpScreen) formatKey(key, description string) string {
-	return fmt.Sprintf("  [%s]%-12s[-] %s\n", h.getColorTag(h.theme.WarningColor), key, description)
+	return fmt.Sprintf("  [%s]%-14s[-] %s\n", h.getColorTag(h.theme.WarningColor), key, description)
 }
 
 // getColorTag converts a tcell.Color to a tview color tag
@@ -0,0 +1,119 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// InstallOptionsDialog is a form overlay to choose how a formula or cask is installed:
+// which version of a formula, --HEAD, --build-from-source, or cask flags
+type InstallOptionsDialog struct {
+	pages *tview.Pages
+	form  *tview.Form
+	theme *theme.Theme
+}
+
+// NewInstallOptionsDialog creates a new install options dialog component
+func NewInstallOptionsDialog(theme *theme.Theme) *InstallOptionsDialog {
+	return &InstallOptionsDialog{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the form, which holds the focus while the dialog is open
+func (d *InstallOptionsDialog) View() *tview.Form {
+	return d.form
+}
+
+// Build creates the dialog as an overlay on top of the main content, prefilled with opts.
+// versions lists the formulae that can be installed instead, the package itself first;
+// hasHead tells whether one of them can be installed with --HEAD.
+// onInstall receives the chosen formula name and options.
+func (d *InstallOptionsDialog) Build(
+	mainContent tview.Primitive,
+	pkg models.Package,
+	versions []string,
+	hasHead func(version string) bool,
+	opts models.InstallOptions,
+	onInstall func(version string, opts models.InstallOptions),
+	onCancel func(),
+) *tview.Pages {
+	d.form = tview.NewForm()
+	d.form.SetBackgroundColor(d.theme.ModalBgColor)
+	d.form.SetFieldBackgroundColor(d.theme.DefaultBgColor)
+	d.form.SetFieldTextColor(d.theme.DefaultTextColor)
+	d.form.SetLabelColor(d.theme.WarningColor)
+	d.form.SetButtonBackgroundColor(d.theme.ButtonBgColor)
+	d.form.SetButtonTextColor(d.theme.ButtonTextColor)
+	d.form.SetButtonActivatedStyle(tcell.StyleDefault.Reverse(true))
+
+	version := pkg.Name
+	if pkg.Type == models.PackageTypeCask {
+		d.form.AddCheckbox("--no-quarantine", opts.NoQuarantine, func(checked bool) { opts.NoQuarantine = checked })
+		d.form.AddCheckbox("--force", opts.Force, func(checked bool) { opts.Force = checked })
+		d.form.AddInputField("--appdir", opts.AppDir, 32, nil, func(text string) { opts.AppDir = strings.TrimSpace(text) })
+		d.form.GetFormItem(2).(*tview.InputField).SetPlaceholder("/Applications")
+	} else {
+		first := 0 // Index of the first checkbox, after the version dropdown
+		addCheckboxes := func() {
+			for d.form.GetFormItemCount() > first {
+				d.form.RemoveFormItem(first)
+			}
+			// Only offer --HEAD for the chosen version when it has a development URL
+			if hasHead(version) {
+				d.form.AddCheckbox("--HEAD", opts.HEAD, func(checked bool) { opts.HEAD = checked })
+			} else {
+				opts.HEAD = false
+			}
+			d.form.AddCheckbox("--build-from-source", opts.BuildFromSource, func(checked bool) { opts.BuildFromSource = checked })
+		}
+		if len(versions) > 1 {
+			first = 1
+			d.form.AddDropDown("Version", versions, 0, func(option string, _ int) {
+				if option == version {
+					return
+				}
+				version = option
+				addCheckboxes()
+			})
+		}
+		addCheckboxes()
+	}
+	d.form.AddButton("Install", func() { onInstall(version, opts) })
+	d.form.AddButton("Cancel", onCancel)
+	d.form.SetCancelFunc(onCancel)
+
+	frame := tview.NewFrame(d.form).
+		SetBorders(0, 1, 1, 1, 1, 1).
+		AddText("Tab/↑/↓ move · Space toggle · Enter confirm · Esc cancel", false, tview.AlignCenter, d.theme.LegendColor)
+	frame.SetBackgroundColor(d.theme.ModalBgColor)
+	frame.SetBorderColor(d.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Install %s %s ", typeTag(pkg.Type), tview.Escape(pkg.Label()))).
+		SetTitleAlign(tview.AlignCenter)
+
+	// Each form item takes two lines, plus the buttons, footer, padding and border
+	boxHeight := d.form.GetFormItemCount()*2 + 1 + 6
+	boxWidth := 64
+
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, boxHeight, 0, true).
+			AddItem(nil, 0, 1, false),
+			boxWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	d.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("installOptions", centered, true, true)
+
+	return d.pages
+}
@@ -0,0 +1,164 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// JobsPanel displays an overlay listing the queued, running and finished background
+// jobs, with the output of the selected one
+type JobsPanel struct {
+	pages    *tview.Pages
+	table    *tview.Table
+	log      *tview.TextView
+	theme    *theme.Theme
+	jobs     []models.Job
+	onSelect func(job models.Job)
+}
+
+// NewJobsPanel creates a new jobs panel component
+func NewJobsPanel(theme *theme.Theme) *JobsPanel {
+	return &JobsPanel{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the table listing the jobs, which handles navigation
+func (p *JobsPanel) View() *tview.Table {
+	return p.table
+}
+
+// Build creates the jobs panel as an overlay on top of the main content.
+// onSelect is called when another job is selected, to show its output with SetLog.
+func (p *JobsPanel) Build(mainContent tview.Primitive, onSelect func(job models.Job)) *tview.Pages {
+	p.jobs = nil
+	p.onSelect = onSelect
+	p.table = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	p.table.SetBackgroundColor(p.theme.ModalBgColor)
+	p.table.SetSelectionChangedFunc(func(row, column int) {
+		if job, ok := p.Selected(); ok && p.onSelect != nil {
+			p.onSelect(job)
+		}
+	})
+
+	p.log = tview.NewTextView().
+		SetScrollable(true).
+		SetWrap(true)
+	p.log.SetBackgroundColor(p.theme.ModalBgColor)
+	p.log.SetBorder(true).
+		SetTitle(" Output ").
+		SetBorderColor(p.theme.BorderColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.table, 0, 1, true).
+		AddItem(p.log, 0, 2, false)
+
+	frame := tview.NewFrame(content).
+		SetBorders(1, 1, 1, 1, 2, 2).
+		AddText("r retry failed · c clear succeeded · Esc close", false, tview.AlignCenter, p.theme.LegendColor)
+	frame.SetBackgroundColor(p.theme.ModalBgColor)
+	frame.SetBorderColor(p.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" Jobs ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Leave a margin around the box so the main view stays visible behind it
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 8, true).
+			AddItem(nil, 0, 1, false),
+			0, 6, true).
+		AddItem(nil, 0, 1, false)
+
+	p.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("jobs", centered, true, true)
+
+	return p.pages
+}
+
+// SetJobs fills the table, keeping the selected job when it is still listed,
+// otherwise selecting the running job or the last one
+func (p *JobsPanel) SetJobs(jobs []models.Job) {
+	selected, hadSelection := p.Selected()
+	p.jobs = jobs
+	p.table.Clear()
+
+	for i, header := range []string{"#", "Action", "Package", "Status", "Time"} {
+		p.table.SetCell(0, i, tview.NewTableCell(header).SetTextColor(p.theme.TableHeaderColor).SetSelectable(false))
+	}
+	p.table.GetCell(0, 2).SetExpansion(1)
+	if len(jobs) == 0 {
+		p.table.SetCell(1, 0, tview.NewTableCell("No jobs: mark packages with space, then press i, r or u to queue them").
+			SetTextColor(p.theme.LegendColor).SetSelectable(false))
+		p.log.Clear()
+		return
+	}
+
+	row := len(jobs)
+	for i, job := range jobs {
+		status := string(job.Status)
+		if job.Status == models.JobFailed && job.Error != "" {
+			status += ": " + job.Error
+		}
+		elapsed := ""
+		if d := job.Duration(); d > 0 {
+			elapsed = d.Round(time.Second).String()
+		}
+		p.table.SetCell(i+1, 0, tview.NewTableCell(fmt.Sprintf("%d", job.ID)).SetAlign(tview.AlignRight))
+		p.table.SetCell(i+1, 1, tview.NewTableCell(string(job.Action)))
+		p.table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(job.Package.Label())))
+		p.table.SetCell(i+1, 3, tview.NewTableCell(tview.Escape(status)).SetTextColor(p.statusColor(job.Status)).SetMaxWidth(40))
+		p.table.SetCell(i+1, 4, tview.NewTableCell(elapsed).SetAlign(tview.AlignRight))
+		switch {
+		case hadSelection && job.ID == selected.ID:
+			row = i + 1
+		case !hadSelection && job.Status == models.JobRunning:
+			row = i + 1
+		}
+	}
+	p.table.Select(row, 0)
+}
+
+// SetLog shows the output of the selected job, scrolled to its end
+func (p *JobsPanel) SetLog(output string) {
+	p.log.SetText(output)
+	p.log.ScrollToEnd()
+}
+
+// Selected returns the selected job
+func (p *JobsPanel) Selected() (models.Job, bool) {
+	if p.table == nil {
+		return models.Job{}, false
+	}
+	row, _ := p.table.GetSelection()
+	if row <= 0 || row-1 >= len(p.jobs) {
+		return models.Job{}, false
+	}
+	return p.jobs[row-1], true
+}
+
+// statusColor returns the color of a job status
+func (p *JobsPanel) statusColor(status models.JobStatus) tcell.Color {
+	switch status {
+	case models.JobSucceeded:
+		return p.theme.SuccessColor
+	case models.JobRunning:
+		return p.theme.WarningColor
+	case models.JobFailed:
+		return p.theme.ErrorColor
+	default:
+		return p.theme.LegendColor
+	}
+}
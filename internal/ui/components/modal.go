@@ -10,6 +10,7 @@ import (
 type Modal struct {
 	view  *tview.Modal
 	theme *theme.Theme
+	text  string
 }
 
 func NewModal(theme *theme.Theme) *Modal {
@@ -38,19 +39,38 @@ func (m *Modal) View() *tview.Modal {
 }
 
 func (m *Modal) Build(text string, confirmFunc func(), cancelFunc func()) *tview.Modal {
+	return m.BuildChoice(text, []string{"Confirm", "Cancel"}, []func(){confirmFunc, cancelFunc})
+}
+
+// BuildChoice builds a modal with one button per label, calling the matching handler
+// when a button is pressed.
+func (m *Modal) BuildChoice(text string, labels []string, handlers []func()) *tview.Modal {
+	buttons := make([]string, len(labels))
+	for i, label := range labels {
+		buttons[i] = "  " + label + "  " // Add padding to button labels for better visual appearance
+	}
+
+	m.text = text
 	m.view.ClearButtons()
 	m.view.
 		SetText(text).
-		// Add padding to button labels with spaces for better visual appearance
-		AddButtons([]string{"  Confirm  ", "  Cancel  "}).
+		AddButtons(buttons).
 		SetDoneFunc(func(buttonIndex int, _ string) {
-			switch buttonIndex {
-			case 0:
-				confirmFunc()
-			case 1:
-				cancelFunc()
+			if buttonIndex >= 0 && buttonIndex < len(handlers) {
+				handlers[buttonIndex]()
 			}
 		})
 
 	return m.view
 }
+
+// ReplaceText updates the text of the modal if it still shows old, e.g. once details
+// loaded in the background are known. It reports whether the text was replaced.
+func (m *Modal) ReplaceText(old, text string) bool {
+	if m.text != old {
+		return false
+	}
+	m.text = text
+	m.view.SetText(text)
+	return true
+}
@@ -0,0 +1,103 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// NewsScreen displays a scrollable overlay of the changes in the Homebrew catalogue
+type NewsScreen struct {
+	pages    *tview.Pages
+	textView *tview.TextView
+	theme    *theme.Theme
+}
+
+// NewNewsScreen creates a new news screen component
+func NewNewsScreen(theme *theme.Theme) *NewsScreen {
+	return &NewsScreen{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the text view holding the news, which handles scrolling
+func (n *NewsScreen) View() *tview.TextView {
+	return n.textView
+}
+
+// Build creates the news screen as an overlay on top of the main content
+func (n *NewsScreen) Build(mainContent tview.Primitive, items []models.NewsItem) *tview.Pages {
+	n.textView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(n.buildNewsContent(items))
+
+	n.textView.SetBackgroundColor(n.theme.ModalBgColor)
+	n.textView.SetTextColor(n.theme.DefaultTextColor)
+
+	frame := tview.NewFrame(n.textView).
+		SetBorders(1, 1, 1, 1, 2, 2).
+		AddText("↑/↓ scroll · Esc close", false, tview.AlignCenter, n.theme.LegendColor)
+	frame.SetBackgroundColor(n.theme.ModalBgColor)
+	frame.SetBorderColor(n.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" What's New in Homebrew ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Leave a margin around the box so the main view stays visible behind it
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 8, true).
+			AddItem(nil, 0, 1, false),
+			0, 6, true).
+		AddItem(nil, 0, 1, false)
+
+	n.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("news", centered, true, true)
+
+	return n.pages
+}
+
+// buildNewsContent lists the changes grouped by the catalogue refresh that found them
+func (n *NewsScreen) buildNewsContent(items []models.NewsItem) string {
+	if len(items) == 0 {
+		return "No changes recorded yet.\n\nNew, removed, deprecated and disabled packages are listed here\nafter the Homebrew catalogue is refreshed (at most once a day)."
+	}
+
+	kindColors := map[models.NewsKind]tcell.Color{
+		models.NewsAdded:      n.theme.SuccessColor,
+		models.NewsRemoved:    n.theme.ErrorColor,
+		models.NewsDeprecated: n.theme.WarningColor,
+		models.NewsDisabled:   n.theme.ErrorColor,
+	}
+
+	var sb strings.Builder
+	var current time.Time
+	for _, item := range items {
+		if !item.Time.Equal(current) {
+			if !current.IsZero() {
+				sb.WriteString("\n")
+			}
+			current = item.Time
+			fmt.Fprintf(&sb, "[::b]%s[::-]\n", item.Time.Local().Format("Mon 2 Jan 2006, 15:04"))
+		}
+
+		fmt.Fprintf(&sb, "  [#%06x]%-10s[-] %s %s",
+			kindColors[item.Kind].Hex(), item.Kind, typeTag(item.Type), tview.Escape(item.Name))
+		if item.Description != "" {
+			fmt.Fprintf(&sb, " [::d]%s[::-]", tview.Escape(item.Description))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
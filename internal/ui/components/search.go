@@ -41,6 +41,11 @@ func (s *Search) UpdateCounter(total, filtered int) {
 	s.counter.SetText(fmt.Sprintf("Total: %d | Filtered: %d", total, filtered))
 }
 
+// SetError shows a query error in place of the counter until the next update.
+func (s *Search) SetError(msg string) {
+	s.counter.SetText("[red]" + tview.Escape(msg) + "[-]")
+}
+
 func (s *Search) Field() *tview.InputField {
 	return s.field
 }
@@ -0,0 +1,138 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ServicesPanel displays an overlay listing the background services of installed
+// formulae (brew services) with their status
+type ServicesPanel struct {
+	pages    *tview.Pages
+	table    *tview.Table
+	theme    *theme.Theme
+	services []models.ServiceStatus
+}
+
+// NewServicesPanel creates a new services panel component
+func NewServicesPanel(theme *theme.Theme) *ServicesPanel {
+	return &ServicesPanel{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the table listing the services, which handles navigation
+func (p *ServicesPanel) View() *tview.Table {
+	return p.table
+}
+
+// Build creates the services panel as an overlay on top of the main content.
+// The services are filled in with SetServices once loaded.
+func (p *ServicesPanel) Build(mainContent tview.Primitive) *tview.Pages {
+	p.services = nil
+	p.table = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	p.table.SetBackgroundColor(p.theme.ModalBgColor)
+	p.SetMessage("Loading services…")
+
+	frame := tview.NewFrame(p.table).
+		SetBorders(1, 1, 1, 1, 2, 2).
+		AddText("s start · x stop · r restart · o run (not at login) · Esc close", false, tview.AlignCenter, p.theme.LegendColor)
+	frame.SetBackgroundColor(p.theme.ModalBgColor)
+	frame.SetBorderColor(p.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" Services ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Leave a margin around the box so the main view stays visible behind it
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 6, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	p.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("services", centered, true, true)
+
+	return p.pages
+}
+
+// SetServices fills the table, keeping the selected service when it is still listed
+func (p *ServicesPanel) SetServices(services []models.ServiceStatus) {
+	selected, _ := p.Selected()
+	p.services = services
+	p.table.Clear()
+
+	for i, header := range []string{"Service", "Status", "User", "PID", "Exit Code"} {
+		p.table.SetCell(0, i, tview.NewTableCell(header).SetTextColor(p.theme.TableHeaderColor).SetSelectable(false))
+	}
+	p.table.GetCell(0, 0).SetExpansion(1)
+	if len(services) == 0 {
+		p.SetMessage("No installed formula defines a service")
+		return
+	}
+
+	row := 1
+	for i, service := range services {
+		pid, exitCode := "", ""
+		if service.PID > 0 {
+			pid = fmt.Sprintf("%d", service.PID)
+		}
+		if service.Loaded || service.ExitCode != 0 {
+			exitCode = fmt.Sprintf("%d", service.ExitCode)
+		}
+		p.table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(service.Name)))
+		p.table.SetCell(i+1, 1, tview.NewTableCell(service.Status).SetTextColor(p.statusColor(service.Status)))
+		p.table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(service.User)))
+		p.table.SetCell(i+1, 3, tview.NewTableCell(pid).SetAlign(tview.AlignRight))
+		p.table.SetCell(i+1, 4, tview.NewTableCell(exitCode).SetAlign(tview.AlignRight))
+		if service.Name == selected {
+			row = i + 1
+		}
+	}
+	p.table.Select(row, 0)
+}
+
+// SetMessage replaces the table with a message, e.g. while loading or on error
+func (p *ServicesPanel) SetMessage(message string) {
+	p.services = nil
+	p.table.Clear()
+	p.table.SetCell(0, 0, tview.NewTableCell(tview.Escape(message)).SetTextColor(p.theme.LegendColor).SetSelectable(false))
+}
+
+// Selected returns the name of the selected service
+func (p *ServicesPanel) Selected() (string, bool) {
+	if p.table == nil {
+		return "", false
+	}
+	row, _ := p.table.GetSelection()
+	if row <= 0 || row-1 >= len(p.services) {
+		return "", false
+	}
+	return p.services[row-1].Name, true
+}
+
+// statusColor returns the color of a service status
+func (p *ServicesPanel) statusColor(status string) tcell.Color {
+	switch status {
+	case "started":
+		return p.theme.SuccessColor
+	case "scheduled":
+		return p.theme.WarningColor
+	case "error":
+		return p.theme.ErrorColor
+	default:
+		return p.theme.LegendColor
+	}
+}
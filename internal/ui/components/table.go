@@ -31,6 +31,24 @@ func (t *Table) SetSelectionHandler(handler func(row, column int)) {
 	t.view.SetSelectionChangedFunc(handler)
 }
 
+// SetHeaderClickHandler calls handler with the column index when a header cell is clicked.
+func (t *Table) SetHeaderClickHandler(handler func(column int)) {
+	t.view.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftClick {
+			return action, event
+		}
+		x, y := event.Position()
+		if !t.view.InRect(x, y) {
+			return action, event
+		}
+		if row, column := t.view.CellAt(x, y); row == 0 && column >= 0 {
+			handler(column)
+			return tview.MouseConsumed, nil
+		}
+		return action, event
+	})
+}
+
 func (t *Table) View() *tview.Table {
 	return t.view
 }
@@ -0,0 +1,110 @@
+package components
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// VersionSwitchDialog is a form overlay to move from one version of a formula to another
+type VersionSwitchDialog struct {
+	pages *tview.Pages
+	form  *tview.Form
+	theme *theme.Theme
+}
+
+// NewVersionSwitchDialog creates a new version switch dialog component
+func NewVersionSwitchDialog(theme *theme.Theme) *VersionSwitchDialog {
+	return &VersionSwitchDialog{
+		pages: tview.NewPages(),
+		theme: theme,
+	}
+}
+
+// View returns the form, which holds the focus while the dialog is open
+func (d *VersionSwitchDialog) View() *tview.Form {
+	return d.form
+}
+
+// Build creates the dialog as an overlay on top of the main content.
+// targets are the other versions of the formula; onSwitch receives the chosen one.
+func (d *VersionSwitchDialog) Build(
+	mainContent tview.Primitive,
+	from models.Package,
+	targets []models.Package,
+	onSwitch func(sw models.VersionSwitch),
+	onCancel func(),
+) *tview.Pages {
+	sw := models.VersionSwitch{From: from, To: targets[0]}
+
+	labels := make([]string, len(targets))
+	for i, target := range targets {
+		labels[i] = versionLabel(target)
+	}
+
+	d.form = tview.NewForm()
+	d.form.SetBackgroundColor(d.theme.ModalBgColor)
+	d.form.SetFieldBackgroundColor(d.theme.DefaultBgColor)
+	d.form.SetFieldTextColor(d.theme.DefaultTextColor)
+	d.form.SetLabelColor(d.theme.WarningColor)
+	d.form.SetButtonBackgroundColor(d.theme.ButtonBgColor)
+	d.form.SetButtonTextColor(d.theme.ButtonTextColor)
+	d.form.SetButtonActivatedStyle(tcell.StyleDefault.Reverse(true))
+
+	d.form.AddDropDown("Switch to", labels, 0, func(_ string, index int) {
+		if index >= 0 {
+			sw.To = targets[index]
+		}
+	})
+	if from.Formula != nil && from.Formula.Service != nil {
+		d.form.AddCheckbox("Move the service", false, func(checked bool) { sw.MigrateService = checked })
+	}
+	d.form.AddCheckbox(fmt.Sprintf("Uninstall %s", from.Name), false, func(checked bool) { sw.RemoveOld = checked })
+	d.form.AddButton("Switch", func() { onSwitch(sw) })
+	d.form.AddButton("Cancel", onCancel)
+	d.form.SetCancelFunc(onCancel)
+
+	frame := tview.NewFrame(d.form).
+		SetBorders(0, 1, 1, 1, 1, 1).
+		AddText("Installs the version if needed, then relinks it", true, tview.AlignCenter, d.theme.LegendColor).
+		AddText("Tab/↑/↓ move · Space toggle · Enter confirm · Esc cancel", false, tview.AlignCenter, d.theme.LegendColor)
+	frame.SetBackgroundColor(d.theme.ModalBgColor)
+	frame.SetBorderColor(d.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Switch from %s ", tview.Escape(from.Name))).
+		SetTitleAlign(tview.AlignCenter)
+
+	// Each form item takes two lines, plus the buttons, header, footer, padding and border
+	boxHeight := d.form.GetFormItemCount()*2 + 1 + 7
+	boxWidth := 64
+
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, boxHeight, 0, true).
+			AddItem(nil, 0, 1, false),
+			boxWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	d.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("versionSwitch", centered, true, true)
+
+	return d.pages
+}
+
+// versionLabel names a version with its state, e.g. "python@3.12 (installed)"
+func versionLabel(pkg models.Package) string {
+	switch {
+	case pkg.Formula != nil && pkg.Formula.Linked():
+		return pkg.Name + " (linked)"
+	case pkg.LocallyInstalled:
+		return pkg.Name + " (installed)"
+	default:
+		return pkg.Name
+	}
+}
@@ -20,6 +20,13 @@ type LayoutInterface interface {
 	GetNotifier() *components.Notifier
 	GetModal() *components.Modal
 	GetHelpScreen() *components.HelpScreen
+	GetNewsScreen() *components.NewsScreen
+	GetDependencyTree() *components.DependencyTree
+	GetCaveatsScreen() *components.CaveatsScreen
+	GetServicesPanel() *components.ServicesPanel
+	GetJobsPanel() *components.JobsPanel
+	GetInstallOptionsDialog() *components.InstallOptionsDialog
+	GetVersionSwitchDialog() *components.VersionSwitchDialog
 }
 
 type Layout struct {
@@ -33,6 +40,13 @@ type Layout struct {
 	notifier    *components.Notifier
 	modal       *components.Modal
 	helpScreen  *components.HelpScreen
+	newsScreen  *components.NewsScreen
+	depTree     *components.DependencyTree
+	caveats     *components.CaveatsScreen
+	services    *components.ServicesPanel
+	jobs        *components.JobsPanel
+	installOpts *components.InstallOptionsDialog
+	switchDlg   *components.VersionSwitchDialog
 }
 
 func NewLayout(t *theme.Theme) LayoutInterface {
@@ -47,6 +61,13 @@ func NewLayout(t *theme.Theme) LayoutInterface {
 		notifier:    components.NewNotifier(t),
 		modal:       components.NewModal(t),
 		helpScreen:  components.NewHelpScreen(t),
+		newsScreen:  components.NewNewsScreen(t),
+		depTree:     components.NewDependencyTree(t),
+		caveats:     components.NewCaveatsScreen(t),
+		services:    components.NewServicesPanel(t),
+		jobs:        components.NewJobsPanel(t),
+		installOpts: components.NewInstallOptionsDialog(t),
+		switchDlg:   components.NewVersionSwitchDialog(t),
 	}
 }
 
@@ -104,12 +125,19 @@ func (l *Layout) Root() tview.Primitive {
 	return l.mainContent
 }
 
-func (l *Layout) GetHeader() *components.Header         { return l.header }
-func (l *Layout) GetSearch() *components.Search         { return l.search }
-func (l *Layout) GetTable() *components.Table           { return l.table }
-func (l *Layout) GetDetails() *components.Details       { return l.details }
-func (l *Layout) GetOutput() *components.Output         { return l.output }
-func (l *Layout) GetLegend() *components.Legend         { return l.legend }
-func (l *Layout) GetNotifier() *components.Notifier     { return l.notifier }
-func (l *Layout) GetModal() *components.Modal           { return l.modal }
-func (l *Layout) GetHelpScreen() *components.HelpScreen { return l.helpScreen }
+func (l *Layout) GetHeader() *components.Header                             { return l.header }
+func (l *Layout) GetSearch() *components.Search                             { return l.search }
+func (l *Layout) GetTable() *components.Table                               { return l.table }
+func (l *Layout) GetDetails() *components.Details                           { return l.details }
+func (l *Layout) GetOutput() *components.Output                             { return l.output }
+func (l *Layout) GetLegend() *components.Legend                             { return l.legend }
+func (l *Layout) GetNotifier() *components.Notifier                         { return l.notifier }
+func (l *Layout) GetModal() *components.Modal                               { return l.modal }
+func (l *Layout) GetHelpScreen() *components.HelpScreen                     { return l.helpScreen }
+func (l *Layout) GetNewsScreen() *components.NewsScreen                     { return l.newsScreen }
+func (l *Layout) GetDependencyTree() *components.DependencyTree             { return l.depTree }
+func (l *Layout) GetCaveatsScreen() *components.CaveatsScreen               { return l.caveats }
+func (l *Layout) GetServicesPanel() *components.ServicesPanel               { return l.services }
+func (l *Layout) GetJobsPanel() *components.JobsPanel                       { return l.jobs }
+func (l *Layout) GetInstallOptionsDialog() *components.InstallOptionsDialog { return l.installOpts }
+func (l *Layout) GetVersionSwitchDialog() *components.VersionSwitchDialog   { return l.switchDlg }